@@ -3,12 +3,23 @@ package main
 import (
 	"log"
 
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
 	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http"
 )
 
 func main() {
 	router := http.RegisterRoutes()
 
+	// Serve the metrics on their own address, if any, so they can be kept off the public listener
+	if metricsListenAddress := infrastructure.GetEnvironment().MetricsListenAddress; metricsListenAddress != "" {
+		metricsRouter := http.RegisterMetricsRoutes()
+		go func() {
+			if err := metricsRouter.Run(metricsListenAddress); err != nil {
+				log.Fatalf("Error starting metrics server: %v", err)
+			}
+		}()
+	}
+
 	if err := router.Run(":3000"); err != nil {
 		log.Fatalf("Error starting server: %v", err)
 	}
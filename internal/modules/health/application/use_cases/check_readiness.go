@@ -0,0 +1,65 @@
+package use_cases
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/dto"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// CheckReadinessUseCase is the use case for checking if the service dependencies can be used.
+type CheckReadinessUseCase struct {
+	// Checkers are the checks of each dependency
+	Checkers []definitions.HealthChecker
+	// Timeout is the max time each check can take
+	Timeout time.Duration
+}
+
+// Execute runs all the checks concurrently and reports the service as ready if all of them pass.
+// The errors are only logged, as they can reveal the hosts, paths and buckets of the dependencies.
+func (u *CheckReadinessUseCase) Execute(ctx context.Context) dto.Readiness {
+	readiness := dto.Readiness{
+		Ready:        true,
+		Dependencies: make([]dto.DependencyStatus, len(u.Checkers)),
+	}
+
+	var wg sync.WaitGroup
+	for idx, checker := range u.Checkers {
+		wg.Add(1)
+		go func(i int, checker definitions.HealthChecker) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, u.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := checker.Check(checkCtx)
+
+			if err != nil {
+				sharedUtilities.GetLogger().
+					WithError(err).
+					WithField("dependency", checker.Name()).
+					Warn("Dependency is not ready")
+			}
+
+			// Each goroutine writes its own position, so no lock is needed
+			readiness.Dependencies[i] = dto.DependencyStatus{
+				Name:     checker.Name(),
+				Ready:    err == nil,
+				Duration: time.Since(start),
+			}
+		}(idx, checker)
+	}
+	wg.Wait()
+
+	for _, dependency := range readiness.Dependencies {
+		if !dependency.Ready {
+			readiness.Ready = false
+		}
+	}
+
+	return readiness
+}
@@ -0,0 +1,17 @@
+package use_cases
+
+import (
+	pdfDefinitions "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	pdfDto "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+)
+
+// GetBrowserPoolStatsUseCase is the use case for inspecting the browser pool used to render the PDFs.
+type GetBrowserPoolStatsUseCase struct {
+	// BrowserPool is the interface for inspecting the browser pool
+	BrowserPool pdfDefinitions.BrowserPool
+}
+
+// Execute returns a snapshot of the browser pool.
+func (u *GetBrowserPoolStatsUseCase) Execute() pdfDto.BrowserPoolStats {
+	return u.BrowserPool.Stats()
+}
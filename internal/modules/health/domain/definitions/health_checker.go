@@ -0,0 +1,11 @@
+package definitions
+
+import "context"
+
+// HealthChecker is the interface for checking a dependency the service needs to handle requests
+type HealthChecker interface {
+	// Name identifies the dependency in the readiness report
+	Name() string
+	// Check returns an error if the dependency cannot be used, aborting if the context is done
+	Check(ctx context.Context) error
+}
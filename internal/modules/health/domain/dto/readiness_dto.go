@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// DependencyStatus represents the result of checking a dependency
+type DependencyStatus struct {
+	Name     string
+	Ready    bool
+	Duration time.Duration
+}
+
+// Readiness represents whether the service can handle requests, along with the state of each dependency
+type Readiness struct {
+	Ready        bool
+	Dependencies []DependencyStatus
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheckLivenessController handles the liveness probe.
+type CheckLivenessController struct{}
+
+// Handle reports the process is up and able to serve requests.
+func (controller *CheckLivenessController) Handle(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Service is alive",
+	})
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// CheckReadinessController handles the readiness probe.
+type CheckReadinessController struct {
+	UseCase use_cases.CheckReadinessUseCase
+}
+
+// Handle checks the service dependencies, replying with 503 if any of them cannot be used.
+func (controller *CheckReadinessController) Handle(c *gin.Context) {
+	readiness := controller.UseCase.Execute(c.Request.Context())
+
+	statusCode := http.StatusOK
+	message := "Service is ready"
+	if !readiness.Ready {
+		statusCode = http.StatusServiceUnavailable
+		message = "Service is not ready"
+	}
+
+	c.JSON(statusCode, gin.H{
+		"message":      message,
+		"dependencies": dependenciesToResponse(readiness.Dependencies),
+	})
+}
+
+// dependenciesToResponse converts the dependency statuses to the response payload.
+// The probe is not authenticated, so the errors are left out and only logged.
+func dependenciesToResponse(dependencies []dto.DependencyStatus) []gin.H {
+	response := make([]gin.H, len(dependencies))
+	for idx, dependency := range dependencies {
+		response[idx] = gin.H{
+			"name":                 dependency.Name,
+			"ready":                dependency.Ready,
+			"durationMilliseconds": dependency.Duration.Milliseconds(),
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/application/use_cases"
+	pdfDto "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// GetBrowserPoolStatsController handles the inspection of the browser pool.
+type GetBrowserPoolStatsController struct {
+	UseCase use_cases.GetBrowserPoolStatsUseCase
+}
+
+// Handle processes the request to get a snapshot of the browser pool.
+func (controller *GetBrowserPoolStatsController) Handle(c *gin.Context) {
+	stats := controller.UseCase.Execute()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Browser pool retrieved successfully",
+		"pool":    browserPoolStatsToResponse(stats),
+	})
+}
+
+// browserPoolStatsToResponse converts the browser pool snapshot to the response payload
+func browserPoolStatsToResponse(stats pdfDto.BrowserPoolStats) gin.H {
+	browsers := make([]gin.H, len(stats.Browsers))
+	for browserIdx, browser := range stats.Browsers {
+		pages := make([]gin.H, len(browser.Pages))
+		pagesInUse := 0
+		for pageIdx, page := range browser.Pages {
+			pages[pageIdx] = gin.H{
+				"inUse": page.InUse,
+			}
+			if page.IdleSince != nil {
+				pages[pageIdx]["idleSince"] = *page.IdleSince
+				pages[pageIdx]["closesAt"] = *page.ClosesAt
+			}
+			if page.InUse {
+				pagesInUse++
+			}
+		}
+
+		browsers[browserIdx] = gin.H{
+			"id":         browser.ID,
+			"pageCount":  len(browser.Pages),
+			"pagesInUse": pagesInUse,
+			"pages":      pages,
+		}
+	}
+
+	return gin.H{
+		"browserCount":           len(stats.Browsers),
+		"maxBrowsers":            stats.MaxBrowsers,
+		"maxPagesPerBrowser":     stats.MaxPagesPerBrowser,
+		"availablePages":         stats.AvailablePages,
+		"waitingQueueLength":     stats.WaitingQueueLength,
+		"pageIdleTimeoutSeconds": int(stats.PageIdleTimeout.Seconds()),
+		"browsers":               browsers,
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/health/infrastructure/http/controllers"
+	"github.com/PChaparro/serpentarius/internal/modules/health/infrastructure/implementations"
+	pdfImplementations "github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/implementations"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRouter handles the routing for the health module
+type HealthRouter struct{}
+
+// RegisterRootRoutes implements the RootRouterRegistry interface to register the probes outside the API prefix
+func (hr *HealthRouter) RegisterRootRoutes(r *gin.RouterGroup) {
+	// Liveness probe
+	checkLivenessController := &controllers.CheckLivenessController{}
+	r.GET("/healthz", checkLivenessController.Handle)
+
+	// Readiness probe
+	checkReadinessController := &controllers.CheckReadinessController{
+		UseCase: use_cases.CheckReadinessUseCase{
+			Checkers: getReadinessHealthCheckers(),
+			Timeout:  time.Duration(sharedInfrastructure.GetEnvironment().ReadinessCheckTimeoutSeconds) * time.Second,
+		},
+	}
+	r.GET("/readyz", checkReadinessController.Handle)
+}
+
+// RegisterRoutes implements the RouterRegistry interface to register all routes for the health module
+func (hr *HealthRouter) RegisterRoutes(r *gin.RouterGroup) {
+	// Register the admin routes, all of them require authentication
+	adminGroup := r.Group("/admin", sharedMiddlewares.AuthMiddleware())
+
+	// Inspect the browser pool
+	getBrowserPoolStatsController := &controllers.GetBrowserPoolStatsController{
+		UseCase: use_cases.GetBrowserPoolStatsUseCase{
+			BrowserPool: pdfImplementations.GetPDFGeneratorRod(),
+		},
+	}
+	adminGroup.GET("/pool", getBrowserPoolStatsController.Handle)
+}
+
+// getReadinessHealthCheckers returns the checkers of the dependencies selected in the environment.
+// Redis is only checked if any driver keeps its data in it, and the storage only if it can be checked.
+func getReadinessHealthCheckers() []definitions.HealthChecker {
+	checkers := []definitions.HealthChecker{}
+	if sharedInfrastructure.GetEnvironment().UsesRedis() {
+		checkers = append(checkers, implementations.GetRedisHealthChecker())
+	}
+	if storageHealthChecker := getStorageHealthChecker(); storageHealthChecker != nil {
+		checkers = append(checkers, storageHealthChecker)
+	}
+
+	return append(checkers, implementations.GetChromiumHealthChecker())
+}
+
+// getStorageHealthChecker returns the checker of the storage selected in the environment.
+// S3 and Cloud Storage are only checked through a configured bucket, so nil is returned without one.
+func getStorageHealthChecker() definitions.HealthChecker {
+	env := sharedInfrastructure.GetEnvironment()
+
+	switch env.StorageDriver {
+	case sharedInfrastructure.STORAGE_DRIVER_LOCAL:
+		return implementations.GetLocalStorageHealthChecker()
+	case sharedInfrastructure.STORAGE_DRIVER_GCS:
+		if env.HealthCheckGcsBucket == "" {
+			return nil
+		}
+		return implementations.GetGCSHealthChecker()
+	case sharedInfrastructure.STORAGE_DRIVER_AZURE:
+		return implementations.GetAzureBlobHealthChecker()
+	default:
+		if env.HealthCheckS3Bucket == "" {
+			return nil
+		}
+		return implementations.GetS3HealthChecker()
+	}
+}
@@ -0,0 +1,48 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+)
+
+// AzureBlobHealthChecker implements the HealthChecker interface by listing the containers of the storage account.
+// As the container is chosen on each request, fetching a single container is enough to check the endpoint and credentials.
+type AzureBlobHealthChecker struct {
+	client *azblob.Client
+}
+
+var (
+	azureBlobHealthChecker     *AzureBlobHealthChecker
+	azureBlobHealthCheckerOnce sync.Once
+)
+
+// GetAzureBlobHealthChecker returns a singleton instance of AzureBlobHealthChecker
+func GetAzureBlobHealthChecker() definitions.HealthChecker {
+	azureBlobHealthCheckerOnce.Do(func() {
+		azureBlobHealthChecker = &AzureBlobHealthChecker{
+			client: sharedImplementations.GetAzureBlobClient(),
+		}
+	})
+
+	return azureBlobHealthChecker
+}
+
+// Name returns the name of the dependency
+func (a *AzureBlobHealthChecker) Name() string {
+	return "azure_blob"
+}
+
+// Check fetches the first page of containers, holding at most one container
+func (a *AzureBlobHealthChecker) Check(ctx context.Context) error {
+	maxResults := int32(1)
+	pager := a.client.NewListContainersPager(&azblob.ListContainersOptions{
+		MaxResults: &maxResults,
+	})
+
+	_, err := pager.NextPage(ctx)
+	return err
+}
@@ -0,0 +1,49 @@
+package implementations
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// ChromiumHealthChecker implements the HealthChecker interface by running the configured Chromium binary.
+// Printing its version is enough to know new browsers can be launched, without the cost of starting one.
+type ChromiumHealthChecker struct {
+	binaryPath string
+}
+
+var (
+	chromiumHealthChecker     *ChromiumHealthChecker
+	chromiumHealthCheckerOnce sync.Once
+)
+
+// GetChromiumHealthChecker returns a singleton instance of ChromiumHealthChecker
+func GetChromiumHealthChecker() definitions.HealthChecker {
+	chromiumHealthCheckerOnce.Do(func() {
+		chromiumHealthChecker = &ChromiumHealthChecker{
+			binaryPath: sharedInfrastructure.GetEnvironment().ChromiumBinaryPath,
+		}
+	})
+
+	return chromiumHealthChecker
+}
+
+// Name returns the name of the dependency
+func (c *ChromiumHealthChecker) Name() string {
+	return "chromium"
+}
+
+// Check runs the Chromium binary to print its version
+func (c *ChromiumHealthChecker) Check(ctx context.Context) error {
+	output, err := exec.CommandContext(ctx, c.binaryPath, "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error running chromium binary: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package implementations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+)
+
+// GCSHealthChecker implements the HealthChecker interface by getting the metadata of a Cloud Storage bucket.
+// Unlike S3, listing the buckets requires a project, so the checker is not registered if no bucket is configured.
+type GCSHealthChecker struct {
+	storage *sharedImplementations.GCSCloudStorage
+	bucket  string // Bucket whose metadata is requested
+}
+
+var (
+	gcsHealthChecker     *GCSHealthChecker
+	gcsHealthCheckerOnce sync.Once
+)
+
+// GetGCSHealthChecker returns a singleton instance of GCSHealthChecker
+func GetGCSHealthChecker() definitions.HealthChecker {
+	gcsHealthCheckerOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		gcsHealthChecker = &GCSHealthChecker{
+			storage: sharedImplementations.NewGCSCloudStorage(sharedImplementations.GetGCSClient(), nil),
+			bucket:  env.HealthCheckGcsBucket,
+		}
+	})
+
+	return gcsHealthChecker
+}
+
+// Name returns the name of the dependency
+func (g *GCSHealthChecker) Name() string {
+	return "gcs"
+}
+
+// Check gets the metadata of the configured bucket
+func (g *GCSHealthChecker) Check(ctx context.Context) error {
+	exists, err := g.storage.BucketExists(ctx, g.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", g.bucket)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package implementations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// LocalStorageHealthChecker implements the HealthChecker interface by writing to the local storage root.
+// Creating and removing an empty file checks both the directory exists and the process can write to it.
+type LocalStorageHealthChecker struct {
+	rootPath string
+}
+
+var (
+	localStorageHealthChecker     *LocalStorageHealthChecker
+	localStorageHealthCheckerOnce sync.Once
+)
+
+// GetLocalStorageHealthChecker returns a singleton instance of LocalStorageHealthChecker
+func GetLocalStorageHealthChecker() definitions.HealthChecker {
+	localStorageHealthCheckerOnce.Do(func() {
+		localStorageHealthChecker = &LocalStorageHealthChecker{
+			rootPath: sharedInfrastructure.GetEnvironment().LocalStorageRootPath,
+		}
+	})
+
+	return localStorageHealthChecker
+}
+
+// Name returns the name of the dependency
+func (l *LocalStorageHealthChecker) Name() string {
+	return "local_storage"
+}
+
+// Check creates and removes an empty file in the storage root, creating the root if needed
+func (l *LocalStorageHealthChecker) Check(ctx context.Context) error {
+	if err := os.MkdirAll(l.rootPath, 0o755); err != nil {
+		return fmt.Errorf("error creating storage root: %w", err)
+	}
+
+	file, err := os.CreateTemp(l.rootPath, ".health-*")
+	if err != nil {
+		return fmt.Errorf("error writing to storage root: %w", err)
+	}
+
+	_ = file.Close()
+	return os.Remove(file.Name())
+}
@@ -0,0 +1,41 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHealthChecker implements the HealthChecker interface by sending a PING to Redis
+type RedisHealthChecker struct {
+	client redis.UniversalClient
+}
+
+var (
+	redisHealthChecker     *RedisHealthChecker
+	redisHealthCheckerOnce sync.Once
+)
+
+// GetRedisHealthChecker returns a singleton instance of RedisHealthChecker
+func GetRedisHealthChecker() definitions.HealthChecker {
+	redisHealthCheckerOnce.Do(func() {
+		redisHealthChecker = &RedisHealthChecker{
+			client: sharedImplementations.GetRedisClient(),
+		}
+	})
+
+	return redisHealthChecker
+}
+
+// Name returns the name of the dependency
+func (r *RedisHealthChecker) Name() string {
+	return "redis"
+}
+
+// Check sends a PING to Redis
+func (r *RedisHealthChecker) Check(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
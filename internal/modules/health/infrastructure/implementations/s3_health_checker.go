@@ -0,0 +1,50 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3HealthChecker implements the HealthChecker interface by getting the metadata of an S3 bucket.
+// As the bucket is chosen on each request, the configured health check bucket is used, and the checker
+// is not registered if there is none, as listing the buckets needs a permission the uploads do not.
+type S3HealthChecker struct {
+	client *s3.Client
+	bucket string // Bucket checked with HeadBucket
+}
+
+var (
+	s3HealthChecker     *S3HealthChecker
+	s3HealthCheckerOnce sync.Once
+)
+
+// GetS3HealthChecker returns a singleton instance of S3HealthChecker
+func GetS3HealthChecker() definitions.HealthChecker {
+	s3HealthCheckerOnce.Do(func() {
+		s3HealthChecker = &S3HealthChecker{
+			client: sharedImplementations.GetS3Client(),
+			bucket: sharedInfrastructure.GetEnvironment().HealthCheckS3Bucket,
+		}
+	})
+
+	return s3HealthChecker
+}
+
+// Name returns the name of the dependency
+func (s *S3HealthChecker) Name() string {
+	return "s3"
+}
+
+// Check sends a HeadBucket request for the configured bucket
+func (s *S3HealthChecker) Check(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	return err
+}
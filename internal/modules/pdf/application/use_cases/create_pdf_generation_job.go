@@ -0,0 +1,87 @@
+package use_cases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// CreatePDFGenerationJobUseCase is the use case for queueing a PDF generation to be processed in background.
+type CreatePDFGenerationJobUseCase struct {
+	// JobStorage is the interface for job storage operations
+	JobStorage sharedDefinitions.JobStorage
+	// JobDispatcher is the interface for handing jobs to background workers
+	JobDispatcher definitions.PDFJobDispatcher
+	// CallbackURLPolicy is the interface deciding which callback URLs can be notified, any URL if nil
+	CallbackURLPolicy sharedDefinitions.URLPolicy
+}
+
+// Execute stores a new queued job for the provided request, dispatches it and returns its state.
+// The job is not created if its callback URL cannot be notified, and it is stored as failed if it cannot be dispatched.
+func (u *CreatePDFGenerationJobUseCase) Execute(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+) (*sharedDefinitions.Job, error) {
+	if request.Config.CallbackURL != nil && u.CallbackURLPolicy != nil {
+		if err := u.CallbackURLPolicy.Check(*request.Config.CallbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	job := sharedDefinitions.Job{
+		ID:        sharedInfrastructure.GenerateXID(),
+		Status:    sharedDefinitions.JOB_STATUS_QUEUED,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Store the job before dispatching it so workers always find it
+	if err := u.JobStorage.Set(ctx, job); err != nil {
+		return nil, fmt.Errorf("error storing queued job: %w", err)
+	}
+
+	// Hand the job to the workers, or record it as failed so it is not left queued
+	if err := u.JobDispatcher.Dispatch(job.ID, request); err != nil {
+		job.Status = sharedDefinitions.JOB_STATUS_FAILED
+		job.Error = BuildJobError(err)
+		job.UpdatedAt = time.Now().UTC()
+
+		if storeErr := u.JobStorage.Set(context.WithoutCancel(ctx), job); storeErr != nil {
+			sharedUtilities.GetLogger().
+				WithError(storeErr).
+				WithField("job_id", job.ID).
+				Error("Failed to store the state of a job that could not be dispatched")
+		}
+
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// BuildJobError converts an error into the payload stored in a failed job.
+// Domain errors are exposed as is, while unexpected errors are hidden behind a generic message.
+func BuildJobError(err error) *sharedDefinitions.JobError {
+	var domainError sharedErrors.DomainError
+	if errors.As(err, &domainError) {
+		return &sharedDefinitions.JobError{
+			Code:     domainError.Code(),
+			Message:  domainError.Message(),
+			Metadata: domainError.Metadata(),
+		}
+	}
+
+	return &sharedDefinitions.JobError{
+		Code:    "ERROR",
+		Message: "There was an error processing your request",
+	}
+}
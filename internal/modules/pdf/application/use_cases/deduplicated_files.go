@@ -0,0 +1,52 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// deduplicatedFileCleaner deletes the deduplicated PDFs no file is copied from anymore
+type deduplicatedFileCleaner struct {
+	// CloudStorage is the interface for cloud storage operations
+	CloudStorage sharedDefinitions.CloudStorage
+	// FileExpirationStorage is the interface for recording when the uploaded files must be deleted
+	FileExpirationStorage sharedDefinitions.FileExpirationStorage
+	// RenderCache is the interface for caching the rendered PDFs, nil if they are not cached
+	RenderCache definitions.RenderCache
+}
+
+// delete deletes the deduplicated PDF and its rendered PDF, so identical requests render it again
+func (c deduplicatedFileCleaner) delete(ctx context.Context, file sharedDefinitions.DeduplicatedFile) error {
+	err := c.CloudStorage.DeleteFile(ctx, sharedDefinitions.DeleteFileRequest{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting deduplicated file from cloud storage: %w", err)
+	}
+
+	err = c.FileExpirationStorage.Unschedule(ctx, sharedDefinitions.ExpiringFile{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		return fmt.Errorf("error unscheduling deduplicated file expiration: %w", err)
+	}
+
+	if c.RenderCache != nil {
+		if err := c.RenderCache.Delete(ctx, file.ContentHash); err != nil {
+			return fmt.Errorf("error deleting rendered PDF of deduplicated file: %w", err)
+		}
+	}
+
+	sharedUtilities.GetLogger().
+		WithField("directory", file.FileFolder).
+		WithField("fileName", file.FilePath).
+		Info("Deduplicated PDF deleted along with its last copy")
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	pdfErrors "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/errors"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DeletePDFUseCase is the use case for deleting a generated PDF and revoking its cached URLs.
+type DeletePDFUseCase struct {
+	// CloudStorage is the interface for cloud storage operations
+	CloudStorage sharedDefinitions.CloudStorage
+	// URLCacheStorage is the interface for URL cache storage operations
+	URLCacheStorage sharedDefinitions.UrlCacheStorage
+	// FileExpirationStorage is the interface for recording when the uploaded files must be deleted
+	FileExpirationStorage sharedDefinitions.FileExpirationStorage
+	// DeduplicatedFileStorage is the interface for recording the files copied from each deduplicated PDF
+	DeduplicatedFileStorage sharedDefinitions.DeduplicatedFileStorage
+	// RenderCache is the interface for caching the rendered PDFs, nil if they are not cached
+	RenderCache definitions.RenderCache
+}
+
+// Execute deletes the PDF from cloud storage and purges every cached URL pointing to it.
+// Deleting a PDF that does not exist succeeds, unless it is identified by a hash that is not cached.
+// The deduplicated PDF the file was copied from, and its rendered PDF, are deleted as well if no other file is
+// copied from it, so identical requests render the PDF again.
+func (u *DeletePDFUseCase) Execute(ctx context.Context, request *dto.DeletePDFDTO) (result *dto.DeletedPDF, err error) {
+	ctx, span := tracer.Start(ctx, "DeletePDFUseCase.Execute")
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	file, err := u.resolveFile(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("pdf.directory", file.FileFolder),
+		attribute.String("pdf.file_name", file.FilePath),
+	)
+
+	// Delete the file first, so a failure leaves the cached URLs to be evicted once they are found stale
+	err = u.CloudStorage.DeleteFile(ctx, sharedDefinitions.DeleteFileRequest{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error deleting file from cloud storage: %w", err)
+	}
+
+	purgedCacheEntries, err := u.URLCacheStorage.DeleteByFile(ctx, *file)
+	if err != nil {
+		return nil, fmt.Errorf("error purging cached URLs of file: %w", err)
+	}
+
+	// The file is gone, so the janitor has nothing left to delete
+	err = u.FileExpirationStorage.Unschedule(ctx, sharedDefinitions.ExpiringFile{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error unscheduling file expiration: %w", err)
+	}
+
+	// Delete the deduplicated PDF the file was copied from once no copy of it is left
+	released, err := u.DeduplicatedFileStorage.RemoveCopy(ctx, sharedDefinitions.FileCopy{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error removing copy of deduplicated file: %w", err)
+	}
+	if released != nil {
+		cleaner := deduplicatedFileCleaner{
+			CloudStorage:          u.CloudStorage,
+			FileExpirationStorage: u.FileExpirationStorage,
+			RenderCache:           u.RenderCache,
+		}
+		if err := cleaner.delete(ctx, *released); err != nil {
+			return nil, err
+		}
+	}
+
+	sharedUtilities.GetLogger().
+		WithField("directory", file.FileFolder).
+		WithField("fileName", file.FilePath).
+		WithField("purgedCacheEntries", purgedCacheEntries).
+		Info("PDF deleted")
+
+	return &dto.DeletedPDF{
+		Directory:          file.FileFolder,
+		FileName:           file.FilePath,
+		PurgedCacheEntries: purgedCacheEntries,
+	}, nil
+}
+
+// resolveFile returns the file to delete, looking it up in the cache if the request identifies it by hash
+func (u *DeletePDFUseCase) resolveFile(ctx context.Context, request *dto.DeletePDFDTO) (*sharedDefinitions.CachedFile, error) {
+	if request.Hash == "" {
+		return &sharedDefinitions.CachedFile{
+			FileFolder: request.Directory,
+			FilePath:   request.FileName,
+		}, nil
+	}
+
+	file, err := u.URLCacheStorage.GetFile(ctx, request.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cached file of hash: %w", err)
+	}
+
+	if file == nil {
+		return nil, pdfErrors.NewPDFNotFoundError(request.Hash)
+	}
+
+	return file, nil
+}
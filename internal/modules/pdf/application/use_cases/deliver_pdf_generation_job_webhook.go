@@ -0,0 +1,160 @@
+package use_cases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+const (
+	WEBHOOK_EVENT_HEADER     = "X-Serpentarius-Event"
+	WEBHOOK_TIMESTAMP_HEADER = "X-Serpentarius-Timestamp"
+	WEBHOOK_SIGNATURE_HEADER = "X-Serpentarius-Signature"
+
+	PDF_GENERATION_JOB_FINISHED_EVENT = "pdf.job.finished"
+)
+
+// pdfGenerationJobWebhookPayload is the body sent to the callback URL when a job finishes
+type pdfGenerationJobWebhookPayload struct {
+	JobID     string                      `json:"jobID"`
+	Status    string                      `json:"status"`
+	URL       *string                     `json:"url,omitempty"`
+	FileSize  *int64                      `json:"fileSize,omitempty"`
+	PageCount *int                        `json:"pageCount,omitempty"`
+	CacheHit  *bool                       `json:"cacheHit,omitempty"`
+	Error     *sharedDefinitions.JobError `json:"error,omitempty"`
+}
+
+// DeliverPDFGenerationJobWebhookUseCase is the use case for notifying the caller that a PDF generation job finished.
+type DeliverPDFGenerationJobWebhookUseCase struct {
+	// Fetcher is the interface for sending HTTP requests
+	Fetcher sharedDefinitions.Fetcher
+	// JobStorage is the interface for job storage operations, used to record the deliveries
+	JobStorage sharedDefinitions.JobStorage
+	// MaxAttempts is the maximum number of delivery attempts
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled on each retry
+	InitialBackoff time.Duration
+}
+
+// Execute POSTs the final state of the job to the callback URL, retrying with exponential backoff.
+// When a secret is provided, the body is signed with HMAC-SHA256 over "{timestamp}.{body}".
+// Every attempt is recorded in the job state so failed deliveries can be inspected.
+// The retries stop as soon as the context is done, or if the callback URL cannot be reached.
+func (u *DeliverPDFGenerationJobWebhookUseCase) Execute(
+	ctx context.Context,
+	job sharedDefinitions.Job,
+	callbackURL string,
+	secret *string,
+) error {
+	body, err := json.Marshal(pdfGenerationJobWebhookPayload{
+		JobID:     job.ID,
+		Status:    job.Status,
+		URL:       job.URL,
+		FileSize:  job.FileSize,
+		PageCount: job.PageCount,
+		CacheHit:  job.CacheHit,
+		Error:     job.Error,
+	})
+	if err != nil {
+		return fmt.Errorf("error serializing webhook payload: %w", err)
+	}
+
+	job.Webhook = &sharedDefinitions.WebhookDelivery{
+		URL:    callbackURL,
+		Status: sharedDefinitions.WEBHOOK_STATUS_PENDING,
+	}
+	u.record(ctx, &job)
+
+	backoff := u.InitialBackoff
+attempts:
+	for attempt := 1; attempt <= u.MaxAttempts; attempt++ {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers := map[string]string{
+			"Content-Type":           "application/json",
+			WEBHOOK_EVENT_HEADER:     PDF_GENERATION_JOB_FINISHED_EVENT,
+			WEBHOOK_TIMESTAMP_HEADER: timestamp,
+		}
+		if secret != nil {
+			headers[WEBHOOK_SIGNATURE_HEADER] = signWebhookBody(*secret, timestamp, body)
+		}
+
+		_, err = u.Fetcher.Post(ctx, sharedDefinitions.PostRequest{
+			URL:     callbackURL,
+			Headers: headers,
+			Body:    body,
+		})
+
+		job.Webhook.Attempts = attempt
+		if err == nil {
+			job.Webhook.Status = sharedDefinitions.WEBHOOK_STATUS_DELIVERED
+			job.Webhook.LastError = nil
+			u.record(ctx, &job)
+			return nil
+		}
+
+		lastError := err.Error()
+		job.Webhook.LastError = &lastError
+		u.record(ctx, &job)
+
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("job_id", job.ID).
+			WithField("attempt", attempt).
+			Warn("Failed to deliver job webhook")
+
+		// The URLs that are not allowed, or redirect to one, would fail again
+		var domainError sharedErrors.DomainError
+		if errors.As(err, &domainError) {
+			break
+		}
+
+		// Wait before retrying, unless this was the last attempt
+		if attempt < u.MaxAttempts {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break attempts
+			case <-time.After(backoff):
+				backoff *= 2
+			}
+		}
+	}
+
+	job.Webhook.Status = sharedDefinitions.WEBHOOK_STATUS_FAILED
+	u.record(context.WithoutCancel(ctx), &job)
+
+	return fmt.Errorf("error delivering webhook after %d attempts: %w", job.Webhook.Attempts, err)
+}
+
+// record stores the current delivery state of the job
+func (u *DeliverPDFGenerationJobWebhookUseCase) record(ctx context.Context, job *sharedDefinitions.Job) {
+	job.Webhook.UpdatedAt = time.Now().UTC()
+
+	if err := u.JobStorage.Set(ctx, *job); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("job_id", job.ID).
+			Error("Failed to record webhook delivery")
+	}
+}
+
+// signWebhookBody returns the "sha256={hex}" HMAC signature of the timestamp and body
+func signWebhookBody(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
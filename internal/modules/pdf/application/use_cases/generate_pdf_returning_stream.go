@@ -0,0 +1,68 @@
+package use_cases
+
+import (
+	"context"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	templateDefinitions "github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+)
+
+// GeneratePDFReturningStreamUseCase is the use case for generating a PDF and returning its content directly.
+type GeneratePDFReturningStreamUseCase struct {
+	// PDFGenerator is the interface for generating PDFs
+	PDFGenerator definitions.PDFGenerator
+	// TemplateStorage is the interface for template storage operations
+	TemplateStorage templateDefinitions.TemplateStorage
+	// TemplateEngine is the interface for rendering templates
+	TemplateEngine templateDefinitions.TemplateEngine
+	// Fingerprinter builds the render cache key of the requests
+	Fingerprinter fingerprint.RequestFingerprinter
+	// RenderCache is the interface for caching the rendered PDFs, nil if they are not cached
+	RenderCache definitions.RenderCache
+}
+
+// Execute generates a PDF based on the provided request and returns it as a stream with its size.
+// The PDF is returned from the render cache if one rendered from the same inputs is cached,
+// unless the request has remote items.
+func (u *GeneratePDFReturningStreamUseCase) Execute(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+) (*dto.PDFStream, error) {
+	// Resolve the item templates, pinning their versions so they are part of the render cache key
+	templates, err := resolveItemTemplates(ctx, request, u.TemplateStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	// The PDFs of requests with remote items are never cached, as the pages can change between renders
+	var contentHash string
+	shareable := false
+	if u.RenderCache != nil {
+		contentHash, shareable, err = u.Fingerprinter.FingerprintContent(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Render the item templates and generate the PDF
+	renderer := pdfRenderer{
+		PDFGenerator:   u.PDFGenerator,
+		TemplateEngine: u.TemplateEngine,
+	}
+	if shareable {
+		renderer.RenderCache = u.RenderCache
+	}
+	pdf, err := renderer.render(ctx, request, templates, contentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PDFStream{
+		Reader:    pdf.Reader,
+		Size:      pdf.Size,
+		FileName:  request.Config.FileName,
+		ItemCache: pdf.ItemCache,
+	}, nil
+}
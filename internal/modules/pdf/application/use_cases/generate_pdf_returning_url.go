@@ -1,15 +1,24 @@
 package use_cases
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	pdfErrors "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/errors"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
 	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
 	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	templateDefinitions "github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	templateDto "github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// DEDUPLICATED_FILES_PREFIX prefixes the paths of the PDFs shared by the requests with the same render inputs
+const DEDUPLICATED_FILES_PREFIX = "deduplicated/"
+
 // GeneratePDFReturningURLUseCase is the use case for generating a PDF and returning its public URL.
 type GeneratePDFReturningURLUseCase struct {
 	// PDFGenerator is the interface for generating PDFs
@@ -18,92 +27,519 @@ type GeneratePDFReturningURLUseCase struct {
 	CloudStorage sharedDefinitions.CloudStorage
 	// URLCacheStorage is the interface for URL cache storage operations
 	URLCacheStorage sharedDefinitions.UrlCacheStorage
-	// HashGenerator is the interface for generating hashes
-	HashGenerator sharedDefinitions.HashGenerator
+	// Fingerprinter builds the cache key of the requests
+	Fingerprinter fingerprint.RequestFingerprinter
+	// RenderCache is the interface for caching the rendered PDFs, nil if they are not cached
+	RenderCache definitions.RenderCache
+	// TemplateStorage is the interface for template storage operations
+	TemplateStorage templateDefinitions.TemplateStorage
+	// TemplateEngine is the interface for rendering templates
+	TemplateEngine templateDefinitions.TemplateEngine
+	// MetricsRecorder is the interface for recording the cache and upload metrics
+	MetricsRecorder sharedDefinitions.MetricsRecorder
+	// FileExpirationStorage is the interface for recording when the uploaded files must be deleted
+	FileExpirationStorage sharedDefinitions.FileExpirationStorage
+	// DeduplicatedFileStorage is the interface for recording the files copied from each deduplicated PDF
+	DeduplicatedFileStorage sharedDefinitions.DeduplicatedFileStorage
+	// Coalescer is the interface for generating the PDF once for the identical concurrent requests, nil if they are not coalesced
+	Coalescer definitions.RequestCoalescer
+	// DefaultRetention is the seconds the files are kept when the request does not say, forever if 0
+	DefaultRetention int64
 }
 
 // Execute generates a PDF based on the provided request and returns the URL of the generated PDF.
+// Each step is traced in its own span, children of the span of the whole execution.
 func (u *GeneratePDFReturningURLUseCase) Execute(
+	ctx context.Context,
 	request *dto.PDFGenerationDTO,
-) (string, error) {
-	// Stringify the request to generate the cache key from it
-	stringifiedRequest, err := json.Marshal(request)
-	if err != nil {
-		return "", fmt.Errorf("error stringifying request to generate cache key: %w", err)
+) (result *dto.PDFURL, err error) {
+	ctx, span := tracer.Start(ctx, "GeneratePDFReturningURLUseCase.Execute")
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	// Presigned URLs cannot be signed without a lifetime
+	if request.Config.URLMode == sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED && getExpiration(request) <= 0 {
+		return nil, pdfErrors.NewPresignedURLExpirationNotValidError()
 	}
 
-	// Generate a hash from the stringified request to use as a cache key
-	hash, err := u.HashGenerator.GenerateHash(string(stringifiedRequest))
+	// Resolve the item templates, pinning their versions so they are part of the cache key
+	stepCtx, stepSpan := tracer.Start(ctx, "resolveItemTemplates")
+	templates, err := resolveItemTemplates(stepCtx, request, u.TemplateStorage)
+	sharedUtilities.EndSpan(stepSpan, err)
 	if err != nil {
-		return "", fmt.Errorf("error generating hash for cache key: %w", err)
+		return nil, err
 	}
 
-	// Check if the URL is already cached
-	cachedURL, err := u.URLCacheStorage.Get(hash)
+	// Generate the cache key from the request
+	_, stepSpan = tracer.Start(ctx, "generateCacheKey")
+	hash, err := u.Fingerprinter.Fingerprint(request)
+	sharedUtilities.EndSpan(stepSpan, err)
 	if err != nil {
-		return "", fmt.Errorf("error checking cache for URL: %w", err)
+		return nil, err
 	}
 
-	// If the URL is cached, check if the file exists in cloud storage and return it
+	// Return the cached URL if the file still exists
+	stepCtx, stepSpan = tracer.Start(ctx, "lookupCachedURL")
+	cachedURL, lookupResult, err := u.lookupCachedURL(stepCtx, request, hash)
+	stepSpan.SetAttributes(attribute.Bool("cache.hit", cachedURL != nil))
+	sharedUtilities.EndSpan(stepSpan, err)
+	if err != nil {
+		return nil, err
+	}
+	u.MetricsRecorder.IncCacheLookup(lookupResult)
 	if cachedURL != nil {
-		url := *cachedURL
+		return &dto.PDFURL{
+			URL:      *cachedURL,
+			Hash:     hash,
+			CacheHit: true,
+		}, nil
+	}
 
-		// Check if the file exists in cloud storage
-		fileExists, err := u.CloudStorage.FileExists(sharedDefinitions.FileExistsRequest{
-			FileFolder: request.Config.Directory,
-			FilePath:   request.Config.FileName,
+	// Generate the PDF once for the identical concurrent requests, unless they are not coalesced
+	if u.Coalescer == nil {
+		return u.generate(ctx, request, templates, hash)
+	}
+
+	stepCtx, stepSpan = tracer.Start(ctx, "coalesceRequest")
+	result, shared, err := u.Coalescer.Do(stepCtx, hash, func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		// Another execution may have stored the PDF while this one waited for it.
+		// The lookup of this request was already recorded, so this one is not.
+		if waited {
+			cachedURL, _, err := u.lookupCachedURL(ctx, request, hash)
+			if err != nil {
+				return nil, err
+			}
+			if cachedURL != nil {
+				return &dto.PDFURL{
+					URL:      *cachedURL,
+					Hash:     hash,
+					CacheHit: true,
+				}, nil
+			}
+		}
+
+		return u.generate(ctx, request, templates, hash)
+	})
+	stepSpan.SetAttributes(attribute.Bool("coalescing.shared", shared))
+	sharedUtilities.EndSpan(stepSpan, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// generate renders the PDF of the request, or copies an identical one, and stores it, caching its URL under the hash
+func (u *GeneratePDFReturningURLUseCase) generate(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	templates map[int]*templateDto.Template,
+	hash string,
+) (*dto.PDFURL, error) {
+	var err error
+
+	// Identify the render inputs, shared by the requests rendering the same PDF.
+	// The PDFs of requests with remote items are never shared, as the pages can change between renders.
+	var contentHash string
+	shareable := false
+	if request.Config.Deduplicate || u.RenderCache != nil {
+		contentHash, shareable, err = u.Fingerprinter.FingerprintContent(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Look up the deduplicated PDF shared by every request with the same render inputs
+	var deduplicatedFile *sharedDefinitions.ExpiringFile
+	deduplicated := false
+	if request.Config.Deduplicate && shareable {
+		stepCtx, stepSpan := tracer.Start(ctx, "lookupDeduplicatedPDF")
+		deduplicatedFile, deduplicated, err = u.lookupDeduplicatedPDF(stepCtx, request, contentHash)
+		stepSpan.SetAttributes(attribute.Bool("deduplication.hit", deduplicated))
+		sharedUtilities.EndSpan(stepSpan, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Render and upload the PDF, unless an identical one is already stored
+	var fileSize *int64
+	var pageCount *int
+	var itemCache *dto.ItemCacheStats
+	if !deduplicated {
+		renderer := pdfRenderer{
+			PDFGenerator:   u.PDFGenerator,
+			TemplateEngine: u.TemplateEngine,
+		}
+		if shareable {
+			renderer.RenderCache = u.RenderCache
+		}
+		pdf, err := renderer.render(ctx, request, templates, contentHash)
+		if err != nil {
+			return nil, err
+		}
+		// Release the generated file once uploaded
+		defer func() {
+			_ = pdf.Reader.Close()
+		}()
+
+		// Upload the PDF to cloud storage, to the deduplicated location if any so later requests can copy it
+		stepCtx, stepSpan := tracer.Start(ctx, "uploadPDF")
+		err = u.uploadPDF(stepCtx, request, pdf, deduplicatedFile)
+		sharedUtilities.EndSpan(stepSpan, err)
+		if err != nil {
+			return nil, err
+		}
+
+		fileSize, pageCount, itemCache = &pdf.Size, &pdf.PageCount, &pdf.ItemCache
+	}
+
+	// Copy the deduplicated PDF to the requested location
+	if deduplicatedFile != nil {
+		stepCtx, stepSpan := tracer.Start(ctx, "copyDeduplicatedPDF")
+		err = u.CloudStorage.CopyFile(stepCtx, sharedDefinitions.CopyFileRequest{
+			SourceFileFolder: deduplicatedFile.FileFolder,
+			SourceFilePath:   deduplicatedFile.FilePath,
+			FileFolder:       request.Config.Directory,
+			FilePath:         request.Config.FileName,
+			FileOptions:      buildFileOptions(request),
 		})
+		sharedUtilities.EndSpan(stepSpan, err)
 		if err != nil {
-			return "", fmt.Errorf("error checking file existence in cloud storage: %w", err)
+			return nil, fmt.Errorf("error copying deduplicated file in cloud storage: %w", err)
 		}
 
-		// If the file exists, return the cached URL
-		if fileExists {
-			sharedUtilities.GetLogger().
-				WithField("url", url).
-				Info("Cache HIT for URL (file exists in cloud storage)")
+		stepCtx, stepSpan = tracer.Start(ctx, "recordDeduplicatedPDFCopy")
+		err = u.recordDeduplicatedPDFCopy(stepCtx, request, *deduplicatedFile, contentHash)
+		sharedUtilities.EndSpan(stepSpan, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Get the URL the PDF can be downloaded from
+	stepCtx, stepSpan := tracer.Start(ctx, "getDownloadURL")
+	downloadURL, err := u.getDownloadURL(stepCtx, request)
+	sharedUtilities.EndSpan(stepSpan, err)
+	if err != nil {
+		return nil, err
+	}
 
-			return url, nil
+	// Record when the file must be deleted, or that it is kept forever if the request replaced an expiring one.
+	// Nothing is recorded if the files are kept forever by default. The expiration of the deduplicated PDF is only
+	// ever postponed, so it lives as long as the last file copied from it with a retention.
+	retention := u.getRetention(request)
+	if retention > 0 || request.Config.Retention != nil {
+		stepCtx, stepSpan = tracer.Start(ctx, "scheduleFileExpiration")
+		file := sharedDefinitions.ExpiringFile{
+			FileFolder: request.Config.Directory,
+			FilePath:   request.Config.FileName,
+		}
+		// The PDF is already stored, so the request succeeds even if its expiration cannot be recorded
+		scheduleErr := u.scheduleFileExpiration(stepCtx, file, retention)
+		if scheduleErr != nil {
+			logFileExpirationFailure(scheduleErr, file)
+		}
+		if deduplicatedFile != nil && retention > 0 {
+			if err := u.extendDeduplicatedFileExpiration(stepCtx, *deduplicatedFile, retention); err != nil {
+				scheduleErr = err
+				logFileExpirationFailure(err, *deduplicatedFile)
+			}
 		}
+		sharedUtilities.EndSpan(stepSpan, scheduleErr)
+	}
 
-		// If the file does not exist, remove it from the cache
-		err = u.URLCacheStorage.Delete(hash)
+	// Cache the URL with the generated hash as the key, unless its signature already expired
+	cacheExpiration, cacheable := getCacheExpiration(request, downloadURL, retention)
+	if cacheable {
+		stepCtx, stepSpan = tracer.Start(ctx, "storeCachedURL")
+		err = u.URLCacheStorage.Set(stepCtx, sharedDefinitions.SetURLCacheRequest{
+			Key:        hash,
+			Value:      downloadURL.URL,
+			Expiration: cacheExpiration,
+			File: sharedDefinitions.CachedFile{
+				FileFolder: request.Config.Directory,
+				FilePath:   request.Config.FileName,
+			},
+		})
+		sharedUtilities.EndSpan(stepSpan, err)
+		// The PDF is already stored, so the request succeeds even if its URL cannot be cached
 		if err != nil {
-			return "", fmt.Errorf("error deleting invalid URL from cache: %w", err)
+			sharedUtilities.GetLogger().
+				WithError(err).
+				WithField("hash", hash).
+				Warn("Failed to set cache for URL")
 		}
 	}
 
-	// Generate the PDF
-	pdfReader, err := u.PDFGenerator.GeneratePDF(request)
+	// Return the URL of the stored PDF
+	return &dto.PDFURL{
+		URL:          downloadURL.URL,
+		Hash:         hash,
+		FileSize:     fileSize,
+		PageCount:    pageCount,
+		CacheHit:     false,
+		Deduplicated: deduplicated,
+		ItemCache:    itemCache,
+	}, nil
+}
+
+// lookupCachedURL returns the cached URL of the request if the file still exists in cloud storage.
+// Cached URLs pointing to missing files are evicted, so nil is returned on both misses and stale entries.
+func (u *GeneratePDFReturningURLUseCase) lookupCachedURL(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	hash string,
+) (*string, string, error) {
+	// Check if the URL is already cached, treating an unavailable cache as a miss
+	cachedURL, err := u.URLCacheStorage.Get(ctx, hash)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("hash", hash).
+			Warn("Failed to check cache for URL, generating the PDF")
+	}
+
+	if cachedURL == nil {
+		return nil, sharedDefinitions.CACHE_LOOKUP_MISS, nil
+	}
+
+	// Check if the file exists in cloud storage
+	fileExists, err := u.CloudStorage.FileExists(ctx, sharedDefinitions.FileExistsRequest{
+		FileFolder: request.Config.Directory,
+		FilePath:   request.Config.FileName,
+	})
 	if err != nil {
-		return "", err
+		return nil, "", fmt.Errorf("error checking file existence in cloud storage: %w", err)
+	}
+
+	// If the file exists, return the cached URL
+	if fileExists {
+		sharedUtilities.GetLogger().
+			WithField("url", *cachedURL).
+			Info("Cache HIT for URL (file exists in cloud storage)")
+
+		return cachedURL, sharedDefinitions.CACHE_LOOKUP_HIT, nil
 	}
 
-	// Upload the PDF to cloud storage
+	// If the file does not exist, remove it from the cache, where it is replaced anyway once the PDF is generated
+	err = u.URLCacheStorage.Delete(ctx, hash)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("hash", hash).
+			Warn("Failed to delete invalid URL from cache")
+	}
+
+	return nil, sharedDefinitions.CACHE_LOOKUP_STALE, nil
+}
+
+// lookupDeduplicatedPDF returns the location of the deduplicated PDF, named after the hash of the render inputs,
+// and whether it is already stored
+func (u *GeneratePDFReturningURLUseCase) lookupDeduplicatedPDF(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	contentHash string,
+) (*sharedDefinitions.ExpiringFile, bool, error) {
+	file := &sharedDefinitions.ExpiringFile{
+		FileFolder: request.Config.Directory,
+		FilePath:   DEDUPLICATED_FILES_PREFIX + contentHash + ".pdf",
+	}
+
+	exists, err := u.CloudStorage.FileExists(ctx, sharedDefinitions.FileExistsRequest{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("error checking deduplicated file existence in cloud storage: %w", err)
+	}
+
+	return file, exists, nil
+}
+
+// recordDeduplicatedPDFCopy records the requested file as a copy of the deduplicated PDF, so the deduplicated PDF is
+// deleted along with its last copy. The PDF the file was copied from before is deleted if no copy of it is left.
+func (u *GeneratePDFReturningURLUseCase) recordDeduplicatedPDFCopy(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	deduplicatedFile sharedDefinitions.ExpiringFile,
+	contentHash string,
+) error {
+	released, err := u.DeduplicatedFileStorage.AddCopy(ctx, sharedDefinitions.DeduplicatedFile{
+		FileFolder:  deduplicatedFile.FileFolder,
+		FilePath:    deduplicatedFile.FilePath,
+		ContentHash: contentHash,
+	}, sharedDefinitions.FileCopy{
+		FileFolder: request.Config.Directory,
+		FilePath:   request.Config.FileName,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording copy of deduplicated file: %w", err)
+	}
+
+	if released == nil {
+		return nil
+	}
+
+	// The PDF is already stored, so the request succeeds even if the replaced PDF cannot be deleted
+	cleaner := deduplicatedFileCleaner{
+		CloudStorage:          u.CloudStorage,
+		FileExpirationStorage: u.FileExpirationStorage,
+		RenderCache:           u.RenderCache,
+	}
+	if err := cleaner.delete(ctx, *released); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("file", released.FileFolder+"/"+released.FilePath).
+			Warn("Failed to delete the deduplicated PDF without copies")
+	}
+
+	return nil
+}
+
+// buildFileOptions returns the options the file of the request is stored with
+func buildFileOptions(request *dto.PDFGenerationDTO) sharedDefinitions.FileOptions {
+	return sharedDefinitions.FileOptions{
+		ContentType:          "application/pdf",
+		Metadata:             request.Config.Metadata,
+		Tags:                 request.Config.Tags,
+		CacheControl:         request.Config.CacheControl,
+		ContentDisposition:   request.Config.ContentDisposition,
+		ServerSideEncryption: request.Config.ServerSideEncryption,
+		KMSKeyID:             request.Config.KMSKeyID,
+		StorageClass:         request.Config.StorageClass,
+	}
+}
+
+// uploadPDF uploads the generated PDF to cloud storage, to the given location or the requested one if nil
+func (u *GeneratePDFReturningURLUseCase) uploadPDF(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	pdf *dto.GeneratedPDF,
+	location *sharedDefinitions.ExpiringFile,
+) error {
 	uploadRequest := sharedDefinitions.UploadFileRequest{
-		FileReader:      pdfReader,
+		FileReader:  pdf.Reader,
+		FileFolder:  request.Config.Directory,
+		FilePath:    request.Config.FileName,
+		FileOptions: buildFileOptions(request),
+	}
+	if location != nil {
+		uploadRequest.FileFolder = location.FileFolder
+		uploadRequest.FilePath = location.FilePath
+	}
+
+	uploadStart := time.Now()
+	if err := u.CloudStorage.UploadFile(ctx, uploadRequest); err != nil {
+		return fmt.Errorf("error uploading file to cloud storage: %w", err)
+	}
+	u.MetricsRecorder.ObserveUpload(time.Since(uploadStart), pdf.Size)
+
+	return nil
+}
+
+// getDownloadURL returns the URL the requested file can be downloaded from
+func (u *GeneratePDFReturningURLUseCase) getDownloadURL(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+) (*sharedDefinitions.DownloadURL, error) {
+	downloadURL, err := u.CloudStorage.GetDownloadURL(ctx, sharedDefinitions.GetDownloadURLRequest{
 		FileFolder:      request.Config.Directory,
 		FilePath:        request.Config.FileName,
-		ContentType:     "application/pdf",
+		Mode:            request.Config.URLMode,
 		PublicURLPrefix: request.Config.PublicURLPrefix,
-	}
-	url, err := u.CloudStorage.UploadFile(uploadRequest)
+		Expiration:      time.Duration(getExpiration(request)) * time.Second,
+	})
 	if err != nil {
-		return "", fmt.Errorf("error uploading file to cloud storage: %w", err)
+		return nil, fmt.Errorf("error getting download URL from cloud storage: %w", err)
 	}
 
-	// Cache the URL with the generated hash as the key
-	cacheRequest := sharedDefinitions.SetURLCacheRequest{
-		Key:        hash,
-		Value:      url,
-		Expiration: *request.Config.Expiration,
+	return downloadURL, nil
+}
+
+// getExpiration returns the requested expiration in seconds, zero if there is none
+func getExpiration(request *dto.PDFGenerationDTO) int64 {
+	if request.Config.Expiration == nil {
+		return 0
 	}
 
-	err = u.URLCacheStorage.Set(cacheRequest)
-	if err != nil {
-		return "", fmt.Errorf("error setting cache for URL: %w", err)
+	return *request.Config.Expiration
+}
+
+// getRetention returns the seconds the file is kept, zero if it is kept forever
+func (u *GeneratePDFReturningURLUseCase) getRetention(request *dto.PDFGenerationDTO) int64 {
+	if request.Config.Retention == nil {
+		return u.DefaultRetention
+	}
+
+	return *request.Config.Retention
+}
+
+// scheduleFileExpiration records when the file must be deleted, or removes its record if it is kept forever
+func (u *GeneratePDFReturningURLUseCase) scheduleFileExpiration(
+	ctx context.Context,
+	file sharedDefinitions.ExpiringFile,
+	retention int64,
+) error {
+	if retention <= 0 {
+		if err := u.FileExpirationStorage.Unschedule(ctx, file); err != nil {
+			return fmt.Errorf("error unscheduling file expiration: %w", err)
+		}
+		return nil
+	}
+
+	expiresAt := time.Now().Add(time.Duration(retention) * time.Second)
+	if err := u.FileExpirationStorage.Schedule(ctx, file, expiresAt); err != nil {
+		return fmt.Errorf("error scheduling file expiration: %w", err)
+	}
+
+	return nil
+}
+
+// logFileExpirationFailure warns the expiration of the stored file could not be recorded
+func logFileExpirationFailure(err error, file sharedDefinitions.ExpiringFile) {
+	sharedUtilities.GetLogger().
+		WithError(err).
+		WithField("file", file.FileFolder+"/"+file.FilePath).
+		Warn("Failed to record the file expiration")
+}
+
+// extendDeduplicatedFileExpiration postpones the deletion of the deduplicated PDF to the end of the retention,
+// unless it is already deleted later. It is never brought forward nor unscheduled, as other files are copied from it.
+func (u *GeneratePDFReturningURLUseCase) extendDeduplicatedFileExpiration(
+	ctx context.Context,
+	file sharedDefinitions.ExpiringFile,
+	retention int64,
+) error {
+	expiresAt := time.Now().Add(time.Duration(retention) * time.Second)
+	if err := u.FileExpirationStorage.Extend(ctx, file, expiresAt); err != nil {
+		return fmt.Errorf("error extending deduplicated file expiration: %w", err)
+	}
+
+	return nil
+}
+
+// getCacheExpiration returns the seconds the URL can be cached, so a cached URL never outlives its signature nor its file.
+// The URL must not be cached if its signature expires in less than a second.
+func getCacheExpiration(request *dto.PDFGenerationDTO, downloadURL *sharedDefinitions.DownloadURL, retention int64) (int64, bool) {
+	expiration := getExpiration(request)
+	if retention > 0 && (expiration <= 0 || retention < expiration) {
+		expiration = retention
+	}
+
+	if downloadURL.ExpiresAt == nil {
+		return expiration, true
+	}
+
+	remaining := int64(time.Until(*downloadURL.ExpiresAt) / time.Second)
+	if remaining <= 0 {
+		return 0, false
+	}
+
+	if expiration <= 0 || remaining < expiration {
+		return remaining, true
 	}
 
-	// Return the public URL of the uploaded PDF
-	return url, nil
+	return expiration, true
 }
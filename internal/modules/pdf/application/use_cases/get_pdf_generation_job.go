@@ -0,0 +1,36 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+)
+
+// GetPDFGenerationJobUseCase is the use case for retrieving the state of a PDF generation job.
+type GetPDFGenerationJobUseCase struct {
+	// JobStorage is the interface for job storage operations
+	JobStorage sharedDefinitions.JobStorage
+}
+
+// Execute returns the state of the job with the given ID.
+func (u *GetPDFGenerationJobUseCase) Execute(ctx context.Context, jobID string) (*sharedDefinitions.Job, error) {
+	job, err := u.JobStorage.Get(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting job: %w", err)
+	}
+
+	if job == nil {
+		code := sharedErrors.JOB_NOT_FOUND_ERROR_CODE
+		return nil, sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+			Code:    &code,
+			Message: "The requested job does not exist or has expired",
+			Metadata: map[string]any{
+				"jobID": jobID,
+			},
+		})
+	}
+
+	return job, nil
+}
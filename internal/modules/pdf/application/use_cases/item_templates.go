@@ -0,0 +1,61 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	templateDefinitions "github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	templateDto "github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	templateErrors "github.com/PChaparro/serpentarius/internal/modules/template/domain/errors"
+)
+
+// resolveItemTemplates gets the templates used by the request items, indexed by item position.
+// The version of every templated item is pinned to the resolved one, so the request always
+// identifies the exact template content (E.g, when it is hashed to generate the cache key).
+func resolveItemTemplates(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	templateStorage templateDefinitions.TemplateStorage,
+) (map[int]*templateDto.Template, error) {
+	templates := make(map[int]*templateDto.Template)
+
+	for idx := range request.Items {
+		item := &request.Items[idx]
+		if item.Template == nil {
+			continue
+		}
+
+		template, err := templateStorage.Get(ctx, *item.Template, item.TemplateVersion)
+		if err != nil {
+			return nil, fmt.Errorf("error getting item template: %w", err)
+		}
+
+		if template == nil {
+			return nil, templateErrors.NewTemplateNotFoundError(*item.Template, item.TemplateVersion)
+		}
+
+		item.TemplateVersion = &template.Version
+		templates[idx] = template
+	}
+
+	return templates, nil
+}
+
+// renderItemTemplates renders the resolved templates with the item data into the items HTML
+func renderItemTemplates(
+	request *dto.PDFGenerationDTO,
+	templates map[int]*templateDto.Template,
+	templateEngine templateDefinitions.TemplateEngine,
+) error {
+	for idx, template := range templates {
+		html, err := templateEngine.Render(template.Content, request.Items[idx].Data)
+		if err != nil {
+			return templateErrors.NewTemplateRenderFailedError(template.ID, template.Version, err.Error())
+		}
+
+		request.Items[idx].BodyHTML = html
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package use_cases
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	templateDefinitions "github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	templateDto "github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// pdfRenderer renders the PDF of a request, going through the render cache if there is one
+type pdfRenderer struct {
+	// PDFGenerator is the interface for generating PDFs
+	PDFGenerator definitions.PDFGenerator
+	// TemplateEngine is the interface for rendering templates
+	TemplateEngine templateDefinitions.TemplateEngine
+	// RenderCache is the interface for caching the rendered PDFs, nil if they are not cached
+	RenderCache definitions.RenderCache
+}
+
+// render returns the PDF cached under the key, or renders the item templates and generates the PDF, caching it if it fits.
+// The render cache is only a shortcut, so its failures are logged and the PDF is generated anyway.
+func (r pdfRenderer) render(
+	ctx context.Context,
+	request *dto.PDFGenerationDTO,
+	templates map[int]*templateDto.Template,
+	key string,
+) (*dto.GeneratedPDF, error) {
+	// Return the PDF rendered from the same inputs
+	if r.RenderCache != nil {
+		stepCtx, stepSpan := tracer.Start(ctx, "lookupRenderedPDF")
+		cached, err := r.RenderCache.Get(stepCtx, key)
+		stepSpan.SetAttributes(attribute.Bool("render_cache.hit", cached != nil))
+		sharedUtilities.EndSpan(stepSpan, err)
+		if err != nil {
+			sharedUtilities.GetLogger().
+				WithError(err).
+				WithField("key", key).
+				Warn("Failed to get rendered PDF from cache")
+		}
+		if cached != nil {
+			return newInMemoryPDF(cached), nil
+		}
+	}
+
+	// Render the item templates
+	_, stepSpan := tracer.Start(ctx, "renderItemTemplates")
+	err := renderItemTemplates(request, templates, r.TemplateEngine)
+	sharedUtilities.EndSpan(stepSpan, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the PDF
+	stepCtx, stepSpan := tracer.Start(ctx, "generatePDF")
+	pdf, err := r.PDFGenerator.GeneratePDF(stepCtx, request)
+	sharedUtilities.EndSpan(stepSpan, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.RenderCache == nil || pdf.Size > r.RenderCache.MaxEntrySize() {
+		return pdf, nil
+	}
+
+	// Cache the PDF, reading it into memory so it is also returned from there
+	stepCtx, stepSpan = tracer.Start(ctx, "cacheRenderedPDF")
+	cached, err := readPDF(pdf)
+	if err != nil {
+		sharedUtilities.EndSpan(stepSpan, err)
+		return nil, err
+	}
+	err = r.RenderCache.Set(stepCtx, key, cached)
+	sharedUtilities.EndSpan(stepSpan, err)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("key", key).
+			Warn("Failed to cache rendered PDF")
+	}
+
+	inMemoryPDF := newInMemoryPDF(cached)
+	inMemoryPDF.ItemCache = pdf.ItemCache
+	return inMemoryPDF, nil
+}
+
+// readPDF reads the generated PDF into memory and releases it
+func readPDF(pdf *dto.GeneratedPDF) (*dto.CachedPDF, error) {
+	defer func() {
+		_ = pdf.Reader.Close()
+	}()
+
+	content, err := io.ReadAll(pdf.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading generated PDF: %w", err)
+	}
+
+	return &dto.CachedPDF{Content: content, PageCount: pdf.PageCount}, nil
+}
+
+// inMemoryPDFReader reads a PDF held in memory. It embeds the reader, so consumers can also
+// read it at offsets and seek it, as they can with the generated files (E.g, the S3 multipart uploads).
+type inMemoryPDFReader struct {
+	*bytes.Reader
+}
+
+// Close does nothing, as there is nothing to release
+func (r inMemoryPDFReader) Close() error {
+	return nil
+}
+
+// newInMemoryPDF returns a generated PDF reading the cached content
+func newInMemoryPDF(pdf *dto.CachedPDF) *dto.GeneratedPDF {
+	return &dto.GeneratedPDF{
+		Reader:    inMemoryPDFReader{Reader: bytes.NewReader(pdf.Content)},
+		Size:      int64(len(pdf.Content)),
+		PageCount: pdf.PageCount,
+	}
+}
@@ -0,0 +1,6 @@
+package use_cases
+
+import "go.opentelemetry.io/otel"
+
+// tracer creates the spans of the PDF use cases
+var tracer = otel.Tracer("github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases")
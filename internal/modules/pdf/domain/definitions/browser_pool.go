@@ -0,0 +1,9 @@
+package definitions
+
+import "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+
+// BrowserPool is the interface for inspecting the browsers used to render the PDFs
+type BrowserPool interface {
+	// Stats returns a snapshot of the browsers, their pages and the clients waiting for a page
+	Stats() dto.BrowserPoolStats
+}
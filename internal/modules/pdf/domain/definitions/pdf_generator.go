@@ -1,14 +1,14 @@
 package definitions
 
 import (
-	"io"
+	"context"
 
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
 )
 
 // PDFGenerator is the interface for generating PDFs
 type PDFGenerator interface {
-	// GeneratePDF generates a PDF based on the provided request.
-	// It returns the generated PDF as an stream and an error if any occurred.
-	GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader, error)
+	// GeneratePDF generates a PDF based on the provided request, aborting if the context is done.
+	// It returns the generated PDF as an stream along with its size and page count, and an error if any occurred.
+	GeneratePDF(ctx context.Context, request *dto.PDFGenerationDTO) (*dto.GeneratedPDF, error)
 }
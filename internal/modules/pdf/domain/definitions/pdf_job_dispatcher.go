@@ -0,0 +1,12 @@
+package definitions
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+)
+
+// PDFJobDispatcher is the interface for handing PDF generation jobs to background workers
+type PDFJobDispatcher interface {
+	// Dispatch queues the request to be processed under the given job ID.
+	// It returns an error if the job could not be queued.
+	Dispatch(jobID string, request *dto.PDFGenerationDTO) error
+}
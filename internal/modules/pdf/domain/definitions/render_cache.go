@@ -0,0 +1,20 @@
+package definitions
+
+import (
+	"context"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+)
+
+// RenderCache is the interface for caching the content of the rendered PDFs, keyed by the fingerprint of their render inputs
+type RenderCache interface {
+	// Get returns the cached PDF, nil if the key is not cached
+	Get(ctx context.Context, key string) (*dto.CachedPDF, error)
+	// Set caches the PDF under the key, replacing the previous one if any.
+	// PDFs larger than MaxEntrySize are not cached.
+	Set(ctx context.Context, key string, pdf *dto.CachedPDF) error
+	// Delete removes the PDF cached under the key, succeeding if there is none
+	Delete(ctx context.Context, key string) error
+	// MaxEntrySize returns the size in bytes of the largest PDF that can be cached
+	MaxEntrySize() int64
+}
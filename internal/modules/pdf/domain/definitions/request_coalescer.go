@@ -0,0 +1,18 @@
+package definitions
+
+import (
+	"context"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+)
+
+// CoalescedFunc generates the PDF of a request. It is told whether it waited for another execution
+// with the same key, in which case it should look for the result of that execution in the caches first.
+type CoalescedFunc func(ctx context.Context, waited bool) (*dto.PDFURL, error)
+
+// RequestCoalescer is the interface for running a single execution of the identical concurrent requests
+type RequestCoalescer interface {
+	// Do runs the function unless an execution with the same key is already running.
+	// The callers waiting for an execution get its result and error, and shared is true for them.
+	Do(ctx context.Context, key string, fn CoalescedFunc) (result *dto.PDFURL, shared bool, err error)
+}
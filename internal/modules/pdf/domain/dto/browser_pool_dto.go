@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// BrowserPageStats represents the state of a page (tab) of the browser pool
+type BrowserPageStats struct {
+	InUse     bool
+	IdleSince *time.Time // Nil while the page is in use
+	ClosesAt  *time.Time // When the idle timer closes the page, nil while the page is in use
+}
+
+// BrowserStats represents the state of a browser of the pool
+type BrowserStats struct {
+	ID    string
+	Pages []BrowserPageStats
+}
+
+// BrowserPoolStats represents a snapshot of the browser pool used to render the PDFs
+type BrowserPoolStats struct {
+	Browsers           []BrowserStats
+	MaxBrowsers        int
+	MaxPagesPerBrowser int
+	AvailablePages     int
+	WaitingQueueLength int
+	PageIdleTimeout    time.Duration
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "io"
+
 // PageSize represents the dimensions of the PDF page
 type PageSize struct {
 	Width  *float64
@@ -20,6 +22,16 @@ type PageRange struct {
 	End   int
 }
 
+// WaitFor represents the conditions the page must meet before being printed.
+// They are checked after the page loads, becomes idle and its images load, in the field order.
+type WaitFor struct {
+	Selector          *string // CSS selector of an element that must appear
+	Expression        *string // JS expression that must become truthy
+	ReadyFlag         *bool   // Whether to wait for the page to set window.serpentariusReady
+	Fonts             *bool   // Whether to wait for document.fonts.ready
+	DelayMilliseconds *int    // Fixed delay once the other conditions are met
+}
+
 // ItemConfig represents the configuration for each PDF element
 type ItemConfig struct {
 	Orientation         *string
@@ -31,20 +43,51 @@ type ItemConfig struct {
 	PageRanges          *PageRange
 	HeaderHTML          *string
 	FooterHTML          *string
+	WaitTimeoutSeconds  *int     // Max seconds to render the item, the environment default if nil
+	WaitFor             *WaitFor // Extra readiness conditions, none if nil
+}
+
+// Cookie represents a cookie sent when rendering a remote URL
+type Cookie struct {
+	Name   string
+	Value  string
+	Domain *string
+	Path   *string
 }
 
-// PDFItem represents an individual PDF generation item
+// PDFItem represents an individual PDF generation item.
+// Exactly one of BodyHTML, URL or Template must be set.
 type PDFItem struct {
-	BodyHTML string // Required field
-	Config   *ItemConfig
+	BodyHTML        string
+	URL             *string
+	Headers         map[string]string // Only used with URL
+	Cookies         []Cookie          // Only used with URL
+	Template        *string
+	TemplateVersion *int           // Latest version if nil, only used with Template
+	Data            map[string]any // Only used with Template
+	Config          *ItemConfig
 }
 
 // GeneralConfig represents the general PDF configuration
 type GeneralConfig struct {
-	Directory       string // Required field
-	FileName        string // Required field
-	PublicURLPrefix string
-	Expiration      *int64
+	Directory       string  // Required field
+	FileName        string  // Required field
+	PublicURLPrefix string  // Only used in public URL mode
+	URLMode         string  // One of the DOWNLOAD_URL_MODE_* constants
+	Expiration      *int64  // Seconds the URL is cached and, in presigned URL mode, valid
+	Retention       *int64  // Seconds the file is kept before being deleted, forever if 0 and the default if nil
+	CallbackURL     *string // Excluded from the cache key, only used by asynchronous jobs
+	CallbackSecret  *string // Excluded from the cache key, only used by asynchronous jobs
+	Deduplicate     bool    // Whether to copy an identical PDF stored by another request instead of rendering it again
+
+	// Options of the stored object, the backend defaults are used if empty
+	Metadata             map[string]string
+	Tags                 map[string]string
+	CacheControl         string
+	ContentDisposition   string
+	ServerSideEncryption string // One of the SERVER_SIDE_ENCRYPTION_* constants
+	KMSKeyID             string
+	StorageClass         string
 }
 
 // PDFGenerationDTO represents the complete PDF generation request
@@ -52,3 +95,58 @@ type PDFGenerationDTO struct {
 	Items  []PDFItem
 	Config GeneralConfig
 }
+
+// ItemCacheStats counts the items of a PDF reused from the render cache and the ones rendered
+type ItemCacheStats struct {
+	Hits   int
+	Misses int
+}
+
+// GeneratedPDF represents a PDF produced by a PDFGenerator
+type GeneratedPDF struct {
+	Reader    io.ReadCloser // Must be closed once consumed, to release the file backing it
+	Size      int64
+	PageCount int
+	ItemCache ItemCacheStats // Both 0 if no item was rendered (E.g, the whole PDF was cached)
+}
+
+// CachedPDF represents a rendered PDF held in memory by a RenderCache
+type CachedPDF struct {
+	Content   []byte
+	PageCount int
+}
+
+// PDFURL represents a PDF stored in cloud storage and the details of its generation
+type PDFURL struct {
+	URL       string
+	Hash      string // Cache key of the request, used to delete the PDF
+	FileSize  *int64 // Unknown on cache hits and deduplicated PDFs
+	PageCount *int   // Unknown on cache hits and deduplicated PDFs
+	CacheHit  bool
+	// Deduplicated is whether the PDF was copied from an identical one instead of being rendered
+	Deduplicated bool
+	// ItemCache counts the items reused from the render cache, unknown on cache hits and deduplicated PDFs
+	ItemCache *ItemCacheStats
+}
+
+// PDFStream represents a generated PDF that is returned directly to the client
+type PDFStream struct {
+	Reader    io.ReadCloser // Must be closed once sent
+	Size      int64
+	FileName  string
+	ItemCache ItemCacheStats
+}
+
+// DeletePDFDTO identifies the PDF to delete, either by its location or by the hash of the request that generated it
+type DeletePDFDTO struct {
+	Directory string
+	FileName  string
+	Hash      string
+}
+
+// DeletedPDF represents the result of deleting a PDF
+type DeletedPDF struct {
+	Directory          string
+	FileName           string
+	PurgedCacheEntries int
+}
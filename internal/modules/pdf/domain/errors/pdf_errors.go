@@ -0,0 +1,64 @@
+package errors
+
+import (
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+)
+
+// NewRenderTimeoutError creates the error returned when an item is not rendered within its timeout
+func NewRenderTimeoutError(itemIndex int, timeoutSeconds int) sharedErrors.DomainError {
+	code := sharedErrors.RENDER_TIMEOUT_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "The item took too long to be rendered",
+		Metadata: map[string]any{
+			"itemIndex":      itemIndex,
+			"timeoutSeconds": timeoutSeconds,
+		},
+	})
+}
+
+// NewScriptError creates the error returned when a script evaluated in the page throws
+func NewScriptError(itemIndex int, reason string) sharedErrors.DomainError {
+	code := sharedErrors.SCRIPT_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "A script failed while rendering the item",
+		Metadata: map[string]any{
+			"itemIndex": itemIndex,
+			"reason":    reason,
+		},
+	})
+}
+
+// NewBrowserUnavailableError creates the error returned when no browser page can be provided
+func NewBrowserUnavailableError(reason string) sharedErrors.DomainError {
+	code := sharedErrors.BROWSER_UNAVAILABLE_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "There is no browser available to render the document. Please, try again later",
+		Metadata: map[string]any{
+			"reason": reason,
+		},
+	})
+}
+
+// NewPresignedURLExpirationNotValidError creates the error returned when a presigned URL is requested without a positive expiration
+func NewPresignedURLExpirationNotValidError() sharedErrors.DomainError {
+	code := sharedErrors.PRESIGNED_URL_EXPIRATION_NOT_VALID_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "Presigned URLs require an expiration greater than zero seconds",
+	})
+}
+
+// NewPDFNotFoundError creates the error returned when the hash of a request does not point to any cached PDF
+func NewPDFNotFoundError(hash string) sharedErrors.DomainError {
+	code := sharedErrors.PDF_NOT_FOUND_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "There is no cached PDF generated from the given hash. Please, delete it by its directory and file name",
+		Metadata: map[string]any{
+			"hash": hash,
+		},
+	})
+}
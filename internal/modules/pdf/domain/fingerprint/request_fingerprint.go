@@ -0,0 +1,325 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+)
+
+// CACHE_KEY_NAMESPACE prefixes the cache keys of the generated PDFs
+const CACHE_KEY_NAMESPACE = "pdf"
+
+// ITEM_CACHE_KEY_NAMESPACE prefixes the render cache keys of the PDFs of single items
+const ITEM_CACHE_KEY_NAMESPACE = "item"
+
+// Defaults applied by Chrome when printing, so leaving an option unset and setting it to its default yield the same key
+const (
+	DEFAULT_SCALE        = 1.0
+	DEFAULT_PAPER_WIDTH  = 8.5
+	DEFAULT_PAPER_HEIGHT = 11.0
+	DEFAULT_MARGIN       = 0.4
+)
+
+// RequestFingerprinter builds the cache key of a PDF generation request from its canonical form.
+// Keys look like "pdf:v{Version}:{hash}", so bumping the version (E.g, after upgrading the renderer)
+// makes every previous key unreachable.
+type RequestFingerprinter struct {
+	// HashGenerator is the interface for generating hashes
+	HashGenerator sharedDefinitions.HashGenerator
+	// Version is the version of the keys namespace
+	Version int
+	// DefaultRetention is the retention applied when the request does not say, so it matches an explicit one
+	DefaultRetention int64
+}
+
+// canonicalRequest is the form of the request that is hashed. Its fields are tagged explicitly and
+// every default is filled, so the key does not depend on the field order nor on nil vs default values.
+type canonicalRequest struct {
+	Items  []canonicalItem `json:"items"`
+	Config canonicalConfig `json:"config"`
+}
+
+type canonicalItem struct {
+	BodyHTML        string              `json:"bodyHTML,omitempty"`
+	URL             string              `json:"url,omitempty"`
+	Headers         map[string]string   `json:"headers,omitempty"`
+	Cookies         []canonicalCookie   `json:"cookies,omitempty"`
+	Template        string              `json:"template,omitempty"`
+	TemplateVersion int                 `json:"templateVersion,omitempty"`
+	Data            map[string]any      `json:"data,omitempty"`
+	Config          canonicalItemConfig `json:"config"`
+}
+
+type canonicalCookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// canonicalContent is the form of the render inputs that is hashed to identify the rendered PDF.
+// The version is part of it, so bumping it renders the deduplicated PDFs again.
+type canonicalContent struct {
+	Version int             `json:"version"`
+	Items   []canonicalItem `json:"items"`
+}
+
+// canonicalItemContent is the form of the render inputs of a single item that is hashed to identify its PDF
+type canonicalItemContent struct {
+	Version  int                 `json:"version"`
+	BodyHTML string              `json:"bodyHTML"`
+	Config   canonicalItemConfig `json:"config"`
+}
+
+// canonicalItemConfig holds the print options of the item. The wait timeout is left out,
+// because it decides whether the item is rendered but not how it looks.
+type canonicalItemConfig struct {
+	Landscape           bool             `json:"landscape"`
+	DisplayHeaderFooter bool             `json:"displayHeaderFooter"`
+	PrintBackground     bool             `json:"printBackground"`
+	Scale               float64          `json:"scale"`
+	PaperWidth          float64          `json:"paperWidth"`
+	PaperHeight         float64          `json:"paperHeight"`
+	MarginTop           float64          `json:"marginTop"`
+	MarginBottom        float64          `json:"marginBottom"`
+	MarginLeft          float64          `json:"marginLeft"`
+	MarginRight         float64          `json:"marginRight"`
+	PageRanges          string           `json:"pageRanges,omitempty"`
+	HeaderHTML          string           `json:"headerHTML,omitempty"`
+	FooterHTML          string           `json:"footerHTML,omitempty"`
+	WaitFor             canonicalWaitFor `json:"waitFor"`
+}
+
+type canonicalWaitFor struct {
+	Selector          string `json:"selector,omitempty"`
+	Expression        string `json:"expression,omitempty"`
+	ReadyFlag         bool   `json:"readyFlag"`
+	Fonts             bool   `json:"fonts"`
+	DelayMilliseconds int    `json:"delayMilliseconds"`
+}
+
+// canonicalConfig holds the options deciding where and how the file is stored and its URL cached.
+// The callback is left out, because it only matters to asynchronous jobs.
+type canonicalConfig struct {
+	Directory            string            `json:"directory"`
+	FileName             string            `json:"fileName"`
+	URLMode              string            `json:"urlMode"`
+	PublicURLPrefix      string            `json:"publicURLPrefix,omitempty"`
+	Expiration           int64             `json:"expiration"`
+	Retention            int64             `json:"retention"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+	CacheControl         string            `json:"cacheControl,omitempty"`
+	ContentDisposition   string            `json:"contentDisposition,omitempty"`
+	ServerSideEncryption string            `json:"serverSideEncryption,omitempty"`
+	KMSKeyID             string            `json:"kmsKeyId,omitempty"`
+	StorageClass         string            `json:"storageClass,omitempty"`
+}
+
+// Fingerprint returns the namespaced cache key of the request
+func (f RequestFingerprinter) Fingerprint(request *dto.PDFGenerationDTO) (string, error) {
+	canonical, err := json.Marshal(f.canonicalize(request))
+	if err != nil {
+		return "", fmt.Errorf("error stringifying canonical request: %w", err)
+	}
+
+	hash, err := f.HashGenerator.GenerateHash(string(canonical))
+	if err != nil {
+		return "", fmt.Errorf("error generating hash of canonical request: %w", err)
+	}
+
+	return fmt.Sprintf("%s:v%d:%s", CACHE_KEY_NAMESPACE, f.Version, hash), nil
+}
+
+// FingerprintContent returns the hash of the render inputs of the request, so requests rendering
+// the same PDF share it regardless of where the file is stored or how its URL is built.
+// Remote items can change between renders, so the PDFs of requests with any of them are never
+// shared and false is returned.
+func (f RequestFingerprinter) FingerprintContent(request *dto.PDFGenerationDTO) (string, bool, error) {
+	for _, item := range request.Items {
+		if item.URL != nil {
+			return "", false, nil
+		}
+	}
+
+	canonical, err := json.Marshal(canonicalContent{
+		Version: f.Version,
+		Items:   canonicalizeItems(request.Items),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("error stringifying canonical render inputs: %w", err)
+	}
+
+	hash, err := f.HashGenerator.GenerateHash(string(canonical))
+	if err != nil {
+		return "", false, fmt.Errorf("error generating hash of canonical render inputs: %w", err)
+	}
+
+	return hash, true, nil
+}
+
+// FingerprintItem returns the render cache key of the item, built from its HTML and print options only,
+// so the item is found whatever the rest of the request is. Remote items can change between renders,
+// so they are never cached and false is returned.
+func (f RequestFingerprinter) FingerprintItem(item dto.PDFItem) (string, bool, error) {
+	if item.URL != nil {
+		return "", false, nil
+	}
+
+	canonical, err := json.Marshal(canonicalItemContent{
+		Version:  f.Version,
+		BodyHTML: item.BodyHTML,
+		Config:   canonicalizeItemConfig(item.Config),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("error stringifying canonical item: %w", err)
+	}
+
+	hash, err := f.HashGenerator.GenerateHash(string(canonical))
+	if err != nil {
+		return "", false, fmt.Errorf("error generating hash of canonical item: %w", err)
+	}
+
+	return ITEM_CACHE_KEY_NAMESPACE + ":" + hash, true, nil
+}
+
+// canonicalize converts the request to its canonical form
+func (f RequestFingerprinter) canonicalize(request *dto.PDFGenerationDTO) canonicalRequest {
+	config := request.Config
+	urlMode := config.URLMode
+	if urlMode == "" {
+		urlMode = sharedDefinitions.DOWNLOAD_URL_MODE_PUBLIC
+	}
+	publicURLPrefix := ""
+	if urlMode == sharedDefinitions.DOWNLOAD_URL_MODE_PUBLIC {
+		publicURLPrefix = config.PublicURLPrefix
+	}
+
+	return canonicalRequest{
+		Items: canonicalizeItems(request.Items),
+		Config: canonicalConfig{
+			Directory:            config.Directory,
+			FileName:             config.FileName,
+			URLMode:              urlMode,
+			PublicURLPrefix:      publicURLPrefix,
+			Expiration:           valueOr(config.Expiration, 0),
+			Retention:            valueOr(config.Retention, f.DefaultRetention),
+			Metadata:             config.Metadata,
+			Tags:                 config.Tags,
+			CacheControl:         config.CacheControl,
+			ContentDisposition:   config.ContentDisposition,
+			ServerSideEncryption: config.ServerSideEncryption,
+			KMSKeyID:             config.KMSKeyID,
+			StorageClass:         config.StorageClass,
+		},
+	}
+}
+
+// canonicalizeItems converts the items to their canonical form
+func canonicalizeItems(items []dto.PDFItem) []canonicalItem {
+	canonical := make([]canonicalItem, len(items))
+	for i, item := range items {
+		canonical[i] = canonicalizeItem(item)
+	}
+
+	return canonical
+}
+
+// canonicalizeItem converts the item to its canonical form.
+// Header names are case-insensitive and the cookies are sent as a set, so both are normalized.
+func canonicalizeItem(item dto.PDFItem) canonicalItem {
+	canonical := canonicalItem{
+		BodyHTML:        item.BodyHTML,
+		URL:             valueOr(item.URL, ""),
+		Template:        valueOr(item.Template, ""),
+		TemplateVersion: valueOr(item.TemplateVersion, 0),
+		Data:            item.Data,
+		Config:          canonicalizeItemConfig(item.Config),
+	}
+
+	if len(item.Headers) > 0 {
+		canonical.Headers = make(map[string]string, len(item.Headers))
+		for name, value := range item.Headers {
+			canonical.Headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+
+	for _, cookie := range item.Cookies {
+		canonical.Cookies = append(canonical.Cookies, canonicalCookie{
+			Name:   cookie.Name,
+			Value:  cookie.Value,
+			Domain: valueOr(cookie.Domain, ""),
+			Path:   valueOr(cookie.Path, ""),
+		})
+	}
+	slices.SortFunc(canonical.Cookies, func(a, b canonicalCookie) int {
+		return strings.Compare(a.Name+"\x00"+a.Domain+"\x00"+a.Path, b.Name+"\x00"+b.Domain+"\x00"+b.Path)
+	})
+
+	return canonical
+}
+
+// canonicalizeItemConfig fills the print options left unset with the defaults Chrome applies
+func canonicalizeItemConfig(config *dto.ItemConfig) canonicalItemConfig {
+	canonical := canonicalItemConfig{
+		Scale:        DEFAULT_SCALE,
+		PaperWidth:   DEFAULT_PAPER_WIDTH,
+		PaperHeight:  DEFAULT_PAPER_HEIGHT,
+		MarginTop:    DEFAULT_MARGIN,
+		MarginBottom: DEFAULT_MARGIN,
+		MarginLeft:   DEFAULT_MARGIN,
+		MarginRight:  DEFAULT_MARGIN,
+	}
+	if config == nil {
+		return canonical
+	}
+
+	canonical.Landscape = valueOr(config.Orientation, "") == "landscape"
+	canonical.DisplayHeaderFooter = valueOr(config.DisplayHeaderFooter, false)
+	canonical.PrintBackground = valueOr(config.PrintBackground, false)
+	canonical.Scale = valueOr(config.Scale, DEFAULT_SCALE)
+	canonical.HeaderHTML = valueOr(config.HeaderHTML, "")
+	canonical.FooterHTML = valueOr(config.FooterHTML, "")
+
+	if config.Size != nil {
+		canonical.PaperWidth = valueOr(config.Size.Width, DEFAULT_PAPER_WIDTH)
+		canonical.PaperHeight = valueOr(config.Size.Height, DEFAULT_PAPER_HEIGHT)
+	}
+
+	if config.Margin != nil {
+		canonical.MarginTop = valueOr(config.Margin.Top, DEFAULT_MARGIN)
+		canonical.MarginBottom = valueOr(config.Margin.Bottom, DEFAULT_MARGIN)
+		canonical.MarginLeft = valueOr(config.Margin.Left, DEFAULT_MARGIN)
+		canonical.MarginRight = valueOr(config.Margin.Right, DEFAULT_MARGIN)
+	}
+
+	if config.PageRanges != nil {
+		canonical.PageRanges = fmt.Sprintf("%d-%d", config.PageRanges.Start, config.PageRanges.End)
+	}
+
+	if config.WaitFor != nil {
+		canonical.WaitFor = canonicalWaitFor{
+			Selector:          valueOr(config.WaitFor.Selector, ""),
+			Expression:        valueOr(config.WaitFor.Expression, ""),
+			ReadyFlag:         valueOr(config.WaitFor.ReadyFlag, false),
+			Fonts:             valueOr(config.WaitFor.Fonts, false),
+			DelayMilliseconds: valueOr(config.WaitFor.DelayMilliseconds, 0),
+		}
+	}
+
+	return canonical
+}
+
+// valueOr returns the pointed value, or the default one if the pointer is nil
+func valueOr[T any](value *T, defaultValue T) T {
+	if value == nil {
+		return defaultValue
+	}
+
+	return *value
+}
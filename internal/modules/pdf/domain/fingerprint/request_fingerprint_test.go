@@ -0,0 +1,186 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/stretchr/testify/assert"
+)
+
+// identityHashGenerator is a HashGenerator using the input as its own hash, so the canonical form can be read back
+type identityHashGenerator struct{}
+
+// GenerateHash returns the input unchanged
+func (g identityHashGenerator) GenerateHash(input string) (string, error) {
+	return input, nil
+}
+
+// TestCanonicalize_ExcludesCallback tests the callback of asynchronous jobs does not change the cache key
+func TestCanonicalize_ExcludesCallback(t *testing.T) {
+	fingerprinter := RequestFingerprinter{HashGenerator: identityHashGenerator{}}
+	callbackURL := "https://hooks.example.com/pdf"
+	callbackSecret := "secret"
+	request := &dto.PDFGenerationDTO{
+		Items:  []dto.PDFItem{{BodyHTML: "<p>Report</p>"}},
+		Config: dto.GeneralConfig{Directory: "reports", FileName: "report.pdf"},
+	}
+
+	withoutCallback, err := fingerprinter.Fingerprint(request)
+	assert.NoError(t, err, "Fingerprinting the request should succeed")
+
+	request.Config.CallbackURL = &callbackURL
+	request.Config.CallbackSecret = &callbackSecret
+	withCallback, err := fingerprinter.Fingerprint(request)
+	assert.NoError(t, err, "Fingerprinting the request should succeed")
+
+	assert.Equal(t, withoutCallback, withCallback, "Callback should not change the cache key")
+	assert.NotContains(t, withCallback, callbackURL, "Callback URL should not be part of the canonical form")
+	assert.NotContains(t, withCallback, callbackSecret, "Callback secret should not be part of the canonical form")
+}
+
+// TestCanonicalize_Defaults tests the unset options are filled with the defaults of their mode
+func TestCanonicalize_Defaults(t *testing.T) {
+	fingerprinter := RequestFingerprinter{HashGenerator: identityHashGenerator{}, DefaultRetention: 3600}
+	canonical := fingerprinter.canonicalize(&dto.PDFGenerationDTO{
+		Config: dto.GeneralConfig{
+			Directory:       "reports",
+			FileName:        "report.pdf",
+			URLMode:         sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED,
+			PublicURLPrefix: "https://cdn.example.com",
+		},
+	})
+
+	assert.Equal(t, sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED, canonical.Config.URLMode, "URL mode should be kept")
+	assert.Empty(t, canonical.Config.PublicURLPrefix, "Public URL prefix should be ignored in presigned mode")
+	assert.Equal(t, int64(3600), canonical.Config.Retention, "Default retention should be applied")
+
+	canonical = fingerprinter.canonicalize(&dto.PDFGenerationDTO{})
+	assert.Equal(t, sharedDefinitions.DOWNLOAD_URL_MODE_PUBLIC, canonical.Config.URLMode, "Public URL mode should be the default")
+}
+
+// TestCanonicalizeItem_Normalized tests the header names and the cookie order do not change the canonical item
+func TestCanonicalizeItem_Normalized(t *testing.T) {
+	url := "https://example.com/report"
+	domain := "example.com"
+	first := canonicalizeItem(dto.PDFItem{
+		URL:     &url,
+		Headers: map[string]string{"x-tenant-id": "acme"},
+		Cookies: []dto.Cookie{
+			{Name: "session", Value: "abc"},
+			{Name: "locale", Value: "en", Domain: &domain},
+		},
+	})
+	second := canonicalizeItem(dto.PDFItem{
+		URL:     &url,
+		Headers: map[string]string{"X-Tenant-Id": "acme"},
+		Cookies: []dto.Cookie{
+			{Name: "locale", Value: "en", Domain: &domain},
+			{Name: "session", Value: "abc"},
+		},
+	})
+
+	assert.Equal(t, first, second, "Equivalent items should have the same canonical form")
+	assert.Equal(t, map[string]string{"X-Tenant-Id": "acme"}, first.Headers, "Header names should be canonicalized")
+	assert.Equal(t, "locale", first.Cookies[0].Name, "Cookies should be sorted by name")
+}
+
+// TestCanonicalizeItemConfig_Defaults tests unset print options and Chrome defaults yield the same canonical form
+func TestCanonicalizeItemConfig_Defaults(t *testing.T) {
+	scale := DEFAULT_SCALE
+	top := DEFAULT_MARGIN
+	explicit := canonicalizeItemConfig(&dto.ItemConfig{
+		Scale:  &scale,
+		Margin: &dto.PageMargin{Top: &top},
+	})
+
+	assert.Equal(t, canonicalizeItemConfig(nil), explicit, "Explicit defaults should match unset options")
+
+	landscape := "landscape"
+	assert.True(t, canonicalizeItemConfig(&dto.ItemConfig{Orientation: &landscape}).Landscape, "Landscape orientation should be kept")
+}
+
+// TestFingerprintItem tests only the inline items get a render cache key, which ignores the wait timeout
+func TestFingerprintItem(t *testing.T) {
+	fingerprinter := RequestFingerprinter{HashGenerator: identityHashGenerator{}, Version: 2}
+	url := "https://example.com/report"
+
+	_, cacheable, err := fingerprinter.FingerprintItem(dto.PDFItem{URL: &url})
+	assert.NoError(t, err, "Fingerprinting a remote item should succeed")
+	assert.False(t, cacheable, "Remote items should not be cached")
+
+	timeout := 5
+	key, cacheable, err := fingerprinter.FingerprintItem(dto.PDFItem{
+		BodyHTML: "<p>Item</p>",
+		Config:   &dto.ItemConfig{WaitTimeoutSeconds: &timeout},
+	})
+	assert.NoError(t, err, "Fingerprinting an inline item should succeed")
+	assert.True(t, cacheable, "Inline items should be cached")
+
+	keyWithoutTimeout, _, _ := fingerprinter.FingerprintItem(dto.PDFItem{BodyHTML: "<p>Item</p>"})
+	assert.Equal(t, keyWithoutTimeout, key, "Wait timeout should not change the item key")
+
+	var content canonicalItemContent
+	if assert.NoError(t, json.Unmarshal([]byte(key[len(ITEM_CACHE_KEY_NAMESPACE)+1:]), &content), "Key should hold the canonical item") {
+		assert.Equal(t, 2, content.Version, "Version should be part of the item key")
+	}
+}
+
+// TestFingerprintContent_RemoteItems tests the render inputs of requests with remote items are never shared
+func TestFingerprintContent_RemoteItems(t *testing.T) {
+	fingerprinter := RequestFingerprinter{HashGenerator: identityHashGenerator{}}
+	url := "https://example.com/report"
+
+	_, shareable, err := fingerprinter.FingerprintContent(&dto.PDFGenerationDTO{
+		Items: []dto.PDFItem{{BodyHTML: "<p>Cover</p>"}, {URL: &url}},
+	})
+	assert.NoError(t, err, "Fingerprinting a remote request should succeed")
+	assert.False(t, shareable, "Requests with remote items should not be shared")
+
+	hash, shareable, err := fingerprinter.FingerprintContent(&dto.PDFGenerationDTO{
+		Items: []dto.PDFItem{{BodyHTML: "<p>Cover</p>"}},
+	})
+	assert.NoError(t, err, "Fingerprinting an inline request should succeed")
+	assert.True(t, shareable, "Inline requests should be shared")
+	assert.NotEmpty(t, hash, "Inline requests should have a content hash")
+}
+
+// TestFingerprint_Namespace tests the keys are prefixed by the versioned namespace and stable across calls
+func TestFingerprint_Namespace(t *testing.T) {
+	v1 := RequestFingerprinter{HashGenerator: identityHashGenerator{}, Version: 1}
+	v2 := RequestFingerprinter{HashGenerator: identityHashGenerator{}, Version: 2}
+	request := &dto.PDFGenerationDTO{
+		Items:  []dto.PDFItem{{BodyHTML: "<p>Report</p>"}},
+		Config: dto.GeneralConfig{Directory: "reports", FileName: "report.pdf"},
+	}
+
+	key, err := v1.Fingerprint(request)
+	assert.NoError(t, err, "Fingerprinting the request should succeed")
+	assert.True(t, strings.HasPrefix(key, CACHE_KEY_NAMESPACE+":v1:"), "Key should be prefixed by the namespace and version")
+
+	sameKey, _ := v1.Fingerprint(request)
+	assert.Equal(t, key, sameKey, "Same request should yield the same key")
+
+	otherVersionKey, _ := v2.Fingerprint(request)
+	assert.NotEqual(t, key, otherVersionKey, "Bumping the version should change every key")
+}
+
+// TestFingerprint_Location tests requests stored in different directories never share a key
+func TestFingerprint_Location(t *testing.T) {
+	fingerprinter := RequestFingerprinter{HashGenerator: identityHashGenerator{}, Version: 1}
+	request := &dto.PDFGenerationDTO{
+		Items:  []dto.PDFItem{{BodyHTML: "<p>Report</p>"}},
+		Config: dto.GeneralConfig{Directory: "reports", FileName: "report.pdf"},
+	}
+
+	key, err := fingerprinter.Fingerprint(request)
+	assert.NoError(t, err, "Fingerprinting the request should succeed")
+
+	request.Config.Directory = "other-tenant"
+	otherKey, err := fingerprinter.Fingerprint(request)
+	assert.NoError(t, err, "Fingerprinting the request should succeed")
+
+	assert.NotEqual(t, key, otherKey, "Different directories should change the key")
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/requests"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePDFGenerationJobController handles the creation of asynchronous PDF generation jobs.
+type CreatePDFGenerationJobController struct {
+	UseCase use_cases.CreatePDFGenerationJobUseCase
+}
+
+// Handle processes the request to queue a PDF generation and returns the created job.
+func (controller *CreatePDFGenerationJobController) Handle(c *gin.Context) {
+	// Get validated request from context
+	req := sharedMiddlewares.GetValidatedRequest(c).(*requests.GeneratePDFReturningURLRequest)
+
+	// Convert request to DTO
+	dto := req.ToDTO()
+
+	// Call the use case
+	job, err := controller.UseCase.Execute(c.Request.Context(), dto)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "PDF generation job queued successfully",
+		"job":     job,
+	})
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/requests"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// DeletePDFController handles the deletion of generated PDFs.
+type DeletePDFController struct {
+	UseCase use_cases.DeletePDFUseCase
+}
+
+// Handle processes the request to delete a PDF and purge its cached URLs.
+func (controller *DeletePDFController) Handle(c *gin.Context) {
+	// Get validated request from context
+	req := sharedMiddlewares.GetValidatedRequest(c).(*requests.DeletePDFRequest)
+
+	// Call the use case
+	result, err := controller.UseCase.Execute(c.Request.Context(), req.ToDTO())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "PDF deleted successfully",
+		"directory":          result.Directory,
+		"fileName":           result.FileName,
+		"purgedCacheEntries": result.PurgedCacheEntries,
+	})
+}
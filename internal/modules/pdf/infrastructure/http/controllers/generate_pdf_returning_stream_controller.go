@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/requests"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// GeneratePDFReturningStreamController handles the generation of a PDF and writes its bytes to the response.
+type GeneratePDFReturningStreamController struct {
+	UseCase use_cases.GeneratePDFReturningStreamUseCase
+}
+
+// Handle processes the request to generate a PDF and return its content.
+func (controller *GeneratePDFReturningStreamController) Handle(c *gin.Context) {
+	// Get validated request from context
+	req := sharedMiddlewares.GetValidatedRequest(c).(*requests.GeneratePDFReturningStreamRequest)
+
+	// Convert request to DTO
+	dto := req.ToDTO()
+
+	// Call the use case
+	pdf, err := controller.UseCase.Execute(c.Request.Context(), dto)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// Release the generated file once sent
+	defer func() {
+		_ = pdf.Reader.Close()
+	}()
+
+	contentDisposition := mime.FormatMediaType(req.GetDisposition(), map[string]string{
+		"filename": pdf.FileName,
+	})
+
+	c.DataFromReader(http.StatusOK, pdf.Size, "application/pdf", pdf.Reader, map[string]string{
+		"Content-Disposition": contentDisposition,
+		"X-Item-Cache-Hits":   strconv.Itoa(pdf.ItemCache.Hits),
+		"X-Item-Cache-Misses": strconv.Itoa(pdf.ItemCache.Misses),
+	})
+}
@@ -23,14 +23,25 @@ func (controller *GeneratePDFReturningURLController) Handle(c *gin.Context) {
 	dto := req.ToDTO()
 
 	// Call the use case
-	url, err := controller.UseCase.Execute(dto)
+	pdf, err := controller.UseCase.Execute(c.Request.Context(), dto)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "PDF generated successfully",
-		"url":     url,
-	})
+	response := gin.H{
+		"message":      "PDF generated successfully",
+		"url":          pdf.URL,
+		"hash":         pdf.Hash,
+		"deduplicated": pdf.Deduplicated,
+	}
+	// Only the rendered PDFs know how many items were reused
+	if pdf.ItemCache != nil {
+		response["itemCache"] = gin.H{
+			"hits":   pdf.ItemCache.Hits,
+			"misses": pdf.ItemCache.Misses,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPDFGenerationJobController handles the retrieval of asynchronous PDF generation jobs.
+type GetPDFGenerationJobController struct {
+	UseCase use_cases.GetPDFGenerationJobUseCase
+}
+
+// Handle processes the request to get the state of a PDF generation job.
+func (controller *GetPDFGenerationJobController) Handle(c *gin.Context) {
+	// Call the use case with the job ID from the path
+	job, err := controller.UseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "PDF generation job retrieved successfully",
+		"job":     job,
+	})
+}
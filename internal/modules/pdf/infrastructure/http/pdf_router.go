@@ -1,12 +1,17 @@
 package http
 
 import (
+	"time"
+
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/controllers"
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/requests"
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/implementations"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
 	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
 	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	templateImplementations "github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/implementations"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,12 +23,28 @@ func (pr *PDFRouter) RegisterRoutes(r *gin.RouterGroup) {
 	// Register the PDF routes
 	pdfGroup := r.Group("/pdf")
 
+	// Build the cache keys of the requests and cache their rendered PDFs
+	fingerprinter := fingerprint.RequestFingerprinter{
+		HashGenerator:    sharedImplementations.GetSha256HashGenerator(),
+		Version:          sharedInfrastructure.GetEnvironment().CacheKeyVersion,
+		DefaultRetention: sharedInfrastructure.GetEnvironment().FileRetentionSeconds,
+	}
+	renderCache := implementations.GetRenderCache()
+
 	// Generate PDF and return URL
 	generatePDFReturningURLUseCase := use_cases.GeneratePDFReturningURLUseCase{
-		PDFGenerator:    implementations.GetPDFGeneratorRod(),
-		CloudStorage:    sharedImplementations.GetS3CloudStorage(),
-		URLCacheStorage: sharedImplementations.GetRedisCacheStorage(),
-		HashGenerator:   sharedImplementations.GetXxHashGenerator(),
+		PDFGenerator:            implementations.GetPDFGeneratorRod(),
+		CloudStorage:            sharedImplementations.GetCloudStorage(),
+		URLCacheStorage:         sharedImplementations.GetUrlCacheStorage(),
+		Fingerprinter:           fingerprinter,
+		RenderCache:             renderCache,
+		TemplateStorage:         templateImplementations.GetTemplateStorage(),
+		TemplateEngine:          templateImplementations.GetHTMLTemplateEngine(),
+		MetricsRecorder:         sharedImplementations.GetPrometheusMetricsRecorder(),
+		FileExpirationStorage:   sharedImplementations.GetFileExpirationStorage(),
+		DeduplicatedFileStorage: sharedImplementations.GetDeduplicatedFileStorage(),
+		Coalescer:               implementations.GetRequestCoalescer(),
+		DefaultRetention:        sharedInfrastructure.GetEnvironment().FileRetentionSeconds,
 	}
 	generatePDFReturningURLController := &controllers.GeneratePDFReturningURLController{
 		UseCase: generatePDFReturningURLUseCase,
@@ -31,7 +52,81 @@ func (pr *PDFRouter) RegisterRoutes(r *gin.RouterGroup) {
 	pdfGroup.POST(
 		"/url",
 		sharedMiddlewares.AuthMiddleware(),
+		sharedMiddlewares.RequestTimeoutMiddleware(),
 		sharedMiddlewares.RequestValidationMiddleware(requests.GeneratePDFReturningURLRequest{}),
 		generatePDFReturningURLController.Handle,
 	)
+
+	// Delete a generated PDF and revoke its cached URLs
+	deletePDFController := &controllers.DeletePDFController{
+		UseCase: use_cases.DeletePDFUseCase{
+			CloudStorage:            sharedImplementations.GetCloudStorage(),
+			URLCacheStorage:         sharedImplementations.GetUrlCacheStorage(),
+			FileExpirationStorage:   sharedImplementations.GetFileExpirationStorage(),
+			DeduplicatedFileStorage: sharedImplementations.GetDeduplicatedFileStorage(),
+			RenderCache:             renderCache,
+		},
+	}
+	pdfGroup.DELETE(
+		"",
+		sharedMiddlewares.AuthMiddleware(),
+		sharedMiddlewares.RequestValidationMiddleware(requests.DeletePDFRequest{}),
+		deletePDFController.Handle,
+	)
+
+	// Generate PDF and return its content
+	generatePDFReturningStreamUseCase := use_cases.GeneratePDFReturningStreamUseCase{
+		PDFGenerator:    implementations.GetPDFGeneratorRod(),
+		TemplateStorage: templateImplementations.GetTemplateStorage(),
+		TemplateEngine:  templateImplementations.GetHTMLTemplateEngine(),
+		Fingerprinter:   fingerprinter,
+		RenderCache:     renderCache,
+	}
+	generatePDFReturningStreamController := &controllers.GeneratePDFReturningStreamController{
+		UseCase: generatePDFReturningStreamUseCase,
+	}
+	pdfGroup.POST(
+		"/stream",
+		sharedMiddlewares.AuthMiddleware(),
+		sharedMiddlewares.RequestTimeoutMiddleware(),
+		sharedMiddlewares.RequestValidationMiddleware(requests.GeneratePDFReturningStreamRequest{}),
+		generatePDFReturningStreamController.Handle,
+	)
+
+	// Queue PDF generation jobs and poll their state
+	deliverPDFGenerationJobWebhookUseCase := use_cases.DeliverPDFGenerationJobWebhookUseCase{
+		Fetcher:        sharedImplementations.GetHTTPFetcher(implementations.GetCallbackURLPolicy()),
+		JobStorage:     sharedImplementations.GetJobStorage(),
+		MaxAttempts:    sharedInfrastructure.GetEnvironment().WebhookMaxAttempts,
+		InitialBackoff: time.Duration(sharedInfrastructure.GetEnvironment().WebhookInitialBackoffMilliseconds) * time.Millisecond,
+	}
+	pdfJobWorkerPool := implementations.GetPDFJobWorkerPool(
+		sharedImplementations.GetJobStorage(),
+		&generatePDFReturningURLUseCase,
+		&deliverPDFGenerationJobWebhookUseCase,
+	)
+	createPDFGenerationJobController := &controllers.CreatePDFGenerationJobController{
+		UseCase: use_cases.CreatePDFGenerationJobUseCase{
+			JobStorage:        sharedImplementations.GetJobStorage(),
+			JobDispatcher:     pdfJobWorkerPool,
+			CallbackURLPolicy: implementations.GetCallbackURLPolicy(),
+		},
+	}
+	pdfGroup.POST(
+		"/jobs",
+		sharedMiddlewares.AuthMiddleware(),
+		sharedMiddlewares.RequestValidationMiddleware(requests.GeneratePDFReturningURLRequest{}),
+		createPDFGenerationJobController.Handle,
+	)
+
+	getPDFGenerationJobController := &controllers.GetPDFGenerationJobController{
+		UseCase: use_cases.GetPDFGenerationJobUseCase{
+			JobStorage: sharedImplementations.GetJobStorage(),
+		},
+	}
+	pdfGroup.GET(
+		"/jobs/:id",
+		sharedMiddlewares.AuthMiddleware(),
+		getPDFGenerationJobController.Handle,
+	)
 }
@@ -0,0 +1,21 @@
+package requests
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+)
+
+// DeletePDFRequest represents the request to delete a PDF, identified by its location or by the hash returned when it was generated
+type DeletePDFRequest struct {
+	Directory string `json:"directory,omitempty" validate:"required_without=Hash,excluded_with=Hash"`
+	FileName  string `json:"fileName,omitempty" validate:"required_without=Hash,excluded_with=Hash"`
+	Hash      string `json:"hash,omitempty"`
+}
+
+// ToDTO converts the request to a DeletePDFDTO that can be used by the use case
+func (r *DeletePDFRequest) ToDTO() *dto.DeletePDFDTO {
+	return &dto.DeletePDFDTO{
+		Directory: r.Directory,
+		FileName:  r.FileName,
+		Hash:      r.Hash,
+	}
+}
@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+)
+
+const (
+	DISPOSITION_ATTACHMENT = "attachment"
+	DISPOSITION_INLINE     = "inline"
+)
+
+// StreamConfig represents the general configuration for a streamed PDF
+type StreamConfig struct {
+	FileName    string  `json:"fileName" validate:"required"`
+	Disposition *string `json:"disposition,omitempty" validate:"omitempty,oneof=attachment inline"`
+}
+
+// GeneratePDFReturningStreamRequest represents the PDF generation request whose result is returned directly
+type GeneratePDFReturningStreamRequest struct {
+	Items  []PDFItem    `json:"items" validate:"required,dive"`
+	Config StreamConfig `json:"config" validate:"required"`
+}
+
+// GetDisposition returns the requested Content-Disposition type, defaulting to attachment
+func (r *GeneratePDFReturningStreamRequest) GetDisposition() string {
+	if r.Config.Disposition == nil {
+		return DISPOSITION_ATTACHMENT
+	}
+
+	return *r.Config.Disposition
+}
+
+// ToDTO converts the request to a PDFGenerationDTO that can be used by the use case
+func (r *GeneratePDFReturningStreamRequest) ToDTO() *dto.PDFGenerationDTO {
+	return &dto.PDFGenerationDTO{
+		Items: buildItems(r.Items),
+		Config: dto.GeneralConfig{
+			FileName: r.Config.FileName,
+		},
+	}
+}
@@ -1,7 +1,11 @@
 package requests
 
 import (
+	"mime"
+	"path"
+
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
 	"github.com/ysmood/gson"
 )
 
@@ -19,6 +23,15 @@ type PageRange struct {
 	End   int `json:"end,omitempty" validate:"omitempty,min=1,gtefield=Start"`
 }
 
+// WaitFor represents the conditions the page must meet before being printed
+type WaitFor struct {
+	Selector          *string `json:"selector,omitempty" validate:"omitempty,min=1"`
+	Expression        *string `json:"expression,omitempty" validate:"omitempty,min=1"`
+	ReadyFlag         *bool   `json:"readyFlag,omitempty"`
+	Fonts             *bool   `json:"fonts,omitempty"`
+	DelayMilliseconds *int    `json:"delayMilliseconds,omitempty" validate:"omitempty,min=0,max=60000"`
+}
+
 // ItemConfig represents the configuration for each PDF element
 type ItemConfig struct {
 	Orientation         *string     `json:"orientation,omitempty" validate:"omitempty,oneof=landscape portrait"`
@@ -30,20 +43,51 @@ type ItemConfig struct {
 	PageRanges          *PageRange  `json:"pageRanges,omitempty" validate:"omitempty"`
 	HeaderHTML          *string     `json:"headerHTML,omitempty"`
 	FooterHTML          *string     `json:"footerHTML,omitempty"`
+	WaitTimeoutSeconds  *int        `json:"waitTimeoutSeconds,omitempty" validate:"omitempty,min=1,max=300"`
+	WaitFor             *WaitFor    `json:"waitFor,omitempty" validate:"omitempty"`
+}
+
+// Cookie represents a cookie sent when rendering a remote URL
+type Cookie struct {
+	Name   string  `json:"name" validate:"required"`
+	Value  string  `json:"value"`
+	Domain *string `json:"domain,omitempty"`
+	Path   *string `json:"path,omitempty"`
 }
 
 // PDFItem represents an individual PDF generation item
 type PDFItem struct {
-	BodyHTML string      `json:"bodyHTML" validate:"required"`
-	Config   *ItemConfig `json:"config,omitempty" validate:"omitempty"`
+	BodyHTML        string            `json:"bodyHTML,omitempty" validate:"required_without_all=URL Template,excluded_with=URL Template"`
+	URL             *string           `json:"url,omitempty" validate:"omitempty,excluded_with=Template,http_url"`
+	Headers         map[string]string `json:"headers,omitempty" validate:"excluded_without=URL"`
+	Cookies         []Cookie          `json:"cookies,omitempty" validate:"excluded_without=URL,dive"`
+	Template        *string           `json:"template,omitempty" validate:"omitempty,min=1"`
+	TemplateVersion *int              `json:"templateVersion,omitempty" validate:"excluded_without=Template,omitempty,min=1"`
+	Data            map[string]any    `json:"data,omitempty" validate:"excluded_without=Template"`
+	Config          *ItemConfig       `json:"config,omitempty" validate:"omitempty"`
 }
 
 // GeneralConfig represents the general PDF configuration
 type GeneralConfig struct {
-	Directory       string `json:"directory" validate:"required"`
-	FileName        string `json:"fileName" validate:"required"`
-	PublicURLPrefix string `json:"publicURLPrefix,omitempty" validate:"required,http_url"`
-	Expiration      *int64 `json:"expiration,omitempty" validate:"omitempty,min=0"` // Expiration time in seconds
+	Directory       string  `json:"directory" validate:"required"`
+	FileName        string  `json:"fileName" validate:"required"`
+	PublicURLPrefix string  `json:"publicURLPrefix,omitempty" validate:"required_unless=URLMode presigned,omitempty,http_url"`
+	URLMode         string  `json:"urlMode,omitempty" validate:"omitempty,oneof=public presigned"`                 // Public by default
+	Expiration      *int64  `json:"expiration,omitempty" validate:"required_if=URLMode presigned,omitempty,min=0"` // Expiration time in seconds
+	Retention       *int64  `json:"retention,omitempty" validate:"omitempty,min=0"`                                // Seconds the file is kept, forever if 0 and the FILE_RETENTION_SECONDS default if nil
+	CallbackURL     *string `json:"callbackURL,omitempty" validate:"omitempty,http_url"`                           // Notified when an asynchronous job finishes
+	CallbackSecret  *string `json:"callbackSecret,omitempty" validate:"omitempty,min=16"`                          // Used to sign the callback body
+	Deduplicate     bool    `json:"deduplicate,omitempty"`                                                         // Copy an identical PDF stored by another request instead of rendering it again
+
+	// Options of the stored object, the backend defaults are used if empty
+	Metadata             map[string]string `json:"metadata,omitempty" validate:"omitempty,max=32,dive,keys,min=1,max=128,endkeys,max=1024"`
+	Tags                 map[string]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,keys,min=1,max=128,endkeys,max=256"`
+	CacheControl         string            `json:"cacheControl,omitempty" validate:"omitempty,max=256"`
+	Disposition          string            `json:"disposition,omitempty" validate:"omitempty,oneof=attachment inline"`
+	DownloadFileName     string            `json:"downloadFileName,omitempty" validate:"omitempty,max=256"` // Name suggested when downloading, the base of the file name by default
+	ServerSideEncryption string            `json:"serverSideEncryption,omitempty" validate:"omitempty,oneof=AES256 aws:kms"`
+	KMSKeyID             string            `json:"kmsKeyId,omitempty" validate:"excluded_unless=ServerSideEncryption aws:kms"`
+	StorageClass         string            `json:"storageClass,omitempty" validate:"omitempty,max=64"`
 }
 
 // GeneratePDFReturningURLRequest represents the complete PDF generation request
@@ -113,6 +157,7 @@ func buildItemConfig(config *ItemConfig) *dto.ItemConfig {
 		FooterHTML:          config.FooterHTML,
 		PrintBackground:     config.PrintBackground,
 		Scale:               config.Scale,
+		WaitTimeoutSeconds:  config.WaitTimeoutSeconds,
 	}
 
 	// Handle Size safely
@@ -138,29 +183,108 @@ func buildItemConfig(config *ItemConfig) *dto.ItemConfig {
 		}
 	}
 
+	// Handle WaitFor safely
+	if config.WaitFor != nil {
+		itemConfig.WaitFor = &dto.WaitFor{
+			Selector:          config.WaitFor.Selector,
+			Expression:        config.WaitFor.Expression,
+			ReadyFlag:         config.WaitFor.ReadyFlag,
+			Fonts:             config.WaitFor.Fonts,
+			DelayMilliseconds: config.WaitFor.DelayMilliseconds,
+		}
+	}
+
 	return itemConfig
 }
 
+// buildCookies converts the request cookies to domain cookies
+func buildCookies(requestCookies []Cookie) []dto.Cookie {
+	if requestCookies == nil {
+		return nil
+	}
+
+	cookies := make([]dto.Cookie, len(requestCookies))
+	for i, cookie := range requestCookies {
+		cookies[i] = dto.Cookie{
+			Name:   cookie.Name,
+			Value:  cookie.Value,
+			Domain: cookie.Domain,
+			Path:   cookie.Path,
+		}
+	}
+
+	return cookies
+}
+
+// buildItems converts the request items to domain items
+func buildItems(requestItems []PDFItem) []dto.PDFItem {
+	items := make([]dto.PDFItem, len(requestItems))
+
+	for i, item := range requestItems {
+		items[i] = dto.PDFItem{
+			BodyHTML:        item.BodyHTML,
+			URL:             item.URL,
+			Headers:         item.Headers,
+			Cookies:         buildCookies(item.Cookies),
+			Template:        item.Template,
+			TemplateVersion: item.TemplateVersion,
+			Data:            item.Data,
+			Config:          buildItemConfig(item.Config),
+		}
+	}
+
+	return items
+}
+
+// buildContentDisposition returns the Content-Disposition of the stored file, or an empty string to not set it
+func (c *GeneralConfig) buildContentDisposition() string {
+	if c.Disposition == "" && c.DownloadFileName == "" {
+		return ""
+	}
+
+	disposition := c.Disposition
+	if disposition == "" {
+		disposition = DISPOSITION_ATTACHMENT
+	}
+
+	downloadFileName := c.DownloadFileName
+	if downloadFileName == "" {
+		downloadFileName = path.Base(c.FileName)
+	}
+
+	return mime.FormatMediaType(disposition, map[string]string{
+		"filename": downloadFileName,
+	})
+}
+
 // ToDTO converts the request to a PDFGenerationDTO that can be used by the use case
 func (r *GeneratePDFReturningURLRequest) ToDTO() *dto.PDFGenerationDTO {
 	config := dto.GeneralConfig{
 		Directory:       r.Config.Directory,
 		FileName:        r.Config.FileName,
 		PublicURLPrefix: r.Config.PublicURLPrefix,
+		URLMode:         r.Config.URLMode,
 		Expiration:      r.Config.Expiration,
-	}
+		Retention:       r.Config.Retention,
+		CallbackURL:     r.Config.CallbackURL,
+		CallbackSecret:  r.Config.CallbackSecret,
+		Deduplicate:     r.Config.Deduplicate,
 
-	items := make([]dto.PDFItem, len(r.Items))
+		Metadata:             r.Config.Metadata,
+		Tags:                 r.Config.Tags,
+		CacheControl:         r.Config.CacheControl,
+		ContentDisposition:   r.Config.buildContentDisposition(),
+		ServerSideEncryption: r.Config.ServerSideEncryption,
+		KMSKeyID:             r.Config.KMSKeyID,
+		StorageClass:         r.Config.StorageClass,
+	}
 
-	for i, item := range r.Items {
-		items[i] = dto.PDFItem{
-			BodyHTML: item.BodyHTML,
-			Config:   buildItemConfig(item.Config),
-		}
+	if config.URLMode == "" {
+		config.URLMode = sharedDefinitions.DOWNLOAD_URL_MODE_PUBLIC
 	}
 
 	return &dto.PDFGenerationDTO{
-		Items:  items,
+		Items:  buildItems(r.Items),
 		Config: config,
 	}
 }
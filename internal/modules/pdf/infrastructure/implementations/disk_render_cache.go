@@ -0,0 +1,306 @@
+package implementations
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// DISK_RENDER_CACHE_FILE_EXTENSION is the extension of the cached PDFs, named "{sha256 of key}.{page count}.pdf"
+const DISK_RENDER_CACHE_FILE_EXTENSION = ".pdf"
+
+// diskRenderCacheEntry is a PDF cached on disk
+type diskRenderCacheEntry struct {
+	id        string    // SHA-256 of the key, so any key is a valid file name
+	pageCount int       // Part of the file name, so it survives restarts
+	size      int64     // Size of the file in bytes
+	createdAt time.Time // Time the file was written, to expire it
+}
+
+// fileName returns the name of the file holding the PDF
+func (e *diskRenderCacheEntry) fileName() string {
+	return fmt.Sprintf("%s.%d%s", e.id, e.pageCount, DISK_RENDER_CACHE_FILE_EXTENSION)
+}
+
+// DiskRenderCache implements the RenderCache interface with files in a directory, evicting the least recently
+// used ones once their total size goes beyond the max size. The index is kept in memory and rebuilt from the
+// directory on startup, ordered by the time the files were written.
+type DiskRenderCache struct {
+	rootPath     string
+	maxSize      int64
+	maxEntrySize int64
+	expiration   time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element // Elements of the recency list, indexed by entry ID
+	recency *list.List               // Entries from the most to the least recently used
+	size    int64                    // Total size of the cached files
+}
+
+var (
+	diskRenderCache     *DiskRenderCache
+	diskRenderCacheOnce sync.Once
+)
+
+// NewDiskRenderCache creates a DiskRenderCache storing the PDFs under the root path, indexing the ones already there.
+// The PDFs up to the max entry size are cached for the given time, forever if 0.
+func NewDiskRenderCache(rootPath string, maxSize int64, maxEntrySize int64, expiration time.Duration) (*DiskRenderCache, error) {
+	if err := os.MkdirAll(rootPath, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating render cache directory: %w", err)
+	}
+
+	cache := &DiskRenderCache{
+		rootPath:     rootPath,
+		maxSize:      maxSize,
+		maxEntrySize: maxEntrySize,
+		expiration:   expiration,
+		entries:      make(map[string]*list.Element),
+		recency:      list.New(),
+	}
+	if err := cache.loadEntries(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// GetDiskRenderCache returns a singleton instance of DiskRenderCache configured from the environment
+func GetDiskRenderCache() definitions.RenderCache {
+	diskRenderCacheOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		cache, err := NewDiskRenderCache(
+			env.RenderCacheDiskPath,
+			int64(env.RenderCacheDiskMaxSizeMb)*1024*1024,
+			int64(env.RenderCacheMaxEntrySizeMb)*1024*1024,
+			time.Duration(env.RenderCacheTTLSeconds)*time.Second,
+		)
+		if err != nil {
+			panic("Unable to create disk render cache: " + err.Error())
+		}
+
+		sharedUtilities.GetLogger().
+			WithField("path", env.RenderCacheDiskPath).
+			WithField("entries", len(cache.entries)).
+			Info("Disk render cache initialized")
+
+		diskRenderCache = cache
+	})
+
+	return diskRenderCache
+}
+
+// loadEntries indexes the cached PDFs in the root path, removing the leftovers of interrupted writes
+func (c *DiskRenderCache) loadEntries() error {
+	dirEntries, err := os.ReadDir(c.rootPath)
+	if err != nil {
+		return fmt.Errorf("error listing render cache directory: %w", err)
+	}
+
+	var entries []*diskRenderCacheEntry
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		if strings.HasSuffix(dirEntry.Name(), ".tmp") {
+			_ = os.Remove(filepath.Join(c.rootPath, dirEntry.Name()))
+			continue
+		}
+
+		entry, isCachedPDF := parseDiskRenderCacheFileName(dirEntry.Name())
+		if !isCachedPDF {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entry.size = info.Size()
+		entry.createdAt = info.ModTime()
+		entries = append(entries, entry)
+	}
+
+	// The most recently written files are the most recently used ones
+	slices.SortFunc(entries, func(a, b *diskRenderCacheEntry) int {
+		return b.createdAt.Compare(a.createdAt)
+	})
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, entry := range entries {
+		// A key is cached once, so only its newest file is kept
+		if _, isIndexed := c.entries[entry.id]; isIndexed {
+			_ = os.Remove(filepath.Join(c.rootPath, entry.fileName()))
+			continue
+		}
+
+		c.entries[entry.id] = c.recency.PushBack(entry)
+		c.size += entry.size
+	}
+	c.evict()
+
+	return nil
+}
+
+// parseDiskRenderCacheFileName returns the entry of the cached PDF file, if the name is one
+func parseDiskRenderCacheFileName(name string) (*diskRenderCacheEntry, bool) {
+	base, isPDF := strings.CutSuffix(name, DISK_RENDER_CACHE_FILE_EXTENSION)
+	if !isPDF {
+		return nil, false
+	}
+
+	id, rawPageCount, found := strings.Cut(base, ".")
+	if !found || len(id) != sha256.Size*2 {
+		return nil, false
+	}
+
+	pageCount, err := strconv.Atoi(rawPageCount)
+	if err != nil {
+		return nil, false
+	}
+
+	return &diskRenderCacheEntry{id: id, pageCount: pageCount}, true
+}
+
+// getEntryID returns the ID of the entry of the key
+func getEntryID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached PDF, nil if the key is not cached or its PDF expired
+func (c *DiskRenderCache) Get(ctx context.Context, key string) (*dto.CachedPDF, error) {
+	id := getEntryID(key)
+
+	c.mutex.Lock()
+	element, isCached := c.entries[id]
+	if !isCached {
+		c.mutex.Unlock()
+		return nil, nil
+	}
+	entry := element.Value.(*diskRenderCacheEntry)
+	if c.expiration > 0 && time.Since(entry.createdAt) > c.expiration {
+		c.remove(element)
+		c.mutex.Unlock()
+		return nil, nil
+	}
+	c.recency.MoveToFront(element)
+	c.mutex.Unlock()
+
+	// The file can be evicted while it is read, which is a miss as well
+	content, err := os.ReadFile(filepath.Join(c.rootPath, entry.fileName()))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cached PDF: %w", err)
+	}
+
+	return &dto.CachedPDF{Content: content, PageCount: entry.pageCount}, nil
+}
+
+// Set caches the PDF under the key, skipping it if it is larger than the max entry size,
+// and evicts the least recently used PDFs until the cache fits its max size
+func (c *DiskRenderCache) Set(ctx context.Context, key string, pdf *dto.CachedPDF) error {
+	size := int64(len(pdf.Content))
+	if size > c.maxEntrySize || size > c.maxSize {
+		return nil
+	}
+
+	entry := &diskRenderCacheEntry{
+		id:        getEntryID(key),
+		pageCount: pdf.PageCount,
+		size:      size,
+		createdAt: time.Now(),
+	}
+
+	// Write to a temporary file first, so readers never see a partial PDF
+	temporaryFile, err := os.CreateTemp(c.rootPath, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating cached PDF file: %w", err)
+	}
+	_, writeErr := temporaryFile.Write(pdf.Content)
+	closeErr := temporaryFile.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(temporaryFile.Name())
+		return fmt.Errorf("error writing cached PDF file: %w", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Replace the previous PDF of the key, which may have been written with another page count
+	if element, isCached := c.entries[entry.id]; isCached {
+		c.remove(element)
+	}
+
+	if err := os.Rename(temporaryFile.Name(), filepath.Join(c.rootPath, entry.fileName())); err != nil {
+		_ = os.Remove(temporaryFile.Name())
+		return fmt.Errorf("error moving cached PDF file: %w", err)
+	}
+
+	c.entries[entry.id] = c.recency.PushFront(entry)
+	c.size += entry.size
+	c.evict()
+
+	return nil
+}
+
+// Delete removes the PDF cached under the key
+func (c *DiskRenderCache) Delete(ctx context.Context, key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, isCached := c.entries[getEntryID(key)]; isCached {
+		c.remove(element)
+	}
+
+	return nil
+}
+
+// MaxEntrySize returns the size in bytes of the largest PDF that can be cached
+func (c *DiskRenderCache) MaxEntrySize() int64 {
+	return c.maxEntrySize
+}
+
+// evict removes the least recently used PDFs until the cache fits its max size.
+// The caller must hold the mutex.
+func (c *DiskRenderCache) evict() {
+	for c.size > c.maxSize {
+		c.remove(c.recency.Back())
+	}
+}
+
+// remove deletes the PDF of the element and unindexes it.
+// The caller must hold the mutex.
+func (c *DiskRenderCache) remove(element *list.Element) {
+	entry := c.recency.Remove(element).(*diskRenderCacheEntry)
+	delete(c.entries, entry.id)
+	c.size -= entry.size
+
+	if err := os.Remove(filepath.Join(c.rootPath, entry.fileName())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("file", entry.fileName()).
+			Warn("Failed to remove cached PDF")
+	}
+}
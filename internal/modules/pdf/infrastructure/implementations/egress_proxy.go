@@ -0,0 +1,210 @@
+package implementations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// EGRESS_PROXY_DIAL_TIMEOUT is the maximum time to connect to the hosts requested through the proxy
+const EGRESS_PROXY_DIAL_TIMEOUT = 30 * time.Second
+
+// errPrivateAddress is returned when a restricted proxy is asked to connect to a private network
+var errPrivateAddress = errors.New("the address belongs to a private network")
+
+// EgressProxy is a local HTTP proxy a browser page reaches the network through.
+// While restricted, it refuses to connect to private, loopback and link-local IPs, checking the
+// address it actually connects to. As the browser does not resolve the hosts when it uses a proxy,
+// a host cannot point to a public IP when its URL is checked and to a private one when the page
+// loads it (DNS rebinding).
+type EgressProxy struct {
+	listener   net.Listener
+	server     *http.Server
+	dialer     *net.Dialer
+	transport  *http.Transport
+	forwarder  *httputil.ReverseProxy
+	restricted atomic.Bool
+
+	mutex   sync.Mutex
+	tunnels map[net.Conn]struct{} // Browser connections of the open CONNECT tunnels
+}
+
+// NewEgressProxy starts an unrestricted proxy listening on a random loopback port
+func NewEgressProxy() (*EgressProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("error listening for the egress proxy: %w", err)
+	}
+
+	proxy := &EgressProxy{
+		listener: listener,
+		tunnels:  make(map[net.Conn]struct{}),
+	}
+	proxy.dialer = &net.Dialer{
+		Timeout: EGRESS_PROXY_DIAL_TIMEOUT,
+		Control: proxy.checkAddress,
+	}
+	// Do not keep the connections, as they could be reused once the proxy is restricted
+	proxy.transport = &http.Transport{
+		DialContext:       proxy.dialer.DialContext,
+		DisableKeepAlives: true,
+	}
+	proxy.forwarder = &httputil.ReverseProxy{
+		Rewrite:      func(*httputil.ProxyRequest) {},
+		Transport:    proxy.transport,
+		ErrorHandler: proxy.handleError,
+	}
+	proxy.server = &http.Server{
+		Handler:           proxy,
+		ReadHeaderTimeout: EGRESS_PROXY_DIAL_TIMEOUT,
+	}
+
+	go func() {
+		_ = proxy.server.Serve(listener)
+	}()
+
+	return proxy, nil
+}
+
+// URL returns the URL the browser must use to reach the proxy
+func (p *EgressProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Restrict sets whether the proxy refuses to connect to private networks.
+// The open tunnels are closed, so the ones opened before cannot be reused.
+func (p *EgressProxy) Restrict(restricted bool) {
+	p.restricted.Store(restricted)
+	p.closeTunnels()
+}
+
+// Close stops the proxy and closes all its connections
+func (p *EgressProxy) Close() error {
+	err := p.server.Close()
+	p.closeTunnels()
+	return err
+}
+
+// ServeHTTP tunnels the CONNECT requests and forwards any other request to its absolute URL
+func (p *EgressProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+
+	if !r.URL.IsAbs() {
+		http.Error(w, "The request URL must be absolute", http.StatusBadRequest)
+		return
+	}
+
+	p.forwarder.ServeHTTP(w, r)
+}
+
+// checkAddress refuses the private addresses while the proxy is restricted.
+// It is called once the host is resolved, right before connecting to the address.
+func (p *EgressProxy) checkAddress(network string, address string, _ syscall.RawConn) error {
+	if !p.restricted.Load() {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errPrivateAddress
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || isPrivateIP(ip) {
+		return errPrivateAddress
+	}
+
+	return nil
+}
+
+// handleError answers with a Forbidden status the requests to private networks and Bad Gateway otherwise
+func (p *EgressProxy) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadGateway
+	if errors.Is(err, errPrivateAddress) {
+		status = http.StatusForbidden
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+// tunnel connects the browser to the requested host, copying the bytes in both directions
+func (p *EgressProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	restricted := p.restricted.Load()
+	upstream, err := p.dialer.DialContext(r.Context(), "tcp", r.Host)
+	if err != nil {
+		p.handleError(w, r, err)
+		return
+	}
+	defer upstream.Close()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Tunnels are not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	// Do not keep a tunnel opened before the proxy was restricted
+	if !p.trackTunnel(client, restricted) {
+		return
+	}
+	defer p.untrackTunnel(client)
+
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return
+	}
+
+	// The buffered reader may already hold the first bytes sent by the browser
+	go func() {
+		_, _ = io.Copy(upstream, buffered)
+		_ = upstream.Close()
+	}()
+	_, _ = io.Copy(client, upstream)
+}
+
+// trackTunnel records the tunnel so it is closed when the restriction changes.
+// It returns false if the proxy was restricted after the tunnel was connected.
+func (p *EgressProxy) trackTunnel(client net.Conn, restricted bool) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.restricted.Load() && !restricted {
+		return false
+	}
+
+	p.tunnels[client] = struct{}{}
+	return true
+}
+
+// untrackTunnel forgets the tunnel once it is closed
+func (p *EgressProxy) untrackTunnel(client net.Conn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.tunnels, client)
+}
+
+// closeTunnels closes all the open tunnels
+func (p *EgressProxy) closeTunnels() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for client := range p.tunnels {
+		_ = client.Close()
+	}
+}
@@ -0,0 +1,73 @@
+package implementations
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newProxiedClient returns a client sending the requests through the proxy
+func newProxiedClient(t *testing.T, proxy *EgressProxy, transport *http.Transport) *http.Client {
+	proxyURL, err := url.Parse(proxy.URL())
+	assert.NoError(t, err, "Proxy URL should be valid")
+
+	transport.Proxy = http.ProxyURL(proxyURL)
+	transport.DisableKeepAlives = true
+	return &http.Client{Transport: transport}
+}
+
+// TestEgressProxy_Forward tests the plain HTTP requests reach private networks only while the proxy is not restricted
+func TestEgressProxy_Forward(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	proxy, err := NewEgressProxy()
+	if !assert.NoError(t, err, "Starting the proxy should succeed") {
+		return
+	}
+	defer proxy.Close()
+	client := newProxiedClient(t, proxy, &http.Transport{})
+
+	response, err := client.Get(server.URL)
+	if assert.NoError(t, err, "Request through the unrestricted proxy should succeed") {
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode, "Unrestricted proxy should reach the loopback server")
+	}
+
+	proxy.Restrict(true)
+	response, err = client.Get(server.URL)
+	if assert.NoError(t, err, "Request through the restricted proxy should be answered") {
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusForbidden, response.StatusCode, "Restricted proxy should refuse the loopback server")
+	}
+}
+
+// TestEgressProxy_Tunnel tests the CONNECT tunnels reach private networks only while the proxy is not restricted
+func TestEgressProxy_Tunnel(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	proxy, err := NewEgressProxy()
+	if !assert.NoError(t, err, "Starting the proxy should succeed") {
+		return
+	}
+	defer proxy.Close()
+	client := newProxiedClient(t, proxy, server.Client().Transport.(*http.Transport).Clone())
+
+	response, err := client.Get(server.URL)
+	if assert.NoError(t, err, "Tunnel through the unrestricted proxy should succeed") {
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode, "Unrestricted proxy should reach the loopback server")
+	}
+
+	proxy.Restrict(true)
+	_, err = client.Get(server.URL)
+	assert.Error(t, err, "Restricted proxy should refuse to tunnel to the loopback server")
+}
@@ -5,24 +5,35 @@ package implementations
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"slices"
 
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
 	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	pdfErrors "github.com/PChaparro/serpentarius/internal/modules/pdf/domain/errors"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
 	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
 	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	pdfProcessingAPI "github.com/pdfcpu/pdfcpu/pkg/api"
 	pdfProcessingModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Variables defining the resource limits for the browser pool
@@ -42,6 +53,7 @@ var (
 type PageWithBrowser struct {
 	Page    *rod.Page    // The browser page instance for rendering content
 	Browser *rod.Browser // The parent browser instance that owns this page
+	Proxy   *EgressProxy // The proxy the page reaches the network through, nil if private networks are allowed
 }
 
 // PageWithTimeout extends PageWithBrowser to include timeout management.
@@ -50,6 +62,7 @@ type PageWithTimeout struct {
 	PageWithBrowser
 	Timer     *time.Timer // Timer to track inactivity and trigger cleanup
 	InUse     bool        // Whether this page is currently being used
+	IdleSince time.Time   // When the page was returned to the pool for the last time
 	BrowserID string      // Unique identifier for the browser
 }
 
@@ -65,11 +78,14 @@ type BrowserInfo struct {
 // to control headless Chrome browsers. It dynamically manages browser and page resources,
 // creating them on-demand and cleaning them up after periods of inactivity.
 type PDFGeneratorRod struct {
-	mutex          sync.Mutex              // Mutex to protect concurrent access to the generator state
-	browsers       map[string]*BrowserInfo // Map of browser instances by their unique IDs
-	availablePages []*PageWithTimeout      // List of available pages
-	waitingQueue   []chan *PageWithTimeout // Channels for clients waiting for a page
-	pageWaitGroup  sync.WaitGroup          // Used to track when pages are being used
+	mutex          sync.Mutex                        // Mutex to protect concurrent access to the generator state
+	browsers       map[string]*BrowserInfo           // Map of browser instances by their unique IDs
+	availablePages []*PageWithTimeout                // List of available pages
+	waitingQueue   []chan *PageWithTimeout           // Channels for clients waiting for a page
+	pageWaitGroup  sync.WaitGroup                    // Used to track when pages are being used
+	metrics        sharedDefinitions.MetricsRecorder // Records the rendering and pool metrics
+	itemCache      definitions.RenderCache           // Caches the PDFs of the single items, nil if they are not cached
+	fingerprinter  fingerprint.RequestFingerprinter  // Builds the render cache keys of the items
 }
 
 // Global singleton instance and initialization control
@@ -83,12 +99,22 @@ var pdfGeneratorOnce sync.Once
 // managing the browser pool across the application.
 func GetPDFGeneratorRod() *PDFGeneratorRod {
 	pdfGeneratorOnce.Do(func() {
-		pdfGeneratorInstance = &PDFGeneratorRod{
-			browsers:       make(map[string]*BrowserInfo),
-			availablePages: make([]*PageWithTimeout, 0),
-			waitingQueue:   make([]chan *PageWithTimeout, 0),
+		env := sharedInfrastructure.GetEnvironment()
+
+		var itemCache definitions.RenderCache
+		if env.RenderCacheItems {
+			itemCache = GetRenderCache()
 		}
 
+		pdfGeneratorInstance = NewPDFGeneratorRod(
+			sharedImplementations.GetPrometheusMetricsRecorder(),
+			itemCache,
+			fingerprint.RequestFingerprinter{
+				HashGenerator: sharedImplementations.GetSha256HashGenerator(),
+				Version:       env.CacheKeyVersion,
+			},
+		)
+
 		// Set up a finalizer to clean up resources when the generator is garbage collected
 		runtime.SetFinalizer(pdfGeneratorInstance, func(p *PDFGeneratorRod) {
 			p.ReleaseBrowserPool()
@@ -98,20 +124,43 @@ func GetPDFGeneratorRod() *PDFGeneratorRod {
 	return pdfGeneratorInstance
 }
 
+// NewPDFGeneratorRod creates a PDFGeneratorRod without browsers, which are launched on demand.
+// The PDFs of the items are reused from the item cache when it is not nil.
+func NewPDFGeneratorRod(
+	metrics sharedDefinitions.MetricsRecorder,
+	itemCache definitions.RenderCache,
+	fingerprinter fingerprint.RequestFingerprinter,
+) *PDFGeneratorRod {
+	return &PDFGeneratorRod{
+		browsers:       make(map[string]*BrowserInfo),
+		availablePages: make([]*PageWithTimeout, 0),
+		waitingQueue:   make([]chan *PageWithTimeout, 0),
+		metrics:        metrics,
+		itemCache:      itemCache,
+		fingerprinter:  fingerprinter,
+	}
+}
+
 // createBrowser launches a new browser instance and adds it to the pool
 func (p *PDFGeneratorRod) createBrowser() (*BrowserInfo, error) {
 	// Launch a new browser instance with optimized settings for headless PDF generation
-	launcherURL := launcher.New().
+	launcherURL, err := launcher.New().
 		Bin(sharedInfrastructure.GetEnvironment().ChromiumBinaryPath). // Use the configured Chromium binary
 		Headless(true).                                                // Run in headless mode (no UI)
 		Leakless(true).                                                // Ensure process cleanup on unexpected termination
 		Set("disable-gpu", "1").                                       // Disable GPU acceleration
 		Set("disable-dev-shm-usage", "1").                             // Avoid using shared memory
 		Set("disable-extensions", "1").                                // Disable browser extensions
-		MustLaunch()
+		Launch()
+	if err != nil {
+		return nil, fmt.Errorf("error launching browser: %w", err)
+	}
 
 	// Connect to the launched browser
-	browser := rod.New().ControlURL(launcherURL).MustConnect()
+	browser := rod.New().ControlURL(launcherURL)
+	if err := browser.Connect(); err != nil {
+		return nil, fmt.Errorf("error connecting to browser: %w", err)
+	}
 
 	// Generate a unique ID for this browser
 	browserID := sharedInfrastructure.GenerateXID()
@@ -126,6 +175,7 @@ func (p *PDFGeneratorRod) createBrowser() (*BrowserInfo, error) {
 
 	// Store in browsers map
 	p.browsers[browserID] = info
+	p.metrics.IncBrowserLaunch()
 
 	sharedUtilities.GetLogger().
 		WithField("browser_id", browserID).
@@ -136,14 +186,42 @@ func (p *PDFGeneratorRod) createBrowser() (*BrowserInfo, error) {
 
 // createPage creates a new page in the given browser
 func (p *PDFGeneratorRod) createPage(browserInfo *BrowserInfo) (*PageWithTimeout, error) {
+	// Route the page through its own proxy, so it can be restricted while rendering remote URLs.
+	// The loopback hosts are proxied as well, as they are not by default.
+	contextOptions := proto.TargetCreateBrowserContext{}
+	var proxy *EgressProxy
+	if !GetRemoteURLPolicy().allowPrivateNetworks {
+		var err error
+		proxy, err = NewEgressProxy()
+		if err != nil {
+			return nil, err
+		}
+
+		contextOptions.ProxyServer = proxy.URL()
+		contextOptions.ProxyBypassList = "<-loopback>"
+	}
+
 	// Create a new incognito page
-	page := browserInfo.Browser.MustIncognito().MustPage()
+	browserContext, err := contextOptions.Call(browserInfo.Browser)
+	if err != nil {
+		closeEgressProxy(proxy)
+		return nil, fmt.Errorf("error creating incognito context: %w", err)
+	}
+	incognito := *browserInfo.Browser
+	incognito.BrowserContextID = browserContext.BrowserContextID
+
+	page, err := incognito.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		closeEgressProxy(proxy)
+		return nil, fmt.Errorf("error creating page: %w", err)
+	}
 
 	// Create PageWithTimeout
 	pwt := &PageWithTimeout{
 		PageWithBrowser: PageWithBrowser{
 			Page:    page,
 			Browser: browserInfo.Browser,
+			Proxy:   proxy,
 		},
 		InUse:     false,
 		BrowserID: browserInfo.ID,
@@ -160,8 +238,10 @@ func (p *PDFGeneratorRod) createPage(browserInfo *BrowserInfo) (*PageWithTimeout
 	return pwt, nil
 }
 
-// findOrCreateAvailablePage finds an available page or creates a new one if needed
-func (p *PDFGeneratorRod) findOrCreateAvailablePage() (*PageWithTimeout, error) {
+// findOrCreateAvailablePage finds an available page or creates a new one if needed.
+// If the context is done while waiting for a page, the context error is returned along with
+// the page handed to the waiter meanwhile, if any, so the caller can return it to the pool.
+func (p *PDFGeneratorRod) findOrCreateAvailablePage(ctx context.Context) (*PageWithTimeout, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
@@ -220,6 +300,8 @@ func (p *PDFGeneratorRod) findOrCreateAvailablePage() (*PageWithTimeout, error)
 				WithError(err).
 				Error("Failed to create page in new browser")
 
+			// Do not keep a browser without pages, as it would never be closed
+			p.closeBrowser(browserInfo)
 			return nil, err
 		}
 
@@ -234,10 +316,31 @@ func (p *PDFGeneratorRod) findOrCreateAvailablePage() (*PageWithTimeout, error)
 
 	// Release the lock while waiting
 	p.mutex.Unlock()
+	select {
+	case page, ok := <-pageChannel:
+		p.mutex.Lock()
+		if !ok {
+			return nil, errors.New("the browser pool was released while waiting for a page")
+		}
+
+		return page, nil
+	case <-ctx.Done():
+		p.mutex.Lock()
+	}
+
+	// Stop waiting if no page was handed to us yet
+	waiterIdx := slices.Index(p.waitingQueue, pageChannel)
+	if waiterIdx != -1 {
+		p.waitingQueue = slices.Delete(p.waitingQueue, waiterIdx, waiterIdx+1)
+		return nil, ctx.Err()
+	}
+
+	// A page was handed to us meanwhile, it is sent right after leaving the queue
+	p.mutex.Unlock()
 	page := <-pageChannel
 	p.mutex.Lock()
 
-	return page, nil
+	return page, ctx.Err()
 }
 
 // startPageTimer starts a timer to close the page after inactivity
@@ -251,6 +354,7 @@ func (p *PDFGeneratorRod) startPageTimer(page *PageWithTimeout) {
 	}
 
 	// Start a new timer
+	page.IdleSince = time.Now()
 	page.Timer = time.AfterFunc(PageIdleTimeout, func() {
 		p.closeIdlePage(page)
 	})
@@ -280,7 +384,7 @@ func (p *PDFGeneratorRod) closeIdlePage(page *PageWithTimeout) {
 		p.availablePages = slices.Delete(p.availablePages, foundIdx, foundIdx+1)
 
 		// Close the page
-		page.Page.MustClose()
+		p.closePage(page.BrowserID, &page.PageWithBrowser)
 
 		// Update browser info
 		browserInfo := p.browsers[page.BrowserID]
@@ -296,8 +400,7 @@ func (p *PDFGeneratorRod) closeIdlePage(page *PageWithTimeout) {
 
 		// If this was the last page, close the browser too
 		if browserInfo.PageCount == 0 {
-			browserInfo.Browser.MustClose()
-			delete(p.browsers, page.BrowserID)
+			p.closeBrowser(browserInfo)
 			sharedUtilities.GetLogger().
 				WithField("browser_id", page.BrowserID).
 				Info("Closed idle browser instance")
@@ -309,25 +412,88 @@ func (p *PDFGeneratorRod) closeIdlePage(page *PageWithTimeout) {
 	}
 }
 
+// closePage closes the page and its proxy, logging any error as the page is discarded anyway
+func (p *PDFGeneratorRod) closePage(browserID string, page *PageWithBrowser) {
+	if err := page.Page.Close(); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("browser_id", browserID).
+			Warn("Failed to close page")
+	}
+
+	closeEgressProxy(page.Proxy)
+}
+
+// closeEgressProxy closes the proxy of a page if any, logging any error as the proxy is discarded anyway
+func closeEgressProxy(proxy *EgressProxy) {
+	if proxy == nil {
+		return
+	}
+
+	if err := proxy.Close(); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			Warn("Failed to close page proxy")
+	}
+}
+
+// closeBrowser closes the browser and removes it from the pool.
+// The caller must hold the mutex.
+func (p *PDFGeneratorRod) closeBrowser(browserInfo *BrowserInfo) {
+	if err := browserInfo.Browser.Close(); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("browser_id", browserInfo.ID).
+			Warn("Failed to close browser")
+	}
+
+	delete(p.browsers, browserInfo.ID)
+	p.metrics.IncBrowserClose()
+}
+
 // RequestPage retrieves an available page or creates a new one.
-// This method will block if all allowed resources are in use until a page becomes available.
-// The caller is responsible for returning the page to the pool after use.
-func (p *PDFGeneratorRod) RequestPage() *PageWithBrowser {
+// This method will block if all allowed resources are in use until a page becomes available
+// or the context is done. The caller is responsible for returning the page to the pool after use.
+func (p *PDFGeneratorRod) RequestPage(ctx context.Context) (*PageWithBrowser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	p.pageWaitGroup.Add(1)
 
 	// Get a page (available or new)
-	page, err := p.findOrCreateAvailablePage()
+	waitStart := time.Now()
+	page, err := p.findOrCreateAvailablePage(ctx)
+	p.metrics.ObservePoolWait(time.Since(waitStart))
+
+	// Convert to the interface expected by existing code
+	var pwb *PageWithBrowser
+	if page != nil {
+		pwb = &PageWithBrowser{
+			Page:    page.Page,
+			Browser: page.Browser,
+			Proxy:   page.Proxy,
+		}
+	}
+
 	if err != nil {
 		sharedUtilities.GetLogger().WithError(err).Error("Failed to get page")
-		p.pageWaitGroup.Done()
-		return nil
-	}
 
-	// Convert to the interface expected by existing code
-	return &PageWithBrowser{
-		Page:    page.Page,
-		Browser: page.Browser,
+		// Give back the page received after the context was done, if any
+		if pwb != nil {
+			p.ReturnPage(pwb)
+		} else {
+			p.pageWaitGroup.Done()
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		return nil, pdfErrors.NewBrowserUnavailableError(err.Error())
 	}
+
+	return pwb, nil
 }
 
 // ReturnPage returns a page to the pool and starts its inactivity timer.
@@ -352,7 +518,7 @@ func (p *PDFGeneratorRod) ReturnPage(pwb *PageWithBrowser) {
 
 	// If the page is not found, just close it
 	if page == nil {
-		pwb.Page.MustClose()
+		p.closePage("", pwb)
 		p.mutex.Unlock()
 		return
 	}
@@ -399,12 +565,11 @@ func (p *PDFGeneratorRod) ReleaseBrowserPool() {
 				page.Timer.Stop()
 			}
 			// Close each page
-			page.Page.MustClose()
+			p.closePage(id, &page.PageWithBrowser)
 		}
 
 		// Close the browser
-		browserInfo.Browser.MustClose()
-		delete(p.browsers, id)
+		p.closeBrowser(browserInfo)
 	}
 
 	// Clear pages
@@ -419,6 +584,47 @@ func (p *PDFGeneratorRod) ReleaseBrowserPool() {
 	sharedUtilities.GetLogger().Info("PDF generator browser pool cleaned up")
 }
 
+// Stats returns a snapshot of the browser pool.
+// Available pages report when they became idle and when their idle timer will close them.
+func (p *PDFGeneratorRod) Stats() dto.BrowserPoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	browsers := make([]dto.BrowserStats, 0, len(p.browsers))
+	for _, browserInfo := range p.browsers {
+		pages := make([]dto.BrowserPageStats, len(browserInfo.Pages))
+		for idx, page := range browserInfo.Pages {
+			pages[idx].InUse = page.InUse
+
+			if !page.InUse && !page.IdleSince.IsZero() {
+				idleSince := page.IdleSince
+				closesAt := idleSince.Add(PageIdleTimeout)
+				pages[idx].IdleSince = &idleSince
+				pages[idx].ClosesAt = &closesAt
+			}
+		}
+
+		browsers = append(browsers, dto.BrowserStats{
+			ID:    browserInfo.ID,
+			Pages: pages,
+		})
+	}
+
+	// Sort the browsers to return a stable snapshot
+	slices.SortFunc(browsers, func(a, b dto.BrowserStats) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return dto.BrowserPoolStats{
+		Browsers:           browsers,
+		MaxBrowsers:        MaxBrowsers,
+		MaxPagesPerBrowser: MaxPagesPerBrowser,
+		AvailablePages:     len(p.availablePages),
+		WaitingQueueLength: len(p.waitingQueue),
+		PageIdleTimeout:    PageIdleTimeout,
+	}
+}
+
 // buildPDFOptions converts a configuration object from the domain DTO into Chrome's PDF print options.
 // It applies all specified configuration parameters such as orientation, margins, headers/footers, etc.
 // If config is nil, default options will be returned.
@@ -490,11 +696,185 @@ func (p *PDFGeneratorRod) buildPDFOptions(config *dto.ItemConfig) *proto.PagePri
 	return pdfOpts
 }
 
+// loadItemContent loads the content of the item into the page.
+// Inline HTML is set directly, while remote URLs, already checked by GeneratePDF, are navigated to
+// with the item headers and cookies. Every request made by a remote page is checked against
+// the RemoteURLPolicy, so redirects and assets cannot reach denied hosts.
+// Unless private networks are allowed, the page proxy is restricted meanwhile, so the addresses
+// the hosts resolve to when the page connects to them are checked too.
+// The returned function resets the page state and must be called once the PDF is generated.
+// Only the loading is bound to the context, the page state is reset even if the context is done.
+// After a remote URL, the cookies of the browser context and the storage of every origin the page
+// requested are cleared as well, so nothing set by a page or an item is sent by the next ones.
+func (p *PDFGeneratorRod) loadItemContent(ctx context.Context, pwb *PageWithBrowser, item dto.PDFItem) (func(), error) {
+	page := pwb.Page
+	if item.URL == nil {
+		return func() {}, page.Context(ctx).SetDocumentContent(item.BodyHTML)
+	}
+
+	policy := GetRemoteURLPolicy()
+	cleanups := make([]func(), 0)
+	cleanup := func() {
+		// Stop any pending request first, as they would not be filtered once the router stops
+		_ = page.StopLoading()
+
+		// Run in reverse order, as the later steps depend on the earlier ones
+		for idx := len(cleanups) - 1; idx >= 0; idx-- {
+			cleanups[idx]()
+		}
+	}
+
+	// Clear the state left by the page once everything else is reset, as it runs last
+	origins := newVisitedOrigins()
+	cleanups = append(cleanups, func() {
+		clearBrowsingData(page, origins.list())
+	})
+
+	// Refuse to connect to private networks, whatever the hosts resolve to
+	if pwb.Proxy != nil {
+		pwb.Proxy.Restrict(true)
+		cleanups = append(cleanups, func() {
+			pwb.Proxy.Restrict(false)
+		})
+	}
+
+	// Block any request to a host that is not allowed by the policy
+	router := page.HijackRequests()
+	err := router.Add("*", "", func(ctx *rod.Hijack) {
+		if err := policy.Check(ctx.Request.URL().String()); err != nil {
+			sharedUtilities.GetLogger().
+				WithField("url", ctx.Request.URL().String()).
+				Warn("Blocked request to a not allowed URL")
+
+			ctx.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+			return
+		}
+
+		origins.add(ctx.Request.URL())
+		ctx.ContinueRequest(&proto.FetchContinueRequest{})
+	})
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("error setting up request filtering: %w", err)
+	}
+	go router.Run()
+	cleanups = append(cleanups, func() {
+		_ = router.Stop()
+	})
+
+	// Set the extra headers
+	if len(item.Headers) > 0 {
+		headers := make([]string, 0, len(item.Headers)*2)
+		for name, value := range item.Headers {
+			headers = append(headers, name, value)
+		}
+
+		restoreHeaders, err := page.SetExtraHeaders(headers)
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("error setting extra headers: %w", err)
+		}
+		cleanups = append(cleanups, restoreHeaders)
+	}
+
+	// Set the cookies
+	if len(item.Cookies) > 0 {
+		cookies := make([]*proto.NetworkCookieParam, len(item.Cookies))
+		for idx, cookie := range item.Cookies {
+			cookies[idx] = &proto.NetworkCookieParam{
+				Name:  cookie.Name,
+				Value: cookie.Value,
+				URL:   *item.URL,
+			}
+			if cookie.Domain != nil {
+				cookies[idx].Domain = *cookie.Domain
+			}
+			if cookie.Path != nil {
+				cookies[idx].Path = *cookie.Path
+			}
+		}
+
+		if err := page.SetCookies(cookies); err != nil {
+			cleanup()
+			return nil, fmt.Errorf("error setting cookies: %w", err)
+		}
+	}
+
+	// Navigate to the remote page
+	if err := page.Context(ctx).Navigate(*item.URL); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("error navigating to remote URL: %w", err)
+	}
+
+	return cleanup, nil
+}
+
+// visitedOrigins records the origins requested by a page, which is filtered concurrently
+type visitedOrigins struct {
+	mutex   sync.Mutex
+	origins map[string]struct{}
+}
+
+// newVisitedOrigins creates an empty record of visited origins
+func newVisitedOrigins() *visitedOrigins {
+	return &visitedOrigins{origins: make(map[string]struct{})}
+}
+
+// add records the origin of the URL, if it has one
+func (v *visitedOrigins) add(requestURL *url.URL) {
+	if requestURL.Scheme != "http" && requestURL.Scheme != "https" {
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.origins[requestURL.Scheme+"://"+requestURL.Host] = struct{}{}
+}
+
+// list returns the recorded origins
+func (v *visitedOrigins) list() []string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	origins := make([]string, 0, len(v.origins))
+	for origin := range v.origins {
+		origins = append(origins, origin)
+	}
+
+	return origins
+}
+
+// clearBrowsingData clears the cookies of the browser context of the page and the storage
+// (local storage, IndexedDB, cache storage, service workers...) of the given origins.
+// Failures are only logged, as the page is reused anyway.
+func clearBrowsingData(page *rod.Page, origins []string) {
+	// An empty list clears all the cookies of the browser context
+	if err := page.SetCookies(nil); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			Warn("Error clearing the cookies of the page")
+	}
+
+	for _, origin := range origins {
+		err := proto.StorageClearDataForOrigin{
+			Origin:       origin,
+			StorageTypes: string(proto.StorageStorageTypeAll),
+		}.Call(page)
+		if err != nil {
+			sharedUtilities.GetLogger().
+				WithError(err).
+				WithField("origin", origin).
+				Warn("Error clearing the storage of an origin")
+		}
+	}
+}
+
 // mergePDFs combines multiple PDF readers into a single PDF document.
 // It works by writing each reader to a temporary file, then using the pdfcpu library
-// to merge them into a single output file, which is then returned as a reader.
+// to merge them into a single output file, which is then returned as a reader along with its size and page count.
+// The reader is backed by the output file, which is removed when the reader is closed.
 // This function handles concurrent writing of the input PDFs to optimize performance.
-func (p *PDFGeneratorRod) mergePDFs(readers []io.Reader) (io.Reader, error) {
+func (p *PDFGeneratorRod) mergePDFs(readers []io.Reader) (*dto.GeneratedPDF, error) {
 	// Create array to store temporary file paths
 	tempFilesNames := make([]string, len(readers))
 
@@ -573,8 +953,17 @@ func (p *PDFGeneratorRod) mergePDFs(readers []io.Reader) (io.Reader, error) {
 		return nil, processingErr
 	}
 
-	// Create output file path with a unique name
-	outputPath := filepath.Join(tempDir, fmt.Sprintf("merged_%s.pdf", sharedInfrastructure.GenerateXID()))
+	// Create the output file outside the temporary directory, so it outlives the merge until the reader is closed
+	outputFile, err := os.CreateTemp("", "pdf_merged_*.pdf")
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			Error("Failed to create merged PDF file")
+
+		return nil, fmt.Errorf("error creating merged PDF file: %w", err)
+	}
+	outputPath := outputFile.Name()
+	_ = outputFile.Close()
 
 	// Merge all PDFs into a single file using pdfcpu library
 	if err := pdfProcessingAPI.MergeCreateFile(tempFilesNames, outputPath, false, pdfProcessingModel.NewDefaultConfiguration()); err != nil {
@@ -583,30 +972,255 @@ func (p *PDFGeneratorRod) mergePDFs(readers []io.Reader) (io.Reader, error) {
 			WithField("output_path", outputPath).
 			Error("Failed to merge PDF files")
 
+		_ = os.Remove(outputPath)
 		return nil, err
 	}
 
-	// Read the merged PDF file
-	merged, err := os.ReadFile(outputPath)
+	// Count the pages of the merged PDF file
+	pageCount, err := pdfProcessingAPI.PageCountFile(outputPath)
 	if err != nil {
 		sharedUtilities.GetLogger().
 			WithError(err).
 			WithField("output_path", outputPath).
-			Error("Failed to read merged PDF file")
+			Error("Failed to count pages of merged PDF file")
 
+		_ = os.Remove(outputPath)
 		return nil, err
 	}
 
-	// Return the merged PDF as a reader
-	return bytes.NewReader(merged), nil
+	// Open the merged PDF file, which is removed once the reader is closed
+	reader, err := openTemporaryFileReader(outputPath)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("output_path", outputPath).
+			Error("Failed to open merged PDF file")
+
+		return nil, err
+	}
+
+	info, err := reader.Stat()
+	if err != nil {
+		_ = reader.Close()
+		return nil, fmt.Errorf("error getting merged PDF file size: %w", err)
+	}
+
+	// Return the merged PDF as a reader of the file, so it is never fully loaded in memory
+	return &dto.GeneratedPDF{
+		Reader:    reader,
+		Size:      info.Size(),
+		PageCount: pageCount,
+	}, nil
+}
+
+// waitForReadiness waits for the conditions of the item to be met, in the order they are declared.
+// The waits are bounded by the page context, so they fail once the item wait timeout is exceeded.
+func (p *PDFGeneratorRod) waitForReadiness(page *rod.Page, waitFor *dto.WaitFor) error {
+	// Wait for the element to appear
+	if waitFor.Selector != nil {
+		if _, err := page.Element(*waitFor.Selector); err != nil {
+			return err
+		}
+	}
+
+	// Wait for the expression to become truthy, awaiting it in case it returns a promise
+	if waitFor.Expression != nil {
+		expression := fmt.Sprintf(`async () => Boolean(await (%s))`, *waitFor.Expression)
+		if err := page.Wait(rod.Eval(expression).ByPromise()); err != nil {
+			return err
+		}
+	}
+
+	// Wait for the page to flag itself as ready
+	if waitFor.ReadyFlag != nil && *waitFor.ReadyFlag {
+		if err := page.Wait(rod.Eval(`() => Boolean(window.serpentariusReady)`)); err != nil {
+			return err
+		}
+	}
+
+	// Wait for the web fonts to load
+	if waitFor.Fonts != nil && *waitFor.Fonts {
+		if _, err := page.Eval(`() => document.fonts.ready.then(() => true)`); err != nil {
+			return err
+		}
+	}
+
+	// Wait for the fixed delay
+	if waitFor.DelayMilliseconds != nil {
+		select {
+		case <-time.After(time.Duration(*waitFor.DelayMilliseconds) * time.Millisecond):
+		case <-page.GetContext().Done():
+			return page.GetContext().Err()
+		}
+	}
+
+	return nil
+}
+
+// itemWaitTimeoutSeconds returns the max seconds the item can take to be rendered
+func itemWaitTimeoutSeconds(item dto.PDFItem) int {
+	if item.Config != nil && item.Config.WaitTimeoutSeconds != nil {
+		return *item.Config.WaitTimeoutSeconds
+	}
+
+	return sharedInfrastructure.GetEnvironment().RenderWaitTimeoutSeconds
+}
+
+// renderItem loads the item into the page, waits for it to be ready and prints it.
+// The whole rendering is bounded by the item wait timeout, exceeding it returns a RENDER_TIMEOUT
+// domain error while scripts throwing in the page return a SCRIPT_ERROR domain error.
+func (p *PDFGeneratorRod) renderItem(ctx context.Context, pwb *PageWithBrowser, idx int, item dto.PDFItem) ([]byte, error) {
+	timeoutSeconds := itemWaitTimeoutSeconds(item)
+	itemCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	// Translate the errors caused by the page into domain errors
+	toDomainError := func(err error) error {
+		var evalErr *rod.EvalError
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(itemCtx.Err(), context.DeadlineExceeded):
+			return pdfErrors.NewRenderTimeoutError(idx, timeoutSeconds)
+		case errors.As(err, &evalErr):
+			return pdfErrors.NewScriptError(idx, evalErr.Error())
+		default:
+			return err
+		}
+	}
+
+	// Ensure an abandoned page stops loading before being reused
+	defer func() {
+		if itemCtx.Err() != nil {
+			_ = pwb.Page.StopLoading()
+		}
+	}()
+
+	// Build PDF options based on item configuration
+	opts := p.buildPDFOptions(item.Config)
+
+	// Load the item content (inline HTML or remote URL) into the page
+	cleanup, err := p.loadItemContent(itemCtx, pwb, item)
+	if err != nil {
+		return nil, toDomainError(err)
+	}
+	// Ensure the page is reset before returning it to the pool
+	defer cleanup()
+
+	// Bind the remaining page operations to the item context
+	page := pwb.Page.Context(itemCtx)
+
+	// Wait for page to fully load and become idle
+	if err := page.WaitLoad(); err != nil {
+		return nil, toDomainError(err)
+	}
+	if err := page.WaitIdle(time.Duration(timeoutSeconds) * time.Second); err != nil {
+		return nil, toDomainError(err)
+	}
+
+	// Wait for all images to load
+	_, err = page.Eval(`() => {
+		return Promise.all(
+			Array.from(document.images).map(img => {
+				if (img.complete) return Promise.resolve();
+				return new Promise(resolve => img.onload = img.onerror = resolve);
+			})
+		);
+	}`)
+	if err != nil {
+		return nil, toDomainError(err)
+	}
+
+	// Wait for the readiness conditions requested for the item
+	if item.Config != nil && item.Config.WaitFor != nil {
+		if err := p.waitForReadiness(page, item.Config.WaitFor); err != nil {
+			return nil, toDomainError(err)
+		}
+	}
+
+	// Generate the PDF from the page, reading it before the page is returned to the pool
+	stream, err := page.PDF(opts)
+	if err != nil {
+		return nil, toDomainError(err)
+	}
+
+	pdf, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, toDomainError(err)
+	}
+
+	return pdf, nil
+}
+
+// lookupCachedItem returns the render cache key of the item, empty if it is not cached, and its cached PDF, if any.
+// The cache is only a shortcut, so its failures are logged and the item is rendered anyway.
+func (p *PDFGeneratorRod) lookupCachedItem(ctx context.Context, idx int, item dto.PDFItem) (string, []byte) {
+	if p.itemCache == nil {
+		return "", nil
+	}
+
+	key, cacheable, err := p.fingerprinter.FingerprintItem(item)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("item_index", idx).
+			Warn("Failed to build item cache key")
+		return "", nil
+	}
+	if !cacheable {
+		return "", nil
+	}
+
+	cached, err := p.itemCache.Get(ctx, key)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("item_index", idx).
+			Warn("Failed to get item PDF from cache")
+		return key, nil
+	}
+	if cached == nil {
+		return key, nil
+	}
+
+	return key, cached.Content
+}
+
+// cacheItem stores the PDF of the item under its render cache key, logging any error.
+// The page count is not needed to merge the items, so it is left unknown.
+func (p *PDFGeneratorRod) cacheItem(ctx context.Context, idx int, key string, pdf []byte) {
+	if key == "" {
+		return
+	}
+
+	if err := p.itemCache.Set(ctx, key, &dto.CachedPDF{Content: pdf}); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("item_index", idx).
+			Warn("Failed to cache item PDF")
+	}
 }
 
 // GeneratePDF is the main method for generating PDFs from HTML content.
 // It processes each PDF item concurrently using the browser pool, then merges
-// all generated PDFs into a single document which is returned as an io.Reader.
+// all generated PDFs into a single document which is returned as an io.Reader with its details.
+// The items found in the item cache are reused, so only the others are dispatched to the browser pool.
 // This method handles initializing the generator if needed and coordinates
 // the parallel generation of multiple PDF items.
-func (p *PDFGeneratorRod) GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader, error) {
+// When the context is done, waiting for pages and rendering stop and the context error is returned.
+// The remote URLs are checked against the RemoteURLPolicy first, so a denied one fails the request
+// before any page is requested from the pool.
+func (p *PDFGeneratorRod) GeneratePDF(ctx context.Context, request *dto.PDFGenerationDTO) (*dto.GeneratedPDF, error) {
+	policy := GetRemoteURLPolicy()
+	for _, item := range request.Items {
+		if item.URL == nil {
+			continue
+		}
+		if err := policy.Check(*item.URL); err != nil {
+			return nil, err
+		}
+	}
+
 	// Prepare storage for individual PDF readers
 	readers := make([]io.Reader, len(request.Items))
 
@@ -614,6 +1228,7 @@ func (p *PDFGeneratorRod) GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader,
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var processingErr error
+	var itemCache dto.ItemCacheStats
 
 	// Process each PDF item concurrently
 	for idx, item := range request.Items {
@@ -621,22 +1236,32 @@ func (p *PDFGeneratorRod) GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader,
 		go func(i int, pdfItem dto.PDFItem) {
 			defer wg.Done()
 
-			// Get a page from the pool
-			pwb := p.RequestPage()
-			// Ensure page is returned to pool after use
-			defer p.ReturnPage(pwb)
+			// Trace the whole item, including the wait for a page
+			itemCtx, span := tracer.Start(ctx, "PDFGeneratorRod.renderItem", trace.WithAttributes(
+				attribute.Int("pdf.item.index", i),
+				attribute.Bool("pdf.item.remote", pdfItem.URL != nil),
+			))
+			var err error
+			defer func() {
+				sharedUtilities.EndSpan(span, err)
+			}()
 
-			// Build PDF options based on item configuration
-			opts := p.buildPDFOptions(pdfItem.Config)
+			// Reuse the PDF of the item if it was rendered before
+			itemKey, cachedPDF := p.lookupCachedItem(itemCtx, i, pdfItem)
+			span.SetAttributes(attribute.Bool("pdf.item.cache_hit", cachedPDF != nil))
+			if cachedPDF != nil {
+				mu.Lock()
+				readers[i] = bytes.NewReader(cachedPDF)
+				itemCache.Hits++
+				mu.Unlock()
+				return
+			}
 
-			// Set the HTML content to the page
-			err := pwb.Page.SetDocumentContent(pdfItem.BodyHTML)
+			// Get a page from the pool
+			waitCtx, waitSpan := tracer.Start(itemCtx, "PDFGeneratorRod.waitForPage")
+			pwb, err := p.RequestPage(waitCtx)
+			sharedUtilities.EndSpan(waitSpan, err)
 			if err != nil {
-				sharedUtilities.GetLogger().
-					WithError(err).
-					WithField("item_index", i).
-					Error("Failed to set document content for PDF generation")
-
 				mu.Lock()
 				if processingErr == nil {
 					processingErr = err
@@ -644,23 +1269,14 @@ func (p *PDFGeneratorRod) GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader,
 				mu.Unlock()
 				return
 			}
+			// Ensure page is returned to pool after use
+			defer p.ReturnPage(pwb)
 
-			// Wait for page to fully load and become idle
-			pwb.Page.MustWaitLoad().MustWaitIdle()
-
-			// Wait for all images to load
-			pwb.Page.MustEval(`() => {
-				return Promise.all(
-					Array.from(document.images).map(img => {
-						if (img.complete) return Promise.resolve();
-						return new Promise(resolve => img.onload = img.onerror = resolve);
-					})
-				);
-			}`)
-
-			// Generate the PDF from the page
-			pdf, err := pwb.Page.PDF(opts)
+			// Render the item into a PDF
+			renderStart := time.Now()
+			pdf, err := p.renderItem(itemCtx, pwb, i, pdfItem)
 			if err != nil {
+				p.metrics.ObserveItemRender(time.Since(renderStart), sharedDefinitions.RENDER_OUTCOME_ERROR)
 				sharedUtilities.GetLogger().
 					WithError(err).
 					WithField("item_index", i).
@@ -674,9 +1290,13 @@ func (p *PDFGeneratorRod) GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader,
 				return
 			}
 
+			p.metrics.ObserveItemRender(time.Since(renderStart), sharedDefinitions.RENDER_OUTCOME_SUCCESS)
+			p.cacheItem(itemCtx, i, itemKey, pdf)
+
 			// Store the generated PDF reader
 			mu.Lock()
-			readers[i] = pdf
+			readers[i] = bytes.NewReader(pdf)
+			itemCache.Misses++
 			mu.Unlock()
 		}(idx, item)
 	}
@@ -684,11 +1304,28 @@ func (p *PDFGeneratorRod) GeneratePDF(request *dto.PDFGenerationDTO) (io.Reader,
 	// Wait for all PDF generation to complete
 	wg.Wait()
 
+	// Report the cancellation rather than the errors it caused in the items
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Check if any errors occurred during generation
 	if processingErr != nil {
 		return nil, processingErr
 	}
 
 	// Merge all generated PDFs into a single document
-	return p.mergePDFs(readers)
+	_, mergeSpan := tracer.Start(ctx, "PDFGeneratorRod.mergePDFs", trace.WithAttributes(
+		attribute.Int("pdf.items", len(readers)),
+	))
+	mergeStart := time.Now()
+	merged, err := p.mergePDFs(readers)
+	sharedUtilities.EndSpan(mergeSpan, err)
+	if err != nil {
+		return nil, err
+	}
+	p.metrics.ObserveMerge(time.Since(mergeStart))
+	merged.ItemCache = itemCache
+
+	return merged, nil
 }
@@ -0,0 +1,103 @@
+package implementations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	"github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/go-rod/rod"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPDFGeneratorRod_RequestPageKeepsProxy tests the requested pages keep the proxy they reach the network through
+func TestPDFGeneratorRod_RequestPageKeepsProxy(t *testing.T) {
+	proxy, err := NewEgressProxy()
+	if !assert.NoError(t, err, "Starting the proxy should succeed") {
+		return
+	}
+	defer proxy.Close()
+
+	generator := NewPDFGeneratorRod(utilities.NewFakeMetricsRecorder(), nil, fingerprint.RequestFingerprinter{})
+	generator.availablePages = append(generator.availablePages, &PageWithTimeout{
+		PageWithBrowser: PageWithBrowser{Page: &rod.Page{}, Proxy: proxy},
+	})
+
+	pwb, err := generator.RequestPage(context.Background())
+	if assert.NoError(t, err, "Requesting an available page should succeed") {
+		// Do not return the page, as it is not backed by a browser
+		defer generator.pageWaitGroup.Done()
+		assert.Same(t, proxy, pwb.Proxy, "Requested page should keep its proxy")
+	}
+}
+
+// TestPDFGeneratorRod_RestrictedPage tests a page refuses to load a loopback address once its proxy is restricted
+func TestPDFGeneratorRod_RestrictedPage(t *testing.T) {
+	if _, err := os.Stat(sharedInfrastructure.GetEnvironment().ChromiumBinaryPath); err != nil {
+		t.Skip("Chromium binary is not available")
+	}
+	if GetRemoteURLPolicy().allowPrivateNetworks {
+		t.Skip("Pages are not proxied when private networks are allowed")
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("<p>Internal</p>"))
+	}))
+	defer server.Close()
+
+	generator := NewPDFGeneratorRod(utilities.NewFakeMetricsRecorder(), nil, fingerprint.RequestFingerprinter{})
+	defer generator.ReleaseBrowserPool()
+
+	pwb, err := generator.RequestPage(context.Background())
+	if !assert.NoError(t, err, "Requesting a page should succeed") {
+		return
+	}
+	defer generator.ReturnPage(pwb)
+
+	if !assert.NotNil(t, pwb.Proxy, "Requested page should be proxied") {
+		return
+	}
+
+	// The host passed the policy check, but it points to a private network when the page connects to it
+	pwb.Proxy.Restrict(true)
+	_ = pwb.Page.Navigate(server.URL)
+	_ = pwb.Page.WaitLoad()
+	pwb.Proxy.Restrict(false)
+	assert.Equal(t, int32(0), requests.Load(), "Restricted page should not reach the loopback server")
+
+	if assert.NoError(t, pwb.Page.Navigate(server.URL), "Unrestricted page should load the loopback server") {
+		_ = pwb.Page.WaitLoad()
+		assert.Positive(t, requests.Load(), "Unrestricted page should reach the loopback server through its proxy")
+	}
+}
+
+// TestVisitedOrigins tests the origins requested by a page are recorded once, without the non-network schemes
+func TestVisitedOrigins(t *testing.T) {
+	origins := newVisitedOrigins()
+	for _, rawURL := range []string{
+		"https://example.com/index.html",
+		"https://example.com/styles.css",
+		"http://cdn.example.com:8080/image.png",
+		"data:image/png;base64,AAAA",
+	} {
+		requestURL, err := url.Parse(rawURL)
+		if assert.NoErrorf(t, err, "Parsing %s should succeed", rawURL) {
+			origins.add(requestURL)
+		}
+	}
+
+	assert.ElementsMatch(
+		t,
+		[]string{"https://example.com", "http://cdn.example.com:8080"},
+		origins.list(),
+		"Should record every network origin once",
+	)
+}
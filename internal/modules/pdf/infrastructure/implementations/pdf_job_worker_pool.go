@@ -0,0 +1,169 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// pdfJob is a PDF generation request waiting to be processed by a worker
+type pdfJob struct {
+	ID      string
+	Request *dto.PDFGenerationDTO
+}
+
+// PDFJobWorkerPool implements the PDFJobDispatcher interface using a bounded queue
+// consumed by a fixed number of goroutines. Each worker generates the PDF with the
+// URL use case, records the progress of the job in the job storage and notifies
+// the callback URL of the job, if any, once it finishes.
+type PDFJobWorkerPool struct {
+	queue          chan pdfJob                                      // Jobs waiting for a worker
+	jobStorage     sharedDefinitions.JobStorage                     // Storage where the job states are recorded
+	useCase        *use_cases.GeneratePDFReturningURLUseCase        // Use case that generates and uploads the PDF
+	webhookUseCase *use_cases.DeliverPDFGenerationJobWebhookUseCase // Use case that notifies the callback URL
+	jobTimeout     time.Duration                                    // Max time a job can take before being cancelled
+}
+
+// Global singleton instance and initialization control
+var pdfJobWorkerPoolInstance *PDFJobWorkerPool
+var pdfJobWorkerPoolOnce sync.Once
+
+// GetPDFJobWorkerPool returns the singleton instance of the job worker pool.
+// The workers are started on the first call, sized according to the environment,
+// later calls return the already running pool.
+func GetPDFJobWorkerPool(
+	jobStorage sharedDefinitions.JobStorage,
+	useCase *use_cases.GeneratePDFReturningURLUseCase,
+	webhookUseCase *use_cases.DeliverPDFGenerationJobWebhookUseCase,
+) *PDFJobWorkerPool {
+	pdfJobWorkerPoolOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		pdfJobWorkerPoolInstance = &PDFJobWorkerPool{
+			queue:          make(chan pdfJob, env.JobQueueSize),
+			jobStorage:     jobStorage,
+			useCase:        useCase,
+			webhookUseCase: webhookUseCase,
+			jobTimeout:     time.Duration(env.JobTimeoutSeconds) * time.Second,
+		}
+
+		for range env.JobWorkers {
+			go pdfJobWorkerPoolInstance.work()
+		}
+
+		sharedUtilities.GetLogger().
+			WithField("workers", env.JobWorkers).
+			WithField("queue_size", env.JobQueueSize).
+			Info("Started PDF job workers")
+	})
+
+	return pdfJobWorkerPoolInstance
+}
+
+// Dispatch queues the request to be processed under the given job ID.
+// It fails with a JOB_QUEUE_FULL domain error if the queue has no room left.
+func (p *PDFJobWorkerPool) Dispatch(jobID string, request *dto.PDFGenerationDTO) error {
+	select {
+	case p.queue <- pdfJob{ID: jobID, Request: request}:
+		return nil
+	default:
+		code := sharedErrors.JOB_QUEUE_FULL_ERROR_CODE
+		return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+			Code:    &code,
+			Message: "There are too many queued jobs. Please, try again later",
+		})
+	}
+}
+
+// work processes queued jobs until the queue is closed
+func (p *PDFJobWorkerPool) work() {
+	for job := range p.queue {
+		p.process(job)
+	}
+}
+
+// process runs a single job, recording its state before and after the generation
+func (p *PDFJobWorkerPool) process(job pdfJob) {
+	// Jobs are detached from the request that created them, so they get their own deadline
+	ctx, cancel := context.WithTimeout(context.Background(), p.jobTimeout)
+	defer cancel()
+
+	state := p.run(ctx, job)
+	if state == nil {
+		return
+	}
+
+	// Notify the caller in background so the worker can take the next job.
+	// The delivery must not be cancelled when the job context is.
+	callbackURL := job.Request.Config.CallbackURL
+	if callbackURL != nil {
+		go func() {
+			_ = p.webhookUseCase.Execute(
+				context.Background(),
+				*state,
+				*callbackURL,
+				job.Request.Config.CallbackSecret,
+			)
+		}()
+	}
+}
+
+// run generates the PDF of the job and returns its final state, or nil if the job could not be found
+func (p *PDFJobWorkerPool) run(ctx context.Context, job pdfJob) *sharedDefinitions.Job {
+	// Recover the stored state to keep the creation date
+	state, err := p.jobStorage.Get(ctx, job.ID)
+	if err != nil || state == nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("job_id", job.ID).
+			Error("Failed to get job state, skipping job")
+		return nil
+	}
+
+	// Mark the job as running
+	state.Status = sharedDefinitions.JOB_STATUS_RUNNING
+	p.saveState(ctx, state)
+
+	// Generate and upload the PDF
+	pdf, err := p.useCase.Execute(ctx, job.Request)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("job_id", job.ID).
+			Error("PDF generation job failed")
+
+		state.Status = sharedDefinitions.JOB_STATUS_FAILED
+		state.Error = use_cases.BuildJobError(err)
+		p.saveState(ctx, state)
+		return state
+	}
+
+	state.Status = sharedDefinitions.JOB_STATUS_SUCCEEDED
+	state.URL = &pdf.URL
+	state.FileSize = pdf.FileSize
+	state.PageCount = pdf.PageCount
+	state.CacheHit = &pdf.CacheHit
+	p.saveState(ctx, state)
+	return state
+}
+
+// saveState stores the job state, logging any error as there is no caller to report it to.
+// The state is stored even if the job context is done, so timed out jobs are recorded as failed.
+func (p *PDFJobWorkerPool) saveState(ctx context.Context, state *sharedDefinitions.Job) {
+	state.UpdatedAt = time.Now().UTC()
+
+	if err := p.jobStorage.Set(context.WithoutCancel(ctx), *state); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("job_id", state.ID).
+			WithField("status", state.Status).
+			Error("Failed to store job state")
+	}
+}
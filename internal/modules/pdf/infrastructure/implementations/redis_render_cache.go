@@ -0,0 +1,113 @@
+package implementations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/redis/go-redis/v9"
+)
+
+// REDIS_RENDER_CACHE_KEY_PREFIX prefixes the hashes holding the content and page count of the cached PDFs
+const REDIS_RENDER_CACHE_KEY_PREFIX = "render-cache:"
+
+// RedisRenderCache implements the RenderCache interface for Redis
+type RedisRenderCache struct {
+	client       redis.UniversalClient
+	maxEntrySize int64
+	expiration   time.Duration
+}
+
+var (
+	redisRenderCache     *RedisRenderCache
+	redisRenderCacheOnce sync.Once
+)
+
+// NewRedisRenderCache creates a RedisRenderCache caching the PDFs up to the given size for the given time, forever if 0
+func NewRedisRenderCache(client redis.UniversalClient, maxEntrySize int64, expiration time.Duration) *RedisRenderCache {
+	return &RedisRenderCache{
+		client:       client,
+		maxEntrySize: maxEntrySize,
+		expiration:   expiration,
+	}
+}
+
+// GetRedisRenderCache returns a singleton instance of RedisRenderCache configured from the environment
+func GetRedisRenderCache() definitions.RenderCache {
+	redisRenderCacheOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		redisRenderCache = NewRedisRenderCache(
+			sharedImplementations.GetRedisClient(),
+			int64(env.RenderCacheMaxEntrySizeMb)*1024*1024,
+			time.Duration(env.RenderCacheTTLSeconds)*time.Second,
+		)
+	})
+
+	return redisRenderCache
+}
+
+// Get returns the cached PDF, nil if the key is not cached
+func (r *RedisRenderCache) Get(ctx context.Context, key string) (*dto.CachedPDF, error) {
+	values, err := r.client.HMGet(ctx, REDIS_RENDER_CACHE_KEY_PREFIX+key, "content", "pageCount").Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting rendered PDF: %w", err)
+	}
+
+	content, isCached := values[0].(string)
+	if !isCached {
+		return nil, nil
+	}
+
+	pageCount := 0
+	if value, ok := values[1].(string); ok {
+		pageCount, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing page count of rendered PDF: %w", err)
+		}
+	}
+
+	return &dto.CachedPDF{Content: []byte(content), PageCount: pageCount}, nil
+}
+
+// Set caches the PDF under the key, skipping it if it is larger than the max entry size
+func (r *RedisRenderCache) Set(ctx context.Context, key string, pdf *dto.CachedPDF) error {
+	if int64(len(pdf.Content)) > r.maxEntrySize {
+		return nil
+	}
+
+	// Replace the hash atomically, so the content and page count of different renders are never mixed
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, REDIS_RENDER_CACHE_KEY_PREFIX+key)
+		pipe.HSet(ctx, REDIS_RENDER_CACHE_KEY_PREFIX+key, "content", pdf.Content, "pageCount", pdf.PageCount)
+		if r.expiration > 0 {
+			pipe.Expire(ctx, REDIS_RENDER_CACHE_KEY_PREFIX+key, r.expiration)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error caching rendered PDF: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the PDF cached under the key
+func (r *RedisRenderCache) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, REDIS_RENDER_CACHE_KEY_PREFIX+key).Err(); err != nil {
+		return fmt.Errorf("error deleting rendered PDF: %w", err)
+	}
+
+	return nil
+}
+
+// MaxEntrySize returns the size in bytes of the largest PDF that can be cached
+func (r *RedisRenderCache) MaxEntrySize() int64 {
+	return r.maxEntrySize
+}
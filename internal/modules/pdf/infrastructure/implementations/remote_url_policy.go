@@ -0,0 +1,217 @@
+package implementations
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// RemoteURLPolicy decides which URLs the browser is allowed to reach when rendering remote pages,
+// or which callback URLs the webhooks are allowed to notify, each with their own settings.
+// It prevents the generator from being used to reach internal services (SSRF).
+type RemoteURLPolicy struct {
+	allowedHosts         []string // Hosts that can be reached, any host if empty
+	deniedHosts          []string // Hosts that can never be reached
+	allowPrivateNetworks bool     // Whether private, loopback and link-local IPs can be reached
+}
+
+// Global singleton instances and initialization control
+var remoteURLPolicyInstance *RemoteURLPolicy
+var remoteURLPolicyOnce sync.Once
+var callbackURLPolicyInstance *RemoteURLPolicy
+var callbackURLPolicyOnce sync.Once
+
+// NewRemoteURLPolicy creates a policy allowing the given hosts, or any host if empty, except the denied ones.
+// Patterns starting with "*." match any subdomain of the rest of the pattern.
+func NewRemoteURLPolicy(allowedHosts []string, deniedHosts []string, allowPrivateNetworks bool) *RemoteURLPolicy {
+	return &RemoteURLPolicy{
+		allowedHosts:         normalizeHosts(allowedHosts),
+		deniedHosts:          normalizeHosts(deniedHosts),
+		allowPrivateNetworks: allowPrivateNetworks,
+	}
+}
+
+// GetRemoteURLPolicy returns the singleton instance of the policy configured in the environment
+func GetRemoteURLPolicy() *RemoteURLPolicy {
+	remoteURLPolicyOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		remoteURLPolicyInstance = NewRemoteURLPolicy(
+			env.RemoteURLAllowedHosts,
+			env.RemoteURLDeniedHosts,
+			env.RemoteURLAllowPrivateNetworks,
+		)
+	})
+
+	return remoteURLPolicyInstance
+}
+
+// GetCallbackURLPolicy returns the singleton instance of the policy of the webhook callback URLs configured in the environment.
+// It is separate from the policy of the rendered URLs, so internal services can be notified without being rendered.
+func GetCallbackURLPolicy() *RemoteURLPolicy {
+	callbackURLPolicyOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		callbackURLPolicyInstance = NewRemoteURLPolicy(
+			env.WebhookAllowedHosts,
+			env.WebhookDeniedHosts,
+			env.WebhookAllowPrivateNetworks,
+		)
+	})
+
+	return callbackURLPolicyInstance
+}
+
+// normalizeHosts lowercases and trims the configured hosts, skipping empty ones
+func normalizeHosts(hosts []string) []string {
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			normalized = append(normalized, host)
+		}
+	}
+
+	return normalized
+}
+
+// matchesHost checks if the host matches any of the patterns.
+// Patterns starting with "*." match any subdomain of the rest of the pattern.
+func matchesHost(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if suffix, isWildcard := strings.CutPrefix(pattern, "*."); isWildcard {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+
+		if host == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedNetworks are the ranges not covered by the net.IP helpers that must not be reachable either
+var reservedNetworks = parseNetworks(
+	"0.0.0.0/8",      // This network
+	"100.64.0.0/10",  // Carrier-grade NAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // Benchmarking
+	"240.0.0.0/4",    // Reserved, including the limited broadcast
+	"64:ff9b::/96",   // Well-known IPv4/IPv6 translation (NAT64), which can embed any IPv4 address
+	"64:ff9b:1::/48", // Local-use IPv4/IPv6 translation
+	"2002::/16",      // 6to4, which can embed any IPv4 address
+)
+
+// parseNetworks parses the CIDR ranges, panicking if any is not valid
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for idx, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic("Invalid network " + cidr + ": " + err.Error())
+		}
+		networks[idx] = network
+	}
+
+	return networks
+}
+
+// isPrivateIP checks if the IP belongs to a range that must not be reachable from the outside
+func isPrivateIP(ip net.IP) bool {
+	if ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() {
+		return true
+	}
+
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check returns a REMOTE_URL_NOT_ALLOWED domain error if the URL cannot be reached.
+// The data, blob and about URLs are always allowed as they do not leave the browser,
+// the http and https URLs are checked, and any other scheme (E.g, file, ftp or ws) is rejected.
+func (p *RemoteURLPolicy) Check(rawURL string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return newRemoteURLNotAllowedError(rawURL, "The URL is not valid")
+	}
+
+	switch parsedURL.Scheme {
+	case "data", "blob", "about":
+		return nil
+	case "http", "https":
+	default:
+		return newRemoteURLNotAllowedError(rawURL, fmt.Sprintf("The %q scheme is not allowed", parsedURL.Scheme))
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+
+	if matchesHost(host, p.deniedHosts) {
+		return newRemoteURLNotAllowedError(rawURL, "The host is denied")
+	}
+
+	if len(p.allowedHosts) > 0 && !matchesHost(host, p.allowedHosts) {
+		return newRemoteURLNotAllowedError(rawURL, "The host is not allowed")
+	}
+
+	if p.allowPrivateNetworks {
+		return nil
+	}
+
+	// Resolve the host to check every address it points to
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		ips, err = net.LookupIP(host)
+		if err != nil {
+			return newRemoteURLNotAllowedError(rawURL, fmt.Sprintf("The host could not be resolved: %s", err.Error()))
+		}
+	}
+
+	for _, ip := range ips {
+		if isPrivateIP(ip) {
+			return newRemoteURLNotAllowedError(rawURL, "The host points to a private network")
+		}
+	}
+
+	return nil
+}
+
+// CheckAddress refuses the private, loopback, link-local and reserved IPs unless private networks are allowed
+func (p *RemoteURLPolicy) CheckAddress(ip net.IP) error {
+	if p.allowPrivateNetworks || !isPrivateIP(ip) {
+		return nil
+	}
+
+	return errPrivateAddress
+}
+
+// newRemoteURLNotAllowedError creates the domain error returned when a URL cannot be reached
+func newRemoteURLNotAllowedError(rawURL string, reason string) error {
+	code := sharedErrors.REMOTE_URL_NOT_ALLOWED_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "The requested URL cannot be rendered",
+		Metadata: map[string]any{
+			"url":    rawURL,
+			"reason": reason,
+		},
+	})
+}
@@ -0,0 +1,145 @@
+package implementations
+
+import (
+	"net"
+	"testing"
+
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertNotAllowed asserts the error is the domain error returned for the URLs that cannot be reached
+func assertNotAllowed(t *testing.T, err error, rawURL string) {
+	var domainError sharedErrors.DomainError
+	if assert.ErrorAsf(t, err, &domainError, "%s should not be allowed", rawURL) {
+		assert.Equal(t, sharedErrors.REMOTE_URL_NOT_ALLOWED_ERROR_CODE, domainError.Code(), "Error code should match")
+	}
+}
+
+// TestIsPrivateIP tests the private, loopback, link-local and reserved ranges are detected
+func TestIsPrivateIP(t *testing.T) {
+	private := []string{
+		"10.0.0.1",
+		"172.16.0.1",
+		"192.168.1.1",
+		"127.0.0.1",
+		"169.254.169.254",
+		"0.0.0.0",
+		"0.1.2.3",
+		"100.64.0.1",
+		"100.127.255.254",
+		"192.0.0.170",
+		"198.18.0.1",
+		"240.0.0.1",
+		"255.255.255.255",
+		"::1",
+		"::",
+		"fc00::1",
+		"fe80::1",
+		"::ffff:10.0.0.1",
+		"::ffff:100.64.0.1",
+		"64:ff9b:1::a00:1",
+		"64:ff9b::7f00:1",
+		"64:ff9b::a9fe:a9fe",
+		"2002:7f00:1::1",
+		"2002:a9fe:a9fe::1",
+	}
+	for _, address := range private {
+		assert.Truef(t, isPrivateIP(net.ParseIP(address)), "%s should be private", address)
+	}
+
+	public := []string{
+		"8.8.8.8",
+		"100.63.255.255",
+		"100.128.0.1",
+		"198.20.0.1",
+		"2606:4700:4700::1111",
+		"::ffff:8.8.8.8",
+	}
+	for _, address := range public {
+		assert.Falsef(t, isPrivateIP(net.ParseIP(address)), "%s should be public", address)
+	}
+}
+
+// TestRemoteURLPolicy_Schemes tests the URLs not leaving the browser are allowed and only http and https are checked
+func TestRemoteURLPolicy_Schemes(t *testing.T) {
+	policy := NewRemoteURLPolicy(nil, nil, false)
+
+	for _, rawURL := range []string{"data:text/html,<p>Hi</p>", "blob:https://example.com/id", "about:blank"} {
+		assert.NoErrorf(t, policy.Check(rawURL), "%s should be allowed", rawURL)
+	}
+	for _, rawURL := range []string{"file:///etc/passwd", "ftp://8.8.8.8/file", "ws://8.8.8.8/socket", "gopher://8.8.8.8"} {
+		assertNotAllowed(t, policy.Check(rawURL), rawURL)
+	}
+	assert.NoError(t, policy.Check("https://8.8.8.8/report"), "Public https URL should be allowed")
+}
+
+// TestRemoteURLPolicy_PrivateNetworks tests the private IPs are rejected unless private networks are allowed
+func TestRemoteURLPolicy_PrivateNetworks(t *testing.T) {
+	rawURLs := []string{
+		"http://127.0.0.1:8080/admin",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/",
+		"http://0.0.0.0/",
+		"http://[::1]/",
+		"http://localhost/",
+	}
+
+	policy := NewRemoteURLPolicy(nil, nil, false)
+	for _, rawURL := range rawURLs {
+		assertNotAllowed(t, policy.Check(rawURL), rawURL)
+	}
+
+	permissivePolicy := NewRemoteURLPolicy(nil, nil, true)
+	for _, rawURL := range rawURLs {
+		assert.NoErrorf(t, permissivePolicy.Check(rawURL), "%s should be allowed with private networks", rawURL)
+	}
+}
+
+// TestCallbackURLPolicy tests the callback URLs follow their own settings, not the ones of the rendered URLs
+func TestCallbackURLPolicy(t *testing.T) {
+	env := sharedInfrastructure.GetEnvironment()
+	previous := env.WebhookAllowPrivateNetworks
+	env.WebhookAllowPrivateNetworks = true
+	defer func() {
+		env.WebhookAllowPrivateNetworks = previous
+	}()
+
+	rawURL := "http://127.0.0.1:8080/webhooks/pdf"
+	assert.NoError(t, GetCallbackURLPolicy().Check(rawURL), "Private callback URL should be allowed by the webhook settings")
+	assertNotAllowed(t, GetRemoteURLPolicy().Check(rawURL), rawURL)
+}
+
+// TestRemoteURLPolicy_Hosts tests the allowed and denied hosts, with wildcards matching the subdomains only
+func TestRemoteURLPolicy_Hosts(t *testing.T) {
+	policy := NewRemoteURLPolicy(
+		[]string{" Example.com ", "*.example.org", ""},
+		[]string{"admin.example.org"},
+		true,
+	)
+
+	assert.NoError(t, policy.Check("https://example.com/report"), "Allowed host should be allowed")
+	assert.NoError(t, policy.Check("https://EXAMPLE.com/report"), "Hosts should be compared case-insensitively")
+	assert.NoError(t, policy.Check("https://docs.example.org/report"), "Subdomain of a wildcard should be allowed")
+
+	assertNotAllowed(t, policy.Check("https://example.org/report"), "Wildcard parent domain")
+	assertNotAllowed(t, policy.Check("https://sub.example.com/report"), "Subdomain of an exact host")
+	assertNotAllowed(t, policy.Check("https://admin.example.org/report"), "Denied host")
+	assertNotAllowed(t, policy.Check("https://example.net/report"), "Host outside the allow list")
+
+	denyOnlyPolicy := NewRemoteURLPolicy(nil, []string{"*.internal.example.com"}, true)
+	assert.NoError(t, denyOnlyPolicy.Check("https://example.net/report"), "Any host should be allowed without an allow list")
+	assertNotAllowed(t, denyOnlyPolicy.Check("https://api.internal.example.com/report"), "Denied subdomain")
+}
+
+// TestRemoteURLPolicy_CheckAddress tests the private addresses are refused unless private networks are allowed
+func TestRemoteURLPolicy_CheckAddress(t *testing.T) {
+	policy := NewRemoteURLPolicy(nil, nil, false)
+	assert.ErrorIs(t, policy.CheckAddress(net.ParseIP("127.0.0.1")), errPrivateAddress, "Loopback address should be refused")
+	assert.ErrorIs(t, policy.CheckAddress(net.ParseIP("169.254.169.254")), errPrivateAddress, "Metadata address should be refused")
+	assert.NoError(t, policy.CheckAddress(net.ParseIP("8.8.8.8")), "Public address should be allowed")
+
+	permissivePolicy := NewRemoteURLPolicy(nil, nil, true)
+	assert.NoError(t, permissivePolicy.CheckAddress(net.ParseIP("127.0.0.1")), "Loopback address should be allowed with private networks")
+}
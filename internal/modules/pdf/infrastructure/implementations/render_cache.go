@@ -0,0 +1,23 @@
+package implementations
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// GetRenderCache returns the RenderCache implementation selected by the RENDER_CACHE_DRIVER environment variable,
+// nil if the rendered PDFs are not cached
+func GetRenderCache() definitions.RenderCache {
+	renderCacheDriver := sharedInfrastructure.GetEnvironment().RenderCacheDriver
+
+	switch renderCacheDriver {
+	case sharedInfrastructure.RENDER_CACHE_DRIVER_NONE:
+		return nil
+	case sharedInfrastructure.RENDER_CACHE_DRIVER_REDIS:
+		return GetRedisRenderCache()
+	case sharedInfrastructure.RENDER_CACHE_DRIVER_DISK:
+		return GetDiskRenderCache()
+	default:
+		panic("Unknown render cache driver: " + renderCacheDriver)
+	}
+}
@@ -0,0 +1,276 @@
+package implementations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	"golang.org/x/sync/singleflight"
+)
+
+// RequestCoalescer implements the RequestCoalescer interface in two levels. The callers in this replica share
+// a single execution per key through a single-flight group, and that execution holds a lock in the lock storage,
+// if any, so the replicas do not run the same key at once. The lock is renewed while the execution runs,
+// and the replicas waiting for it run the function themselves once it is released. If its owner recorded
+// a failure, every replica waiting for it returns the failure instead, which is kept for the lease,
+// so only the replicas taking the lock after it expires run the function again.
+type RequestCoalescer struct {
+	group        singleflight.Group
+	lockStorage  sharedDefinitions.LockStorage // Locks shared by the replicas, nil to only coalesce the local callers
+	lease        time.Duration                 // Time the lock is held without being renewed
+	pollInterval time.Duration                 // Time between the attempts to acquire a held lock
+}
+
+var (
+	requestCoalescer     *RequestCoalescer
+	requestCoalescerOnce sync.Once
+)
+
+// REQUEST_COALESCER_MIN_LOCK_LEASE is the shortest lease the locks can be renewed within
+const REQUEST_COALESCER_MIN_LOCK_LEASE = time.Millisecond
+
+// NewRequestCoalescer creates a RequestCoalescer locking the keys in the given storage, if not nil.
+// The lease and the poll interval are only used with a lock storage, which requires them to be positive.
+func NewRequestCoalescer(
+	lockStorage sharedDefinitions.LockStorage,
+	lease time.Duration,
+	pollInterval time.Duration,
+) *RequestCoalescer {
+	if lockStorage != nil && lease < REQUEST_COALESCER_MIN_LOCK_LEASE {
+		panic(fmt.Sprintf("The coalescing lock lease must be at least %s", REQUEST_COALESCER_MIN_LOCK_LEASE))
+	}
+	if lockStorage != nil && pollInterval <= 0 {
+		panic("The coalescing poll interval must be positive")
+	}
+
+	return &RequestCoalescer{
+		lockStorage:  lockStorage,
+		lease:        lease,
+		pollInterval: pollInterval,
+	}
+}
+
+// GetRequestCoalescer returns the RequestCoalescer selected by the COALESCING_DRIVER environment variable,
+// nil if the requests are not coalesced
+func GetRequestCoalescer() definitions.RequestCoalescer {
+	env := sharedInfrastructure.GetEnvironment()
+	if env.CoalescingDriver == sharedInfrastructure.COALESCING_DRIVER_NONE {
+		return nil
+	}
+
+	requestCoalescerOnce.Do(func() {
+		var lockStorage sharedDefinitions.LockStorage
+		switch env.CoalescingDriver {
+		case sharedInfrastructure.COALESCING_DRIVER_LOCAL:
+		case sharedInfrastructure.COALESCING_DRIVER_REDIS:
+			lockStorage = sharedImplementations.GetRedisLockStorage()
+		default:
+			panic("Unknown coalescing driver: " + env.CoalescingDriver)
+		}
+
+		requestCoalescer = NewRequestCoalescer(
+			lockStorage,
+			time.Duration(env.CoalescingLockLeaseSeconds)*time.Second,
+			time.Duration(env.CoalescingPollIntervalMilliseconds)*time.Millisecond,
+		)
+	})
+
+	return requestCoalescer
+}
+
+// Do runs the function unless an execution with the same key is already running, waiting for it otherwise.
+// The execution is detached from the cancellation of the caller that started it, so the other callers
+// are not failed by its disconnection, but it is still bounded by its deadline.
+func (c *RequestCoalescer) Do(
+	ctx context.Context,
+	key string,
+	fn definitions.CoalescedFunc,
+) (*dto.PDFURL, bool, error) {
+	resultChannel := c.group.DoChan(key, func() (any, error) {
+		executionCtx := context.WithoutCancel(ctx)
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+			var cancel context.CancelFunc
+			executionCtx, cancel = context.WithDeadline(executionCtx, deadline)
+			defer cancel()
+		}
+
+		return c.runExclusively(executionCtx, key, fn)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case result := <-resultChannel:
+		if result.Err != nil {
+			return nil, result.Shared, result.Err
+		}
+		return result.Val.(*dto.PDFURL), result.Shared, nil
+	}
+}
+
+// runExclusively runs the function holding the lock of the key, waiting for the lock if another replica holds it.
+// If the lock storage is unavailable, the function runs without the lock rather than failing the request.
+func (c *RequestCoalescer) runExclusively(ctx context.Context, key string, fn definitions.CoalescedFunc) (*dto.PDFURL, error) {
+	if c.lockStorage == nil {
+		return fn(ctx, false)
+	}
+
+	lock, waited, err := c.acquire(ctx, key)
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("key", key).
+			Warn("Failed to acquire request lock, running without it")
+		return fn(ctx, false)
+	}
+
+	// Return the error of the owner this execution waited for, keeping it for the other waiting replicas
+	if waited {
+		failure, err := c.lockStorage.GetFailure(ctx, key)
+		if err != nil {
+			sharedUtilities.GetLogger().
+				WithError(err).
+				WithField("key", key).
+				Warn("Failed to get request lock failure")
+		}
+		if failure != nil {
+			c.abandon(ctx, key, lock)
+			return nil, fromLockFailure(failure)
+		}
+	}
+
+	stopRenewal := c.startRenewal(ctx, key, lock)
+	result, err := fn(ctx, waited)
+	stopRenewal()
+
+	// The errors caused by the deadline of this execution are not the ones of the others
+	var failure *sharedDefinitions.LockFailure
+	if err != nil && ctx.Err() == nil {
+		failure = toLockFailure(err)
+	}
+	c.release(ctx, key, lock, failure)
+
+	return result, err
+}
+
+// acquire takes the lock of the key, polling it while another owner holds it.
+// It returns whether it had to wait, so the caller knows another execution ran meanwhile.
+func (c *RequestCoalescer) acquire(ctx context.Context, key string) (sharedDefinitions.Lock, bool, error) {
+	waited := false
+	for {
+		lock, err := c.lockStorage.Acquire(ctx, key, c.lease)
+		if err != nil {
+			return nil, waited, err
+		}
+		if lock != nil {
+			return lock, waited, nil
+		}
+
+		waited = true
+		select {
+		case <-ctx.Done():
+			return nil, waited, ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// startRenewal renews the lease of the lock every third of it until the returned function is called
+func (c *RequestCoalescer) startRenewal(ctx context.Context, key string, lock sharedDefinitions.Lock) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(c.lease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Renew(ctx, c.lease); err != nil {
+					sharedUtilities.GetLogger().
+						WithError(err).
+						WithField("key", key).
+						Warn("Failed to renew request lock")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// release frees the lock, logging any error as the lock expires anyway
+func (c *RequestCoalescer) release(
+	ctx context.Context,
+	key string,
+	lock sharedDefinitions.Lock,
+	failure *sharedDefinitions.LockFailure,
+) {
+	// Release the lock even if the deadline passed, so the others do not wait for it to expire
+	if err := lock.Release(context.WithoutCancel(ctx), failure); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("key", key).
+			Warn("Failed to release request lock")
+	}
+}
+
+// abandon frees the lock without touching the recorded failure, logging any error as the lock expires anyway
+func (c *RequestCoalescer) abandon(ctx context.Context, key string, lock sharedDefinitions.Lock) {
+	if err := lock.Abandon(context.WithoutCancel(ctx)); err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("key", key).
+			Warn("Failed to abandon request lock")
+	}
+}
+
+// toLockFailure converts the error to a failure other replicas can read, keeping the code and metadata of domain errors
+func toLockFailure(err error) *sharedDefinitions.LockFailure {
+	var domainError sharedErrors.DomainError
+	if errors.As(err, &domainError) {
+		return &sharedDefinitions.LockFailure{
+			Code:     domainError.Code(),
+			Message:  domainError.Message(),
+			Metadata: domainError.Metadata(),
+		}
+	}
+
+	return &sharedDefinitions.LockFailure{Message: err.Error()}
+}
+
+// fromLockFailure converts the failure back to an error, a domain error with the same code and metadata if it was one
+func fromLockFailure(failure *sharedDefinitions.LockFailure) error {
+	if failure.Code == "" {
+		return errors.New(failure.Message)
+	}
+
+	code := failure.Code
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:     &code,
+		Message:  failure.Message,
+		Metadata: failure.Metadata,
+	})
+}
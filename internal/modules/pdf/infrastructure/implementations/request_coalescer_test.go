@@ -0,0 +1,243 @@
+package implementations
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	"github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// renewalCountingLockStorage is a LockStorage handing out a single lock that counts its renewals
+type renewalCountingLockStorage struct {
+	renewals atomic.Int32
+	released atomic.Bool
+}
+
+// Acquire always takes the lock
+func (s *renewalCountingLockStorage) Acquire(ctx context.Context, key string, lease time.Duration) (sharedDefinitions.Lock, error) {
+	return s, nil
+}
+
+// GetFailure never returns a failure
+func (s *renewalCountingLockStorage) GetFailure(ctx context.Context, key string) (*sharedDefinitions.LockFailure, error) {
+	return nil, nil
+}
+
+// Renew counts the renewal
+func (s *renewalCountingLockStorage) Renew(ctx context.Context, lease time.Duration) error {
+	s.renewals.Add(1)
+	return nil
+}
+
+// Release records the lock was released
+func (s *renewalCountingLockStorage) Release(ctx context.Context, failure *sharedDefinitions.LockFailure) error {
+	s.released.Store(true)
+	return nil
+}
+
+// Abandon records the lock was released
+func (s *renewalCountingLockStorage) Abandon(ctx context.Context) error {
+	s.released.Store(true)
+	return nil
+}
+
+// TestNewRequestCoalescer_Validation tests the lease and the poll interval are only required with a lock storage
+func TestNewRequestCoalescer_Validation(t *testing.T) {
+	lockStorage := utilities.NewFakeLockStorage()
+
+	assert.NotPanics(t, func() {
+		NewRequestCoalescer(nil, 0, 0)
+	}, "Local coalescer should not use the lease nor the poll interval")
+	assert.NotPanics(t, func() {
+		NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+	}, "Valid lease and poll interval should be accepted")
+
+	assert.Panics(t, func() {
+		NewRequestCoalescer(lockStorage, 0, time.Millisecond)
+	}, "Zero lease should be rejected")
+	assert.Panics(t, func() {
+		NewRequestCoalescer(lockStorage, 2*time.Nanosecond, time.Millisecond)
+	}, "Lease too short to be renewed should be rejected")
+	assert.Panics(t, func() {
+		NewRequestCoalescer(lockStorage, time.Second, 0)
+	}, "Zero poll interval should be rejected")
+}
+
+// TestRequestCoalescer_LeaderAndWaiters tests the callers of the same key share the execution of the first one
+func TestRequestCoalescer_LeaderAndWaiters(t *testing.T) {
+	coalescer := NewRequestCoalescer(nil, 0, 0)
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	fn := func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		calls.Add(1)
+		<-release
+		return &dto.PDFURL{URL: "https://cdn.example.com/reports/report.pdf"}, nil
+	}
+
+	results := make([]*dto.PDFURL, 3)
+	shared := make([]bool, 3)
+	var waitGroup sync.WaitGroup
+	for idx := range results {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			results[idx], shared[idx], _ = coalescer.Do(context.Background(), "report", fn)
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return calls.Load() > 0
+	}, time.Second, time.Millisecond, "Leader should start running")
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	waitGroup.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "Function should run once")
+	for idx, result := range results {
+		if assert.NotNil(t, result, "Every caller should get the result") {
+			assert.Equal(t, "https://cdn.example.com/reports/report.pdf", result.URL, "Every caller should get the same URL")
+		}
+		assert.True(t, shared[idx], "Result should be reported as shared")
+	}
+}
+
+// TestRequestCoalescer_CancelledCaller tests a cancelled caller returns at once without cancelling the execution
+func TestRequestCoalescer_CancelledCaller(t *testing.T) {
+	coalescer := NewRequestCoalescer(nil, 0, 0)
+	release := make(chan struct{})
+	executionErrors := make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := coalescer.Do(ctx, "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+			<-release
+			executionErrors <- ctx.Err()
+			return &dto.PDFURL{}, nil
+		})
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled, "Cancelled caller should return its context error")
+
+	close(release)
+	assert.NoError(t, <-executionErrors, "Execution should not be cancelled with its caller")
+}
+
+// TestRequestCoalescer_LockRenewal tests the lock is renewed while the function runs and released afterwards
+func TestRequestCoalescer_LockRenewal(t *testing.T) {
+	lockStorage := &renewalCountingLockStorage{}
+	coalescer := NewRequestCoalescer(lockStorage, 3*time.Millisecond, time.Millisecond)
+
+	_, _, err := coalescer.Do(context.Background(), "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		assert.False(t, waited, "Function should not have waited")
+		time.Sleep(30 * time.Millisecond)
+		return &dto.PDFURL{}, nil
+	})
+
+	assert.NoError(t, err, "Execution should succeed")
+	assert.Positive(t, lockStorage.renewals.Load(), "Lock should be renewed while the function runs")
+	assert.True(t, lockStorage.released.Load(), "Lock should be released")
+}
+
+// TestRequestCoalescer_FailureReturnedToEveryWaiter tests the failure of an owner is returned to every waiting replica,
+// none of which runs the function again
+func TestRequestCoalescer_FailureReturnedToEveryWaiter(t *testing.T) {
+	const waitingReplicas = 3
+	lockStorage := utilities.NewFakeLockStorage()
+	var calls atomic.Int32
+	fn := func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		calls.Add(1)
+		return &dto.PDFURL{}, nil
+	}
+
+	// Another replica holds the lock while several replicas wait for it
+	lockStorage.Held["report"] = true
+	errs := make(chan error, waitingReplicas)
+	for range waitingReplicas {
+		replica := NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+		go func() {
+			_, _, err := replica.Do(context.Background(), "report", fn)
+			errs <- err
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	lockStorage.Release("report", &sharedDefinitions.LockFailure{Message: "storage unavailable"})
+
+	for range waitingReplicas {
+		assert.EqualError(t, <-errs, "storage unavailable", "Every waiting replica should get the failure of the owner")
+	}
+
+	assert.Zero(t, calls.Load(), "No waiting replica should run the function again")
+	assert.Contains(t, lockStorage.Failures, "report", "Failure should be kept until it expires")
+	assert.False(t, lockStorage.Held["report"], "Lock should be released")
+}
+
+// TestRequestCoalescer_FailureMetadata tests a replica waiting for another one gets its domain error with the same
+// code, message and metadata, even once the failure is serialized as the Redis lock storage does
+func TestRequestCoalescer_FailureMetadata(t *testing.T) {
+	code := sharedErrors.REMOTE_URL_NOT_ALLOWED_ERROR_CODE
+	leaderErr := sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:     &code,
+		Message:  "The requested URL cannot be rendered",
+		Metadata: map[string]any{"url": "http://127.0.0.1/", "reason": "The host points to a private network"},
+	})
+
+	lockStorage := utilities.NewFakeLockStorage()
+	leader := NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+	follower := NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, _, err := leader.Do(context.Background(), "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+			close(started)
+			<-release
+			return nil, leaderErr
+		})
+		leaderDone <- err
+	}()
+
+	// Wait for the leader to hold the lock before the follower asks for it
+	<-started
+	followerDone := make(chan error, 1)
+	go func() {
+		_, _, err := follower.Do(context.Background(), "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+			return &dto.PDFURL{}, nil
+		})
+		followerDone <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	assert.ErrorIs(t, <-leaderDone, leaderErr, "Leader should get its own error")
+
+	var domainError sharedErrors.DomainError
+	if assert.ErrorAs(t, <-followerDone, &domainError, "Follower should get a domain error") {
+		assert.Equal(t, leaderErr.Code(), domainError.Code(), "Follower should get the code of the leader")
+		assert.Equal(t, leaderErr.Message(), domainError.Message(), "Follower should get the message of the leader")
+		assert.Equal(t, leaderErr.Metadata(), domainError.Metadata(), "Follower should get the metadata of the leader")
+	}
+
+	// The failure read from Redis is deserialized from JSON
+	serialized, err := json.Marshal(toLockFailure(leaderErr))
+	assert.NoError(t, err, "Failure should be serialized")
+	var failure sharedDefinitions.LockFailure
+	assert.NoError(t, json.Unmarshal(serialized, &failure), "Failure should be deserialized")
+	if assert.ErrorAs(t, fromLockFailure(&failure), &domainError, "Deserialized failure should be a domain error") {
+		assert.Equal(t, leaderErr.Metadata(), domainError.Metadata(), "Deserialized failure should keep the metadata")
+	}
+}
@@ -0,0 +1,31 @@
+package implementations
+
+import (
+	"errors"
+	"os"
+)
+
+// temporaryFileReader reads a temporary file and removes it when closed.
+// It embeds the file, so consumers can also read it at offsets and seek it (E.g, the S3 multipart uploads).
+type temporaryFileReader struct {
+	*os.File
+}
+
+// openTemporaryFileReader opens the temporary file for reading, removing it if it cannot be opened
+func openTemporaryFileReader(path string) (*temporaryFileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	return &temporaryFileReader{File: file}, nil
+}
+
+// Close closes and removes the file
+func (r *temporaryFileReader) Close() error {
+	closeErr := r.File.Close()
+	removeErr := os.Remove(r.File.Name())
+
+	return errors.Join(closeErr, removeErr)
+}
@@ -1,14 +1,54 @@
 package definitions
 
-import "io"
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	// DOWNLOAD_URL_MODE_PUBLIC builds the URL from the public URL prefix, so the file must be publicly readable
+	DOWNLOAD_URL_MODE_PUBLIC = "public"
+	// DOWNLOAD_URL_MODE_PRESIGNED signs a time-limited URL, so the file can stay private
+	DOWNLOAD_URL_MODE_PRESIGNED = "presigned"
+)
+
+const (
+	// SERVER_SIDE_ENCRYPTION_S3 encrypts the file with keys managed by S3 (SSE-S3)
+	SERVER_SIDE_ENCRYPTION_S3 = "AES256"
+	// SERVER_SIDE_ENCRYPTION_KMS encrypts the file with a KMS key (SSE-KMS)
+	SERVER_SIDE_ENCRYPTION_KMS = "aws:kms"
+)
+
+// FileOptions represents the options a file is stored with.
+// The optional fields are left to the backend defaults when empty.
+type FileOptions struct {
+	ContentType          string
+	Metadata             map[string]string // Custom metadata stored with the file
+	Tags                 map[string]string // Tags used by lifecycle and access policies, not supported by every backend
+	CacheControl         string
+	ContentDisposition   string
+	ServerSideEncryption string // One of the SERVER_SIDE_ENCRYPTION_* constants, only supported by S3
+	KMSKeyID             string // Only used with SERVER_SIDE_ENCRYPTION_KMS, the bucket default key if empty
+	StorageClass         string // Backend specific (E.g, STANDARD_IA in S3 or Cool in Azure Blob Storage)
+}
 
 // UploadFileRequest represents the request for uploading a file to cloud storage.
 type UploadFileRequest struct {
-	FileReader      io.Reader
-	FileFolder      string
-	FilePath        string
-	ContentType     string
-	PublicURLPrefix string
+	FileReader io.Reader
+	FileFolder string
+	FilePath   string
+	FileOptions
+}
+
+// CopyFileRequest represents the request for copying a file within cloud storage.
+// The copy is stored with the given options instead of the ones of the source file.
+type CopyFileRequest struct {
+	SourceFileFolder string
+	SourceFilePath   string
+	FileFolder       string
+	FilePath         string
+	FileOptions
 }
 
 // FileExistsRequest represents the request for checking if a file exists in cloud storage.
@@ -17,8 +57,36 @@ type FileExistsRequest struct {
 	FilePath   string
 }
 
+// DeleteFileRequest represents the request for deleting a file from cloud storage.
+type DeleteFileRequest struct {
+	FileFolder string
+	FilePath   string
+}
+
+// GetDownloadURLRequest represents the request for getting the URL a file can be downloaded from.
+type GetDownloadURLRequest struct {
+	FileFolder      string
+	FilePath        string
+	Mode            string        // One of the DOWNLOAD_URL_MODE_* constants
+	PublicURLPrefix string        // Only used in public mode
+	Expiration      time.Duration // Lifetime of the signature, only used in presigned mode
+}
+
+// DownloadURL represents the URL a file can be downloaded from.
+type DownloadURL struct {
+	URL       string
+	ExpiresAt *time.Time // When the signature of the URL expires, nil if it never does
+}
+
 // CloudStorage is an interface for cloud storage operations.
 type CloudStorage interface {
-	UploadFile(request UploadFileRequest) (string, error)
-	FileExists(request FileExistsRequest) (bool, error)
+	UploadFile(ctx context.Context, request UploadFileRequest) error
+	FileExists(ctx context.Context, request FileExistsRequest) (bool, error)
+	// GetDownloadURL returns the public or presigned URL of the file.
+	// Backends may shorten the requested expiration to the maximum they can sign.
+	GetDownloadURL(ctx context.Context, request GetDownloadURLRequest) (*DownloadURL, error)
+	// CopyFile copies the file without downloading it, replacing the destination if it exists
+	CopyFile(ctx context.Context, request CopyFileRequest) error
+	// DeleteFile deletes the file, succeeding if it does not exist
+	DeleteFile(ctx context.Context, request DeleteFileRequest) error
 }
@@ -0,0 +1,27 @@
+package definitions
+
+import "context"
+
+// DeduplicatedFile is a stored PDF shared by the requests with the same render inputs, copied to the requested files
+type DeduplicatedFile struct {
+	FileFolder  string `json:"fileFolder"`
+	FilePath    string `json:"filePath"`
+	ContentHash string `json:"contentHash"` // Hash of the render inputs, also the key of the PDF in the render cache
+}
+
+// FileCopy identifies a file copied from a deduplicated file
+type FileCopy struct {
+	FileFolder string `json:"fileFolder"`
+	FilePath   string `json:"filePath"`
+}
+
+// DeduplicatedFileStorage is an interface for the record of the files copied from each deduplicated file,
+// so the deduplicated file is deleted along with its last copy.
+type DeduplicatedFileStorage interface {
+	// AddCopy records the file as a copy of the deduplicated file, replacing the one it was copied from before, if any.
+	// It returns the deduplicated file the copy was made from before if no copy of it is left, nil otherwise.
+	AddCopy(ctx context.Context, source DeduplicatedFile, fileCopy FileCopy) (*DeduplicatedFile, error)
+	// RemoveCopy removes the record of the copy, returning the deduplicated file it was made from
+	// if no copy of it is left, nil otherwise or if the file is not a copy
+	RemoveCopy(ctx context.Context, fileCopy FileCopy) (*DeduplicatedFile, error)
+}
@@ -1,5 +1,7 @@
 package definitions
 
+import "context"
+
 // GetRequest represents a request to fetch content from a URL.
 type GetRequest struct {
 	// URL is the URL to fetch.
@@ -8,6 +10,19 @@ type GetRequest struct {
 	Headers map[string]string `json:"headers" binding:"required"`
 }
 
+// PostRequest represents a request to send content to a URL.
+type PostRequest struct {
+	// URL is the URL to send the content to.
+	URL string `json:"url" binding:"required"`
+	// Headers are the headers to include in the request.
+	Headers map[string]string `json:"headers" binding:"required"`
+	// Body is the content to send.
+	Body []byte `json:"body"`
+}
+
+// Fetcher is an interface for HTTP operations against external services.
+// Responses with a non-2xx status code are returned as errors.
 type Fetcher interface {
-	Get(request GetRequest) ([]byte, error)
+	Get(ctx context.Context, request GetRequest) ([]byte, error)
+	Post(ctx context.Context, request PostRequest) ([]byte, error)
 }
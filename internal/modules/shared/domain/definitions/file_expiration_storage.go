@@ -0,0 +1,27 @@
+package definitions
+
+import (
+	"context"
+	"time"
+)
+
+// ExpiringFile represents a stored file that must be deleted once its expiration time passes.
+type ExpiringFile struct {
+	FileFolder string `json:"fileFolder"`
+	FilePath   string `json:"filePath"`
+}
+
+// FileExpirationStorage is an interface for the record of the uploaded files to delete once they expire.
+type FileExpirationStorage interface {
+	// Schedule records the file to be deleted at the given time, replacing its previous expiration if any
+	Schedule(ctx context.Context, file ExpiringFile, expiresAt time.Time) error
+	// Extend records the file to be deleted at the given time, unless it is already scheduled to be deleted later
+	Extend(ctx context.Context, file ExpiringFile, expiresAt time.Time) error
+	// Unschedule removes the file from the record, so it is kept forever
+	Unschedule(ctx context.Context, file ExpiringFile) error
+	// ListExpired returns up to limit files whose expiration time is not after now
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]ExpiringFile, error)
+	// Claim removes the file from the record only if it is still expired, returning whether it did.
+	// It ensures a file uploaded again after being listed is not deleted, and that only one cleaner deletes each file.
+	Claim(ctx context.Context, file ExpiringFile, now time.Time) (bool, error)
+}
@@ -0,0 +1,55 @@
+package definitions
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	JOB_STATUS_QUEUED    = "queued"
+	JOB_STATUS_RUNNING   = "running"
+	JOB_STATUS_SUCCEEDED = "succeeded"
+	JOB_STATUS_FAILED    = "failed"
+)
+
+const (
+	WEBHOOK_STATUS_PENDING   = "pending"
+	WEBHOOK_STATUS_DELIVERED = "delivered"
+	WEBHOOK_STATUS_FAILED    = "failed"
+)
+
+// JobError represents the domain error payload of a failed job.
+type JobError struct {
+	Code     string         `json:"code"`
+	Message  string         `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// WebhookDelivery represents the record of the callback sent when a job finishes.
+type WebhookDelivery struct {
+	URL       string    `json:"url"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError *string   `json:"lastError,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Job represents the state of an asynchronous job.
+type Job struct {
+	ID        string           `json:"id"`
+	Status    string           `json:"status"`
+	URL       *string          `json:"url,omitempty"`
+	FileSize  *int64           `json:"fileSize,omitempty"`
+	PageCount *int             `json:"pageCount,omitempty"`
+	CacheHit  *bool            `json:"cacheHit,omitempty"`
+	Error     *JobError        `json:"error,omitempty"`
+	Webhook   *WebhookDelivery `json:"webhook,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// JobStorage is an interface for storage operations related to asynchronous jobs.
+type JobStorage interface {
+	Set(ctx context.Context, job Job) error
+	Get(ctx context.Context, id string) (*Job, error)
+}
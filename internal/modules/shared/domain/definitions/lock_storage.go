@@ -0,0 +1,36 @@
+package definitions
+
+import (
+	"context"
+	"time"
+)
+
+// LockFailure is the error of the work guarded by a lock, recorded for the owners waiting for the lock
+type LockFailure struct {
+	Code     string         `json:"code,omitempty"` // Domain error code, empty if the error was not a domain one
+	Message  string         `json:"message"`
+	Metadata map[string]any `json:"metadata,omitempty"` // Domain error metadata, empty if the error was not a domain one
+}
+
+// Lock is a lock held by this replica
+type Lock interface {
+	// Renew extends the lease of the lock, failing if the lock was lost
+	Renew(ctx context.Context, lease time.Duration) error
+	// Release frees the lock if it is still held, recording the failure of the guarded work for as long as the lease,
+	// so the owners waiting for the lock get it instead of doing the work again, or clearing the failure of a
+	// previous owner if the work succeeded
+	Release(ctx context.Context, failure *LockFailure) error
+	// Abandon frees the lock if it is still held without recording nor clearing any failure,
+	// so the failure of the previous owner is still returned to the other owners waiting for the lock
+	Abandon(ctx context.Context) error
+}
+
+// LockStorage is an interface for the locks shared by every replica of the service.
+// The locks expire after their lease unless renewed, so they are freed if their owner crashes.
+type LockStorage interface {
+	// Acquire takes the lock of the key if it is free, returning nil if another owner holds it
+	Acquire(ctx context.Context, key string, lease time.Duration) (Lock, error)
+	// GetFailure returns the failure recorded by the last owner of the lock of the key, nil if it succeeded
+	// or the failure expired
+	GetFailure(ctx context.Context, key string) (*LockFailure, error)
+}
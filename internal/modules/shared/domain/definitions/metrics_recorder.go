@@ -0,0 +1,37 @@
+package definitions
+
+import "time"
+
+// Results of looking up a generated PDF in the cache
+const (
+	CACHE_LOOKUP_HIT   = "hit"   // The cached file exists and was returned
+	CACHE_LOOKUP_MISS  = "miss"  // There was no cached entry
+	CACHE_LOOKUP_STALE = "stale" // The cached entry pointed to a missing file and was evicted
+)
+
+// Outcomes of rendering a PDF item
+const (
+	RENDER_OUTCOME_SUCCESS = "success"
+	RENDER_OUTCOME_ERROR   = "error"
+)
+
+// MetricsRecorder is the interface for recording the operational metrics of the service.
+// It keeps the application decoupled from the metrics backend, so it can be replaced in tests.
+type MetricsRecorder interface {
+	// ObserveItemRender records the time spent rendering a single PDF item
+	ObserveItemRender(duration time.Duration, outcome string)
+	// ObserveMerge records the time spent merging the rendered items into a single PDF
+	ObserveMerge(duration time.Duration)
+	// ObserveUpload records the time spent uploading a file to cloud storage and its size
+	ObserveUpload(duration time.Duration, bytes int64)
+	// IncCacheLookup counts a lookup of a generated PDF in the cache by its result
+	IncCacheLookup(result string)
+	// ObservePoolWait records the time spent waiting for a browser page
+	ObservePoolWait(duration time.Duration)
+	// IncBrowserLaunch counts a launched browser
+	IncBrowserLaunch()
+	// IncBrowserClose counts a closed browser
+	IncBrowserClose()
+	// ObserveHTTPRequest records an HTTP request by its method, route template and status code
+	ObserveHTTPRequest(method string, route string, statusCode int, duration time.Duration)
+}
@@ -1,14 +1,27 @@
 package definitions
 
+import "context"
+
+// CachedFile identifies the file a cached URL points to
+type CachedFile struct {
+	FileFolder string `json:"fileFolder"`
+	FilePath   string `json:"filePath"`
+}
+
 type SetURLCacheRequest struct {
 	Key        string
 	Value      string
 	Expiration int64
+	File       CachedFile // Indexed so every URL pointing to the file can be purged at once
 }
 
 // UrlCacheStorage is an interface for cache storage operations related to links.
 type UrlCacheStorage interface {
-	Set(request SetURLCacheRequest) error
-	Get(key string) (*string, error)
-	Delete(key string) error
+	Set(ctx context.Context, request SetURLCacheRequest) error
+	Get(ctx context.Context, key string) (*string, error)
+	Delete(ctx context.Context, key string) error
+	// GetFile returns the file the cached URL points to, nil if the key is not cached
+	GetFile(ctx context.Context, key string) (*CachedFile, error)
+	// DeleteByFile removes every cached URL pointing to the file and returns how many were removed
+	DeleteByFile(ctx context.Context, file CachedFile) (int, error)
 }
@@ -0,0 +1,15 @@
+package definitions
+
+import "net"
+
+// URLPolicy is an interface for deciding which URLs the outgoing requests can reach,
+// so they cannot be used to reach internal services (SSRF).
+type URLPolicy interface {
+	// Check returns a domain error if the URL cannot be reached
+	Check(rawURL string) error
+
+	// CheckAddress returns an error if the IP a host resolved to cannot be connected to.
+	// It is checked right before connecting, so a host cannot point to an allowed IP when its URL
+	// is checked and to a private one when it is connected to (DNS rebinding).
+	CheckAddress(ip net.IP) error
+}
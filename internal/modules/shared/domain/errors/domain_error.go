@@ -2,6 +2,7 @@ package errors
 
 // DomainError is an interface that represents a domain error in the application.
 type DomainError interface {
+	error
 	Code() string
 	Message() string
 	Metadata() map[string]any
@@ -50,3 +51,8 @@ func (e *GenericDomainError) Message() string {
 func (e *GenericDomainError) Metadata() map[string]any {
 	return e.metadata
 }
+
+// Error returns the error message so domain errors can be returned as regular errors.
+func (e *GenericDomainError) Error() string {
+	return e.message
+}
@@ -0,0 +1,24 @@
+package errors
+
+// Domain error codes shared between the modules and the HTTP error handler
+const (
+	JOB_NOT_FOUND_ERROR_CODE  = "JOB_NOT_FOUND"
+	JOB_QUEUE_FULL_ERROR_CODE = "JOB_QUEUE_FULL"
+
+	REMOTE_URL_NOT_ALLOWED_ERROR_CODE = "REMOTE_URL_NOT_ALLOWED"
+
+	RENDER_TIMEOUT_ERROR_CODE      = "RENDER_TIMEOUT"
+	SCRIPT_ERROR_CODE              = "SCRIPT_ERROR"
+	BROWSER_UNAVAILABLE_ERROR_CODE = "BROWSER_UNAVAILABLE"
+
+	PRESIGNED_URL_EXPIRATION_NOT_VALID_ERROR_CODE = "PRESIGNED_URL_EXPIRATION_NOT_VALID"
+	PDF_NOT_FOUND_ERROR_CODE                      = "PDF_NOT_FOUND"
+
+	TEMPLATE_NOT_FOUND_ERROR_CODE     = "TEMPLATE_NOT_FOUND"
+	TEMPLATE_NOT_VALID_ERROR_CODE     = "TEMPLATE_NOT_VALID"
+	TEMPLATE_RENDER_FAILED_ERROR_CODE = "TEMPLATE_RENDER_FAILED"
+
+	STORED_FILE_NOT_FOUND_ERROR_CODE    = "STORED_FILE_NOT_FOUND"
+	FILE_SIGNATURE_NOT_VALID_ERROR_CODE = "FILE_SIGNATURE_NOT_VALID"
+	FILE_PATH_NOT_VALID_ERROR_CODE      = "FILE_PATH_NOT_VALID"
+)
@@ -13,6 +13,32 @@ import (
 const (
 	ENVIRONMENT_PRODUCTION  = "production"
 	ENVIRONMENT_DEVELOPMENT = "development"
+
+	STORAGE_DRIVER_S3    = "s3"
+	STORAGE_DRIVER_LOCAL = "local"
+	STORAGE_DRIVER_GCS   = "gcs"
+	STORAGE_DRIVER_AZURE = "azure"
+
+	RENDER_CACHE_DRIVER_NONE  = "none"
+	RENDER_CACHE_DRIVER_REDIS = "redis"
+	RENDER_CACHE_DRIVER_DISK  = "disk"
+
+	URL_CACHE_DRIVER_REDIS  = "redis"
+	URL_CACHE_DRIVER_MEMORY = "memory"
+	URL_CACHE_DRIVER_TIERED = "tiered"
+
+	COALESCING_DRIVER_NONE  = "none"
+	COALESCING_DRIVER_LOCAL = "local"
+	COALESCING_DRIVER_REDIS = "redis"
+
+	TEMPLATE_STORAGE_DRIVER_REDIS  = "redis"
+	TEMPLATE_STORAGE_DRIVER_MEMORY = "memory"
+
+	JOB_STORAGE_DRIVER_REDIS  = "redis"
+	JOB_STORAGE_DRIVER_MEMORY = "memory"
+
+	FILE_EXPIRATION_STORAGE_DRIVER_REDIS  = "redis"
+	FILE_EXPIRATION_STORAGE_DRIVER_MEMORY = "memory"
 )
 
 // EnvironmentSpec holds the configuration for the application environment.
@@ -27,17 +53,121 @@ type EnvironmentSpec struct {
 	MaxChromiumTabsPerBrowser int `split_words:"true" default:"4"`  // Max tabs per browser
 	MaxChromiumTabIdleSeconds int `split_words:"true" default:"30"` // Max seconds a tab can be idle
 
+	// Deadlines
+	RequestTimeoutSeconds    int `split_words:"true" default:"120"` // Max seconds a synchronous PDF generation request can take
+	RenderWaitTimeoutSeconds int `split_words:"true" default:"30"`  // Default max seconds to render each item
+
+	// Templates
+	TemplateStorageDriver string `split_words:"true" default:"redis"` // Backend storing the templates (redis/memory)
+
+	// Asynchronous jobs
+	JobStorageDriver    string `split_words:"true" default:"redis"` // Backend storing the job states (redis/memory)
+	JobWorkers          int    `split_words:"true" default:"2"`     // Number of workers processing jobs
+	JobQueueSize        int    `split_words:"true" default:"100"`   // Max jobs waiting for a worker
+	JobRetentionSeconds int64  `split_words:"true" default:"86400"` // Seconds a job state is kept
+	JobTimeoutSeconds   int    `split_words:"true" default:"600"`   // Max seconds a job can take
+
+	// Remote URL rendering
+	RemoteURLAllowedHosts         []string `split_words:"true"`                 // Hosts that can be rendered, any host if empty
+	RemoteURLDeniedHosts          []string `split_words:"true"`                 // Hosts that can never be rendered
+	RemoteURLAllowPrivateNetworks bool     `split_words:"true" default:"false"` // Whether private, loopback and link-local IPs can be reached
+
+	// Webhooks
+	HttpFetcherTimeoutSeconds         int      `split_words:"true" default:"10"`    // Timeout for outgoing HTTP requests
+	WebhookMaxAttempts                int      `split_words:"true" default:"5"`     // Max delivery attempts per webhook
+	WebhookInitialBackoffMilliseconds int      `split_words:"true" default:"1000"`  // Wait before the first retry, doubled on each retry
+	WebhookAllowedHosts               []string `split_words:"true"`                 // Hosts that can be notified, any host if empty
+	WebhookDeniedHosts                []string `split_words:"true"`                 // Hosts that can never be notified
+	WebhookAllowPrivateNetworks       bool     `split_words:"true" default:"false"` // Whether callbacks can reach private, loopback and link-local IPs
+
+	// Health checks
+	ReadinessCheckTimeoutSeconds int    `split_words:"true" default:"5"` // Max seconds each readiness check can take
+	HealthCheckS3Bucket          string `split_words:"true"`             // Bucket checked by the readiness probe, left out of it if empty
+	HealthCheckGcsBucket         string `split_words:"true"`             // Cloud Storage bucket checked by the readiness probe, left out of it if empty
+
+	// Metrics
+	MetricsListenAddress string `split_words:"true"`                 // Address serving /metrics on its own (E.g, ":9090"), served along with the API if empty
+	MetricsAuthRequired  bool   `split_words:"true" default:"false"` // Whether /metrics requires the API authentication
+
+	// Tracing
+	TracingExporter     string  `split_words:"true" default:"none"`         // Span exporter (none/stdout/otlp)
+	TracingServiceName  string  `split_words:"true" default:"serpentarius"` // Service name reported in the spans
+	TracingSampleRatio  float64 `split_words:"true" default:"1"`            // Ratio of the new traces that are sampled
+	TracingOtlpEndpoint string  `split_words:"true"`                        // OTLP collector host and port, the OTEL_EXPORTER_OTLP_* defaults if empty
+	TracingOtlpInsecure bool    `split_words:"true" default:"false"`        // Whether to send the spans over plain HTTP
+
+	// Storage
+	StorageDriver                          string `split_words:"true" default:"s3"`        // Backend storing the generated files (s3/local/gcs/azure)
+	LocalStorageRootPath                   string `split_words:"true" default:"./storage"` // Directory holding the files of the local storage
+	LocalStorageSigningSecret              string `split_words:"true"`                     // Secret signing the local file URLs, the URLs are not signed if empty
+	LocalStorageSignedURLExpirationSeconds int    `split_words:"true" default:"3600"`      // Seconds a signed local file URL is valid
+
+	// Retention of the generated files
+	FileRetentionSeconds        int64  `split_words:"true" default:"0"`     // Seconds the generated files are kept if the request does not say, forever if 0
+	FileCleanupIntervalSeconds  int    `split_words:"true" default:"300"`   // Seconds between the deletions of the expired files, disabled if 0
+	FileCleanupBatchSize        int    `split_words:"true" default:"100"`   // Expired files listed at once when cleaning up
+	FileExpirationStorageDriver string `split_words:"true" default:"redis"` // Backend recording when the files expire (redis/memory)
+
 	// AWS S3
-	AwsS3EndpointURL   string `split_words:"true" default:"https://s3.amazonaws.com"` // S3 endpoint URL
-	AwsAccessKeyID     string `required:"true" split_words:"true"`                    // S3 access key
-	AwsSecretAccessKey string `required:"true" split_words:"true"`                    // S3 secret key
-	AwsRegion          string `split_words:"true" default:"us-east-1"`                // S3 region
+	AwsS3EndpointURL       string `split_words:"true" default:"https://s3.amazonaws.com"` // S3 endpoint URL
+	AwsAccessKeyID         string `split_words:"true"`                                    // S3 access key, the default AWS credentials chain is used if empty
+	AwsSecretAccessKey     string `split_words:"true"`                                    // S3 secret key
+	AwsRegion              string `split_words:"true" default:"us-east-1"`                // S3 region
+	AwsS3UploadPartSizeMb  int    `split_words:"true" default:"8"`                        // Size of the parts of the multipart uploads, at least 5 MiB
+	AwsS3UploadConcurrency int    `split_words:"true" default:"5"`                        // Parts of the same file uploaded in parallel
+
+	// Google Cloud Storage
+	GcsEndpointURL           string `split_words:"true" default:"https://storage.googleapis.com"` // Cloud Storage JSON API endpoint URL
+	GcsWithoutAuthentication bool   `split_words:"true" default:"false"`                          // Whether to skip the application default credentials (E.g, for fake-gcs-server)
+	GcsServiceAccountKeyFile string `split_words:"true"`                                          // Service account key signing the presigned URLs
+
+	// Azure Blob Storage
+	AzureStorageConnectionString string `split_words:"true"` // Connection string, takes precedence over the account name and key
+	AzureStorageAccountName      string `split_words:"true"` // Storage account name
+	AzureStorageAccountKey       string `split_words:"true"` // Storage account shared key
+	AzureStorageEndpointURL      string `split_words:"true"` // Blob service endpoint URL, https://{account}.blob.core.windows.net if empty
+
+	// Cache
+	CacheKeyVersion          int    `split_words:"true" default:"1"`     // Version of the cache keys, bumped to invalidate every cached URL
+	UrlCacheDriver           string `split_words:"true" default:"redis"` // Backend caching the URLs (redis/memory/tiered)
+	UrlCacheMemoryMaxEntries int    `split_words:"true" default:"10000"` // URLs kept in memory, the least recently used ones are evicted beyond it
+	UrlCacheLocalTTLSeconds  int64  `split_words:"true" default:"60"`    // Seconds the tiered cache keeps a URL in memory at most, as long as in Redis if 0
+
+	// Render cache
+	RenderCacheDriver         string `split_words:"true" default:"none"`           // Backend caching the rendered PDFs (none/redis/disk)
+	RenderCacheMaxEntrySizeMb int    `split_words:"true" default:"10"`             // Size of the largest PDF that is cached
+	RenderCacheTTLSeconds     int    `split_words:"true" default:"3600"`           // Seconds a rendered PDF is cached, forever if 0
+	RenderCacheDiskPath       string `split_words:"true" default:"./render-cache"` // Directory holding the PDFs of the disk render cache
+	RenderCacheDiskMaxSizeMb  int    `split_words:"true" default:"1024"`           // Size of the disk render cache, the least recently used PDFs are evicted beyond it
+	RenderCacheItems          bool   `split_words:"true" default:"false"`          // Whether the PDFs of the single items are cached as well, so unchanged items are not rendered again
+
+	// Request coalescing
+	CoalescingDriver                   string `split_words:"true" default:"local"` // Scope in which identical concurrent requests are generated once (none/local/redis)
+	CoalescingLockLeaseSeconds         int    `split_words:"true" default:"30"`    // Seconds a replica holds the lock of a request without renewing it
+	CoalescingPollIntervalMilliseconds int    `split_words:"true" default:"250"`   // Milliseconds between the attempts to acquire a lock held by another replica
 
 	// Redis
-	RedisHost     string `required:"true" split_words:"true"` // Redis host
-	RedisPort     string `split_words:"true" default:"6379"`  // Redis port
-	RedisPassword string `required:"true" split_words:"true"` // Redis password
-	RedisDB       int    `split_words:"true" default:"0"`     // Redis DB number
+	RedisHost                   string   `split_words:"true" default:"localhost"` // Redis host, used if there are no addresses
+	RedisPort                   string   `split_words:"true" default:"6379"`      // Redis port, used if there are no addresses
+	RedisAddresses              []string `split_words:"true"`                     // Comma-separated host:port of the sentinels or the cluster seed nodes
+	RedisMasterName             string   `split_words:"true"`                     // Sentinel master name, the addresses are the sentinels if set
+	RedisClusterMode            bool     `split_words:"true" default:"false"`     // Whether the addresses are cluster seed nodes, implied by more than one address without master name
+	RedisUsername               string   `split_words:"true"`                     // Redis ACL username, the default user if empty
+	RedisPassword               string   `split_words:"true"`                     // Redis password, no password if empty
+	RedisDB                     int      `split_words:"true" default:"0"`         // Redis DB number, ignored in cluster mode
+	RedisSentinelUsername       string   `split_words:"true"`                     // ACL username of the sentinels
+	RedisSentinelPassword       string   `split_words:"true"`                     // Password of the sentinels
+	RedisTLSEnabled             bool     `split_words:"true" default:"false"`     // Whether the connections use TLS
+	RedisTLSCaFile              string   `split_words:"true"`                     // PEM file with the CAs verifying the server, the system ones if empty
+	RedisTLSCertFile            string   `split_words:"true"`                     // PEM file with the client certificate, for mutual TLS
+	RedisTLSKeyFile             string   `split_words:"true"`                     // PEM file with the key of the client certificate
+	RedisTLSServerName          string   `split_words:"true"`                     // Name the server certificate is verified against, the host of each address if empty
+	RedisPoolSize               int      `split_words:"true" default:"0"`         // Connections per node, 10 per CPU if 0
+	RedisMinIdleConns           int      `split_words:"true" default:"0"`         // Idle connections kept open per node
+	RedisMaxIdleConns           int      `split_words:"true" default:"0"`         // Idle connections kept per node at most, unlimited if 0
+	RedisPoolTimeoutSeconds     int      `split_words:"true" default:"0"`         // Seconds waiting for a free connection, the read timeout plus one if 0
+	RedisConnMaxIdleTimeSeconds int      `split_words:"true" default:"0"`         // Seconds a connection stays idle before being closed, 30 minutes if 0
+	RedisConnMaxLifetimeSeconds int      `split_words:"true" default:"0"`         // Seconds a connection is reused at most, forever if 0
 
 	// Authentication
 	AuthSecret string `required:"true" split_words:"true"` // Secret for JWT auth
@@ -58,6 +188,17 @@ func GetEnvironment() *EnvironmentSpec {
 	return environment
 }
 
+// UsesRedis returns whether any of the selected drivers keeps its data in Redis.
+func (e *EnvironmentSpec) UsesRedis() bool {
+	return e.TemplateStorageDriver == TEMPLATE_STORAGE_DRIVER_REDIS ||
+		e.JobStorageDriver == JOB_STORAGE_DRIVER_REDIS ||
+		e.FileExpirationStorageDriver == FILE_EXPIRATION_STORAGE_DRIVER_REDIS ||
+		e.UrlCacheDriver == URL_CACHE_DRIVER_REDIS ||
+		e.UrlCacheDriver == URL_CACHE_DRIVER_TIERED ||
+		e.RenderCacheDriver == RENDER_CACHE_DRIVER_REDIS ||
+		e.CoalescingDriver == COALESCING_DRIVER_REDIS
+}
+
 // loadFromEnvFile loads environment variables from a .env file if not in production.
 func loadFromEnvFile() {
 	execEnvironment := os.Getenv("ENVIRONMENT")
@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
@@ -8,9 +10,32 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// HTTP_STATUS_CLIENT_CLOSED_REQUEST is the non-standard status of the requests the client closed before they were processed
+const HTTP_STATUS_CLIENT_CLOSED_REQUEST = 499
+
 // domainErrorCodeToHTTPStatusCode maps error codes to HTTP status codes
 var domainErrorCodeToHTTPStatusCode = map[string]int{
 	"ERROR": http.StatusInternalServerError,
+
+	sharedErrors.JOB_NOT_FOUND_ERROR_CODE:  http.StatusNotFound,
+	sharedErrors.JOB_QUEUE_FULL_ERROR_CODE: http.StatusServiceUnavailable,
+
+	sharedErrors.REMOTE_URL_NOT_ALLOWED_ERROR_CODE: http.StatusUnprocessableEntity,
+
+	sharedErrors.RENDER_TIMEOUT_ERROR_CODE:      http.StatusGatewayTimeout,
+	sharedErrors.SCRIPT_ERROR_CODE:              http.StatusUnprocessableEntity,
+	sharedErrors.BROWSER_UNAVAILABLE_ERROR_CODE: http.StatusServiceUnavailable,
+
+	sharedErrors.PRESIGNED_URL_EXPIRATION_NOT_VALID_ERROR_CODE: http.StatusUnprocessableEntity,
+	sharedErrors.PDF_NOT_FOUND_ERROR_CODE:                      http.StatusNotFound,
+
+	sharedErrors.TEMPLATE_NOT_FOUND_ERROR_CODE:     http.StatusNotFound,
+	sharedErrors.TEMPLATE_NOT_VALID_ERROR_CODE:     http.StatusUnprocessableEntity,
+	sharedErrors.TEMPLATE_RENDER_FAILED_ERROR_CODE: http.StatusUnprocessableEntity,
+
+	sharedErrors.STORED_FILE_NOT_FOUND_ERROR_CODE:    http.StatusNotFound,
+	sharedErrors.FILE_SIGNATURE_NOT_VALID_ERROR_CODE: http.StatusForbidden,
+	sharedErrors.FILE_PATH_NOT_VALID_ERROR_CODE:      http.StatusBadRequest,
 }
 
 // ErrorHandlerMiddleware is a Gin middleware that handles errors returned by the application
@@ -22,33 +47,51 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 		if len(c.Errors) > 0 {
 			err := c.Errors[0]
 
+			// Handle requests the client closed, there is no one left to answer to
+			if errors.Is(err.Err, context.Canceled) {
+				sharedUtilities.
+					GetLogger().
+					WithError(err.Err).
+					Info("The request was canceled by the client")
+
+				c.AbortWithStatus(HTTP_STATUS_CLIENT_CLOSED_REQUEST)
+				return
+			}
+
 			sharedUtilities.
 				GetLogger().
 				WithError(err.Err).
 				Error("An error occurred while processing the request")
 
-			switch e := err.Err.(type) {
-			// Handle domain errors
-			case sharedErrors.DomainError:
-				domainErrorCode := e.Code()
+			// Handle requests that exceeded their deadline
+			if errors.Is(err.Err, context.DeadlineExceeded) {
+				c.JSON(http.StatusGatewayTimeout, gin.H{
+					"message": "The request took too long to be processed",
+				})
+				return
+			}
 
-				statusCode, isErrorCodeMapped := domainErrorCodeToHTTPStatusCode[domainErrorCode]
+			// Handle domain errors, even if they were wrapped
+			var domainError sharedErrors.DomainError
+			if errors.As(err.Err, &domainError) {
+				statusCode, isErrorCodeMapped := domainErrorCodeToHTTPStatusCode[domainError.Code()]
 				if !isErrorCodeMapped {
 					// If the error code is not mapped, use the default error code
 					statusCode = http.StatusInternalServerError
 				}
 
 				c.JSON(statusCode, gin.H{
-					"message":  e.Message(),
-					"metadata": e.Metadata(),
-				})
-			// Handle unexpected errors
-			default:
-				c.JSON(500, gin.H{
-					"message": "There was an error processing your request",
-					// "error":   e.Error(),
+					"message":  domainError.Message(),
+					"metadata": domainError.Metadata(),
 				})
+				return
 			}
+
+			// Handle unexpected errors
+			c.JSON(500, gin.H{
+				"message": "There was an error processing your request",
+				// "error":   err.Error(),
+			})
 		}
 	}
 }
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/gin-gonic/gin"
+)
+
+// UNMATCHED_ROUTE labels the requests that did not match any route, so unknown paths
+// cannot create an unbounded number of metric series
+const UNMATCHED_ROUTE = "unmatched"
+
+// MetricsMiddleware records the status code and duration of every request by its route template
+func MetricsMiddleware(recorder definitions.MetricsRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = UNMATCHED_ROUTE
+		}
+
+		recorder.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
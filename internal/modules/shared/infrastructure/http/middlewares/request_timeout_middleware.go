@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"context"
+	"time"
+
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeoutMiddleware bounds the request context with the REQUEST_TIMEOUT_SECONDS deadline.
+// It is only installed on the routes generating PDFs synchronously, so downloads and scrapes are not cut off.
+// Handlers must pass c.Request.Context() down the pipeline so the work is cancelled once the
+// deadline is exceeded or the client disconnects.
+func RequestTimeoutMiddleware() gin.HandlerFunc {
+	timeout := time.Duration(sharedInfrastructure.GetEnvironment().RequestTimeoutSeconds) * time.Second
+
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
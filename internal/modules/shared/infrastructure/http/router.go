@@ -1,15 +1,23 @@
 package http
 
 import (
+	healthHttp "github.com/PChaparro/serpentarius/internal/modules/health/infrastructure/http"
 	pdfHttp "github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http"
 	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
 	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	storageHttp "github.com/PChaparro/serpentarius/internal/modules/storage/infrastructure/http"
+	templateHttp "github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/http"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // moduleRegistries contains all routers to be registered
 var moduleRegistries = []RouterRegistry{
-	&pdfHttp.PDFRouter{}, // PDF module routes
+	&pdfHttp.PDFRouter{},           // PDF module routes
+	&templateHttp.TemplateRouter{}, // Template module routes
+	&healthHttp.HealthRouter{},     // Health module routes
+	&storageHttp.StorageRouter{},   // Storage module routes
 }
 
 // RouterRegistry registers routes of all modules
@@ -22,14 +30,47 @@ func RegisterRoutes() *gin.Engine {
 	// Start the router
 	router := gin.Default()
 
-	// Register global middlewares
+	// Register global middlewares, tracing first so the spans cover the whole request
+	infrastructure.SetupTracing()
+	router.Use(otelgin.Middleware(infrastructure.GetEnvironment().TracingServiceName))
+	router.Use(sharedMiddlewares.MetricsMiddleware(sharedImplementations.GetPrometheusMetricsRecorder()))
 	router.Use(sharedMiddlewares.ErrorHandlerMiddleware())
 
 	// Register all routes
 	apiV1 := router.Group("/api/v1")
 	for _, registry := range moduleRegistries {
 		registry.RegisterRoutes(apiV1)
+
+		// Some modules also expose routes outside the API prefix (E.g, the health probes)
+		if rootRegistry, ok := registry.(RootRouterRegistry); ok {
+			rootRegistry.RegisterRootRoutes(&router.RouterGroup)
+		}
+	}
+
+	// Serve the metrics along with the API unless they have their own address
+	if infrastructure.GetEnvironment().MetricsListenAddress == "" {
+		registerMetricsRoute(&router.RouterGroup)
 	}
 
 	return router
 }
+
+// RegisterMetricsRoutes returns a router serving only the Prometheus metrics, listening on METRICS_LISTEN_ADDRESS
+func RegisterMetricsRoutes() *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	registerMetricsRoute(&router.RouterGroup)
+
+	return router
+}
+
+// registerMetricsRoute serves the Prometheus metrics, behind the API authentication if METRICS_AUTH_REQUIRED is set
+func registerMetricsRoute(r *gin.RouterGroup) {
+	handlers := []gin.HandlerFunc{}
+	if infrastructure.GetEnvironment().MetricsAuthRequired {
+		handlers = append(handlers, sharedMiddlewares.AuthMiddleware())
+	}
+	handlers = append(handlers, gin.WrapH(sharedImplementations.GetPrometheusMetricsRecorder().Handler()))
+
+	r.GET("/metrics", handlers...)
+}
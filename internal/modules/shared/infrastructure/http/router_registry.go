@@ -6,3 +6,8 @@ import "github.com/gin-gonic/gin"
 type RouterRegistry interface {
 	RegisterRoutes(router *gin.RouterGroup)
 }
+
+// RootRouterRegistry defines the contract for modules that also register routes outside the API prefix
+type RootRouterRegistry interface {
+	RegisterRootRoutes(router *gin.RouterGroup)
+}
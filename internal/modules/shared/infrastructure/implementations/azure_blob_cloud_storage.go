@@ -0,0 +1,272 @@
+package implementations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// AZURE_COPY_POLL_INTERVAL is the time between the checks of a pending blob copy
+const AZURE_COPY_POLL_INTERVAL = 500 * time.Millisecond
+
+// AzureBlobCloudStorage implements the CloudStorage interface for Azure Blob Storage.
+// The file folder is used as the container and the file path as the blob name.
+type AzureBlobCloudStorage struct {
+	client *azblob.Client
+}
+
+var (
+	azureBlobCloudStorage     *AzureBlobCloudStorage
+	azureBlobCloudStorageOnce sync.Once
+
+	azureBlobClient     *azblob.Client
+	azureBlobClientOnce sync.Once
+)
+
+// GetAzureBlobCloudStorage returns a singleton instance of AzureBlobCloudStorage
+func GetAzureBlobCloudStorage() definitions.CloudStorage {
+	azureBlobCloudStorageOnce.Do(func() {
+		azureBlobCloudStorage = NewAzureBlobCloudStorage(GetAzureBlobClient())
+	})
+
+	return azureBlobCloudStorage
+}
+
+// NewAzureBlobCloudStorage creates an AzureBlobCloudStorage using the given client
+func NewAzureBlobCloudStorage(client *azblob.Client) *AzureBlobCloudStorage {
+	return &AzureBlobCloudStorage{
+		client: client,
+	}
+}
+
+// GetAzureBlobClient returns the Blob service client shared by every component talking to Azure
+func GetAzureBlobClient() *azblob.Client {
+	azureBlobClientOnce.Do(func() {
+		azureBlobClient = createAzureBlobClient()
+	})
+
+	return azureBlobClient
+}
+
+// createAzureBlobClient creates a client from the connection string if any, or the account name and key otherwise
+func createAzureBlobClient() *azblob.Client {
+	env := infrastructure.GetEnvironment()
+
+	if env.AzureStorageConnectionString != "" {
+		client, err := azblob.NewClientFromConnectionString(env.AzureStorageConnectionString, nil)
+		if err != nil {
+			panic("Unable to create Azure Blob client: " + err.Error())
+		}
+
+		return client
+	}
+
+	credential, err := azblob.NewSharedKeyCredential(env.AzureStorageAccountName, env.AzureStorageAccountKey)
+	if err != nil {
+		panic("Unable to load Azure Storage credentials: " + err.Error())
+	}
+
+	endpointURL := env.AzureStorageEndpointURL
+	if endpointURL == "" {
+		endpointURL = fmt.Sprintf("https://%s.blob.core.windows.net/", env.AzureStorageAccountName)
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(endpointURL, credential, nil)
+	if err != nil {
+		panic("Unable to create Azure Blob client: " + err.Error())
+	}
+
+	return client
+}
+
+// UploadFile uploads a file to the container
+func (a *AzureBlobCloudStorage) UploadFile(ctx context.Context, request definitions.UploadFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "AzureBlobCloudStorage.UploadFile", trace.WithAttributes(
+		attribute.String("azure.container", request.FileFolder),
+		attribute.String("azure.blob", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	_, err = a.client.UploadStream(ctx, request.FileFolder, request.FilePath, request.FileReader, buildUploadStreamOptions(request))
+	return err
+}
+
+// buildUploadStreamOptions converts the upload request to the blob options.
+// The server-side encryption is not set because Azure Storage always encrypts the blobs.
+func buildUploadStreamOptions(request definitions.UploadFileRequest) *azblob.UploadStreamOptions {
+	options := &azblob.UploadStreamOptions{
+		HTTPHeaders: buildBlobHTTPHeaders(request.FileOptions),
+		Metadata:    buildBlobMetadata(request.FileOptions),
+	}
+
+	if len(request.Tags) > 0 {
+		options.Tags = request.Tags
+	}
+	if request.StorageClass != "" {
+		accessTier := blob.AccessTier(request.StorageClass)
+		options.AccessTier = &accessTier
+	}
+
+	return options
+}
+
+// buildBlobHTTPHeaders returns the headers the blob is served with
+func buildBlobHTTPHeaders(options definitions.FileOptions) *blob.HTTPHeaders {
+	headers := &blob.HTTPHeaders{
+		BlobContentType: &options.ContentType,
+	}
+
+	if options.CacheControl != "" {
+		headers.BlobCacheControl = &options.CacheControl
+	}
+	if options.ContentDisposition != "" {
+		headers.BlobContentDisposition = &options.ContentDisposition
+	}
+
+	return headers
+}
+
+// buildBlobMetadata returns the metadata of the blob, nil if there is none
+func buildBlobMetadata(options definitions.FileOptions) map[string]*string {
+	if len(options.Metadata) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]*string, len(options.Metadata))
+	for key, value := range options.Metadata {
+		metadata[key] = &value
+	}
+
+	return metadata
+}
+
+// FileExists checks if the blob exists in the container
+func (a *AzureBlobCloudStorage) FileExists(ctx context.Context, request definitions.FileExistsRequest) (bool, error) {
+	_, err := a.client.ServiceClient().
+		NewContainerClient(request.FileFolder).
+		NewBlobClient(request.FilePath).
+		GetProperties(ctx, nil)
+
+	if err != nil {
+		// Check if the error is because the blob doesn't exist
+		if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound, bloberror.ResourceNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetDownloadURL returns the public URL of the blob, or a URL with a read-only service SAS.
+// Signing the SAS requires the client to use shared key credentials.
+func (a *AzureBlobCloudStorage) GetDownloadURL(ctx context.Context, request definitions.GetDownloadURLRequest) (*definitions.DownloadURL, error) {
+	if request.Mode != definitions.DOWNLOAD_URL_MODE_PRESIGNED {
+		return buildPublicDownloadURL(request), nil
+	}
+
+	expiresAt := time.Now().UTC().Add(request.Expiration)
+	sasURL, err := a.client.ServiceClient().
+		NewContainerClient(request.FileFolder).
+		NewBlobClient(request.FilePath).
+		GetSASURL(sas.BlobPermissions{Read: true}, expiresAt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error signing blob URL: %w", err)
+	}
+
+	return &definitions.DownloadURL{
+		URL:       sasURL,
+		ExpiresAt: &expiresAt,
+	}, nil
+}
+
+// CopyFile copies the blob within the storage account, waiting for the copy to finish.
+// The copy keeps the HTTP headers of the source, so the requested ones are set once it finishes.
+func (a *AzureBlobCloudStorage) CopyFile(ctx context.Context, request definitions.CopyFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "AzureBlobCloudStorage.CopyFile", trace.WithAttributes(
+		attribute.String("azure.source_container", request.SourceFileFolder),
+		attribute.String("azure.source_blob", request.SourceFilePath),
+		attribute.String("azure.container", request.FileFolder),
+		attribute.String("azure.blob", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	serviceClient := a.client.ServiceClient()
+	sourceURL := serviceClient.NewContainerClient(request.SourceFileFolder).NewBlobClient(request.SourceFilePath).URL()
+	blobClient := serviceClient.NewContainerClient(request.FileFolder).NewBlobClient(request.FilePath)
+
+	options := &blob.StartCopyFromURLOptions{
+		Metadata: buildBlobMetadata(request.FileOptions),
+	}
+	if len(request.Tags) > 0 {
+		options.BlobTags = request.Tags
+	}
+	if request.StorageClass != "" {
+		accessTier := blob.AccessTier(request.StorageClass)
+		options.Tier = &accessTier
+	}
+
+	response, err := blobClient.StartCopyFromURL(ctx, sourceURL, options)
+	if err != nil {
+		return fmt.Errorf("error starting blob copy: %w", err)
+	}
+
+	// Copies within the same account usually finish right away, but they can be pending
+	copyStatus := response.CopyStatus
+	for copyStatus != nil && *copyStatus == blob.CopyStatusTypePending {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(AZURE_COPY_POLL_INTERVAL):
+		}
+
+		properties, err := blobClient.GetProperties(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("error getting blob copy status: %w", err)
+		}
+		copyStatus = properties.CopyStatus
+	}
+	if copyStatus != nil && *copyStatus != blob.CopyStatusTypeSuccess {
+		return fmt.Errorf("blob copy finished with status %s", *copyStatus)
+	}
+
+	if _, err = blobClient.SetHTTPHeaders(ctx, *buildBlobHTTPHeaders(request.FileOptions), nil); err != nil {
+		return fmt.Errorf("error setting blob headers: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFile deletes the blob from the container, succeeding if it does not exist
+func (a *AzureBlobCloudStorage) DeleteFile(ctx context.Context, request definitions.DeleteFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "AzureBlobCloudStorage.DeleteFile", trace.WithAttributes(
+		attribute.String("azure.container", request.FileFolder),
+		attribute.String("azure.blob", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	_, err = a.client.DeleteBlob(ctx, request.FileFolder, request.FilePath, nil)
+	if err != nil && bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound, bloberror.ResourceNotFound) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,33 @@
+package implementations
+
+import (
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// GetCloudStorage returns the CloudStorage implementation selected by the STORAGE_DRIVER environment variable
+func GetCloudStorage() definitions.CloudStorage {
+	storageDriver := infrastructure.GetEnvironment().StorageDriver
+
+	switch storageDriver {
+	case infrastructure.STORAGE_DRIVER_S3:
+		return GetS3CloudStorage()
+	case infrastructure.STORAGE_DRIVER_LOCAL:
+		return GetLocalCloudStorage()
+	case infrastructure.STORAGE_DRIVER_GCS:
+		return GetGCSCloudStorage()
+	case infrastructure.STORAGE_DRIVER_AZURE:
+		return GetAzureBlobCloudStorage()
+	default:
+		panic("Unknown storage driver: " + storageDriver)
+	}
+}
+
+// buildPublicDownloadURL builds the URL of a publicly readable file from the public URL prefix
+func buildPublicDownloadURL(request definitions.GetDownloadURLRequest) *definitions.DownloadURL {
+	return &definitions.DownloadURL{
+		URL: fmt.Sprintf("%s/%s/%s", request.PublicURLPrefix, request.FileFolder, request.FilePath),
+	}
+}
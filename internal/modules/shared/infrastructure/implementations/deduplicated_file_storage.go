@@ -0,0 +1,21 @@
+package implementations
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// GetDeduplicatedFileStorage returns the DeduplicatedFileStorage implementation selected by the
+// FILE_EXPIRATION_STORAGE_DRIVER environment variable, which records the copies along with the expirations
+func GetDeduplicatedFileStorage() definitions.DeduplicatedFileStorage {
+	fileExpirationStorageDriver := infrastructure.GetEnvironment().FileExpirationStorageDriver
+
+	switch fileExpirationStorageDriver {
+	case infrastructure.FILE_EXPIRATION_STORAGE_DRIVER_REDIS:
+		return GetRedisDeduplicatedFileStorage()
+	case infrastructure.FILE_EXPIRATION_STORAGE_DRIVER_MEMORY:
+		return GetInMemoryDeduplicatedFileStorage()
+	default:
+		panic("Unknown file expiration storage driver: " + fileExpirationStorageDriver)
+	}
+}
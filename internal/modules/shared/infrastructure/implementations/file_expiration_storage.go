@@ -0,0 +1,21 @@
+package implementations
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// GetFileExpirationStorage returns the FileExpirationStorage implementation selected by the
+// FILE_EXPIRATION_STORAGE_DRIVER environment variable
+func GetFileExpirationStorage() definitions.FileExpirationStorage {
+	fileExpirationStorageDriver := infrastructure.GetEnvironment().FileExpirationStorageDriver
+
+	switch fileExpirationStorageDriver {
+	case infrastructure.FILE_EXPIRATION_STORAGE_DRIVER_REDIS:
+		return GetRedisFileExpirationStorage()
+	case infrastructure.FILE_EXPIRATION_STORAGE_DRIVER_MEMORY:
+		return GetInMemoryFileExpirationStorage()
+	default:
+		panic("Unknown file expiration storage driver: " + fileExpirationStorageDriver)
+	}
+}
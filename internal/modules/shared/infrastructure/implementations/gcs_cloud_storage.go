@@ -0,0 +1,244 @@
+package implementations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/jwt"
+	"google.golang.org/api/option"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// GCS_MAX_SIGNED_URL_EXPIRATION is the maximum lifetime of a V4 signed URL
+const GCS_MAX_SIGNED_URL_EXPIRATION = 7 * 24 * time.Hour
+
+// GCSCloudStorage implements the CloudStorage interface for Google Cloud Storage.
+// It uses the Cloud Storage client library, whose JSON API is also served by emulators such as fake-gcs-server.
+type GCSCloudStorage struct {
+	client     *storage.Client
+	signingKey *jwt.Config // Service account key signing the presigned URLs, nil if none is configured
+}
+
+var (
+	gcsCloudStorage     *GCSCloudStorage
+	gcsCloudStorageOnce sync.Once
+
+	gcsClient     *storage.Client
+	gcsClientOnce sync.Once
+)
+
+// GetGCSCloudStorage returns a singleton instance of GCSCloudStorage
+func GetGCSCloudStorage() definitions.CloudStorage {
+	gcsCloudStorageOnce.Do(func() {
+		gcsCloudStorage = NewGCSCloudStorage(GetGCSClient(), loadGCSSigningKey())
+	})
+
+	return gcsCloudStorage
+}
+
+// NewGCSCloudStorage creates a GCSCloudStorage with the given client.
+// The signing key can be nil if presigned URLs are not needed.
+func NewGCSCloudStorage(client *storage.Client, signingKey *jwt.Config) *GCSCloudStorage {
+	return &GCSCloudStorage{
+		client:     client,
+		signingKey: signingKey,
+	}
+}
+
+// loadGCSSigningKey loads the configured service account key, or returns nil if there is none
+func loadGCSSigningKey() *jwt.Config {
+	keyFile := infrastructure.GetEnvironment().GcsServiceAccountKeyFile
+	if keyFile == "" {
+		return nil
+	}
+
+	serviceAccountKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		panic("Unable to read Google Cloud service account key: " + err.Error())
+	}
+
+	signingKey, err := google.JWTConfigFromJSON(serviceAccountKey)
+	if err != nil {
+		panic("Unable to load Google Cloud service account key: " + err.Error())
+	}
+
+	return signingKey
+}
+
+// GetGCSClient returns the Cloud Storage client shared by every component talking to Cloud Storage
+func GetGCSClient() *storage.Client {
+	gcsClientOnce.Do(func() {
+		gcsClient = createGCSClient()
+	})
+
+	return gcsClient
+}
+
+// createGCSClient creates a client for the configured endpoint, authenticated with the application default credentials
+func createGCSClient() *storage.Client {
+	env := infrastructure.GetEnvironment()
+
+	options := []option.ClientOption{
+		option.WithEndpoint(strings.TrimSuffix(env.GcsEndpointURL, "/") + "/storage/v1/"),
+		option.WithScopes(storage.ScopeReadWrite),
+	}
+	if env.GcsWithoutAuthentication {
+		options = append(options, option.WithoutAuthentication())
+	}
+
+	client, err := storage.NewClient(context.Background(), options...)
+	if err != nil {
+		panic("Unable to create Google Cloud Storage client: " + err.Error())
+	}
+
+	return client
+}
+
+// setGCSObjectAttrs sets the options of the object to the attributes sent along its upload or copy.
+// Cloud Storage has no object tags and always encrypts the objects, so those options are not sent.
+func setGCSObjectAttrs(attrs *storage.ObjectAttrs, options definitions.FileOptions) {
+	attrs.ContentType = options.ContentType
+	attrs.Metadata = options.Metadata
+	attrs.CacheControl = options.CacheControl
+	attrs.ContentDisposition = options.ContentDisposition
+	attrs.StorageClass = options.StorageClass
+}
+
+// UploadFile uploads a file to the bucket along with its object options.
+// The content is streamed from the reader, so it is never held in memory.
+func (g *GCSCloudStorage) UploadFile(ctx context.Context, request definitions.UploadFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "GCSCloudStorage.UploadFile", trace.WithAttributes(
+		attribute.String("gcs.bucket", request.FileFolder),
+		attribute.String("gcs.object", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	// Cancelling the context aborts the upload, so a partial object is never written
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := g.client.Bucket(request.FileFolder).Object(request.FilePath).NewWriter(ctx)
+	setGCSObjectAttrs(&writer.ObjectAttrs, request.FileOptions)
+
+	if _, err := io.Copy(writer, request.FileReader); err != nil {
+		return fmt.Errorf("error uploading object: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error uploading object: %w", err)
+	}
+
+	return nil
+}
+
+// FileExists checks if the object exists in the bucket
+func (g *GCSCloudStorage) FileExists(ctx context.Context, request definitions.FileExistsRequest) (bool, error) {
+	_, err := g.client.Bucket(request.FileFolder).Object(request.FilePath).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting object metadata: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetDownloadURL returns the public URL of the file, or a V4 signed GET URL valid for at most seven days
+func (g *GCSCloudStorage) GetDownloadURL(ctx context.Context, request definitions.GetDownloadURLRequest) (*definitions.DownloadURL, error) {
+	if request.Mode != definitions.DOWNLOAD_URL_MODE_PRESIGNED {
+		return buildPublicDownloadURL(request), nil
+	}
+
+	if g.signingKey == nil {
+		return nil, fmt.Errorf("a service account key is required to sign cloud storage URLs")
+	}
+
+	expiresAt := time.Now().Add(min(request.Expiration, GCS_MAX_SIGNED_URL_EXPIRATION))
+
+	signedURL, err := g.client.Bucket(request.FileFolder).SignedURL(request.FilePath, &storage.SignedURLOptions{
+		Scheme:         storage.SigningSchemeV4,
+		Method:         http.MethodGet,
+		GoogleAccessID: g.signingKey.Email,
+		PrivateKey:     g.signingKey.PrivateKey,
+		Expires:        expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing cloud storage URL: %w", err)
+	}
+
+	return &definitions.DownloadURL{
+		URL:       signedURL,
+		ExpiresAt: &expiresAt,
+	}, nil
+}
+
+// CopyFile copies the object within Cloud Storage with the given object options, without downloading it.
+// The copier keeps rewriting the object until it is done, as copying large objects or across locations takes several requests.
+func (g *GCSCloudStorage) CopyFile(ctx context.Context, request definitions.CopyFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "GCSCloudStorage.CopyFile", trace.WithAttributes(
+		attribute.String("gcs.source_bucket", request.SourceFileFolder),
+		attribute.String("gcs.source_object", request.SourceFilePath),
+		attribute.String("gcs.bucket", request.FileFolder),
+		attribute.String("gcs.object", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	source := g.client.Bucket(request.SourceFileFolder).Object(request.SourceFilePath)
+	copier := g.client.Bucket(request.FileFolder).Object(request.FilePath).CopierFrom(source)
+	setGCSObjectAttrs(&copier.ObjectAttrs, request.FileOptions)
+
+	if _, err := copier.Run(ctx); err != nil {
+		return fmt.Errorf("error copying object: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFile deletes the object from the bucket, succeeding if it does not exist
+func (g *GCSCloudStorage) DeleteFile(ctx context.Context, request definitions.DeleteFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "GCSCloudStorage.DeleteFile", trace.WithAttributes(
+		attribute.String("gcs.bucket", request.FileFolder),
+		attribute.String("gcs.object", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	err = g.client.Bucket(request.FileFolder).Object(request.FilePath).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("error deleting object: %w", err)
+	}
+
+	return nil
+}
+
+// BucketExists checks if the bucket exists and can be reached with the configured credentials
+func (g *GCSCloudStorage) BucketExists(ctx context.Context, bucket string) (bool, error) {
+	_, err := g.client.Bucket(bucket).Attrs(ctx)
+	if errors.Is(err, storage.ErrBucketNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting bucket metadata: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,164 @@
+package implementations
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+const (
+	// HTTP_FETCHER_MAX_RESPONSE_BYTES caps the response bodies read, as they come from external services
+	HTTP_FETCHER_MAX_RESPONSE_BYTES = 1 << 20
+	// HTTP_FETCHER_MAX_REDIRECTS is the number of redirects followed before failing, like the standard HTTP client
+	HTTP_FETCHER_MAX_REDIRECTS = 10
+	// HTTP_FETCHER_DIAL_TIMEOUT is the maximum time to connect to the requested hosts
+	HTTP_FETCHER_DIAL_TIMEOUT = 30 * time.Second
+)
+
+// HTTPFetcher implements the Fetcher interface using the standard HTTP client.
+// The requested URLs and every redirect are checked against the URL policy, if any,
+// as well as the addresses their hosts resolve to when they are connected to.
+type HTTPFetcher struct {
+	client *http.Client
+	policy definitions.URLPolicy // Policy of the URLs that can be reached, any URL if nil
+}
+
+var (
+	httpFetcher     *HTTPFetcher
+	httpFetcherOnce sync.Once
+)
+
+// NewHTTPFetcher creates an HTTPFetcher whose requests time out after the given duration,
+// reaching only the URLs allowed by the policy, or any URL if it is nil
+func NewHTTPFetcher(timeout time.Duration, policy definitions.URLPolicy) *HTTPFetcher {
+	fetcher := &HTTPFetcher{policy: policy}
+	dialer := &net.Dialer{
+		Timeout: HTTP_FETCHER_DIAL_TIMEOUT,
+		Control: fetcher.checkAddress,
+	}
+
+	// Connect to the hosts directly, as the addresses of an environment proxy would be checked instead
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	fetcher.client = &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: fetcher.checkRedirect,
+	}
+
+	return fetcher
+}
+
+// GetHTTPFetcher returns the singleton instance of HTTPFetcher, reaching only the URLs allowed by the policy.
+// The policy of the first call is kept, later calls return the already created fetcher.
+func GetHTTPFetcher(policy definitions.URLPolicy) definitions.Fetcher {
+	httpFetcherOnce.Do(func() {
+		httpFetcher = NewHTTPFetcher(
+			time.Duration(infrastructure.GetEnvironment().HttpFetcherTimeoutSeconds)*time.Second,
+			policy,
+		)
+	})
+
+	return httpFetcher
+}
+
+// checkURL returns the domain error of the policy if the URL cannot be reached
+func (f *HTTPFetcher) checkURL(rawURL string) error {
+	if f.policy == nil {
+		return nil
+	}
+
+	return f.policy.Check(rawURL)
+}
+
+// checkAddress refuses the addresses the policy does not allow.
+// It is called once the host is resolved, right before connecting to the address.
+func (f *HTTPFetcher) checkAddress(network string, address string, _ syscall.RawConn) error {
+	if f.policy == nil {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("error parsing the address %q: %w", address, err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.New("the address is not an IP")
+	}
+
+	return f.policy.CheckAddress(ip)
+}
+
+// checkRedirect stops following the redirects to URLs that cannot be reached, or after too many redirects
+func (f *HTTPFetcher) checkRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= HTTP_FETCHER_MAX_REDIRECTS {
+		return fmt.Errorf("stopped after %d redirects", HTTP_FETCHER_MAX_REDIRECTS)
+	}
+
+	return f.checkURL(request.URL.String())
+}
+
+// Get fetches the content of the given URL
+func (f *HTTPFetcher) Get(ctx context.Context, request definitions.GetRequest) ([]byte, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, request.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GET request: %w", err)
+	}
+
+	return f.do(httpRequest, request.Headers)
+}
+
+// Post sends the given content to the URL and returns the response content
+func (f *HTTPFetcher) Post(ctx context.Context, request definitions.PostRequest) ([]byte, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating POST request: %w", err)
+	}
+
+	return f.do(httpRequest, request.Headers)
+}
+
+// do sends the request with the given headers and reads up to HTTP_FETCHER_MAX_RESPONSE_BYTES of the response body,
+// failing if the URL cannot be reached or the response status code is not 2xx
+func (f *HTTPFetcher) do(httpRequest *http.Request, headers map[string]string) ([]byte, error) {
+	if err := f.checkURL(httpRequest.URL.String()); err != nil {
+		return nil, err
+	}
+
+	for headerName, headerContent := range headers {
+		httpRequest.Header.Set(headerName, headerContent)
+	}
+
+	response, err := f.client.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, HTTP_FETCHER_MAX_RESPONSE_BYTES))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return body, fmt.Errorf("unexpected response status code %d", response.StatusCode)
+	}
+
+	return body, nil
+}
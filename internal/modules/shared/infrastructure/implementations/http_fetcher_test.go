@@ -0,0 +1,60 @@
+package implementations
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/stretchr/testify/assert"
+)
+
+// errLoopbackAddress is returned by the loopbackDenyingPolicy for the loopback addresses
+var errLoopbackAddress = errors.New("loopback address")
+
+// loopbackDenyingPolicy is a URLPolicy allowing any URL, like a policy checking a host that resolves to a
+// public IP, while refusing to connect to the loopback addresses
+type loopbackDenyingPolicy struct{}
+
+// Check allows any URL
+func (p loopbackDenyingPolicy) Check(rawURL string) error {
+	return nil
+}
+
+// CheckAddress refuses the loopback addresses
+func (p loopbackDenyingPolicy) CheckAddress(ip net.IP) error {
+	if ip.IsLoopback() {
+		return errLoopbackAddress
+	}
+
+	return nil
+}
+
+// TestHTTPFetcher_CheckAddress tests the addresses the hosts resolve to are checked when they are connected to
+func TestHTTPFetcher_CheckAddress(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if !assert.NoError(t, err, "Server URL should be valid") {
+		return
+	}
+	// The host passes the URL check, but resolves to loopback when the fetcher connects to it
+	localhostURL := "http://localhost:" + serverURL.Port()
+
+	_, err = NewHTTPFetcher(0, loopbackDenyingPolicy{}).Post(context.Background(), definitions.PostRequest{URL: localhostURL})
+	assert.ErrorIs(t, err, errLoopbackAddress, "Host resolving to loopback should be refused")
+	assert.Equal(t, int32(0), requests.Load(), "Refused host should not be reached")
+
+	_, err = NewHTTPFetcher(0, nil).Post(context.Background(), definitions.PostRequest{URL: localhostURL})
+	assert.NoError(t, err, "Any address should be reached without a policy")
+	assert.Equal(t, int32(1), requests.Load(), "Host should be reached without a policy")
+}
@@ -0,0 +1,174 @@
+package implementations
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// inMemoryCacheEntry is a URL cached in memory
+type inMemoryCacheEntry struct {
+	key       string
+	value     string
+	file      definitions.CachedFile
+	expiresAt time.Time // Zero if the URL never expires
+}
+
+// isExpired returns whether the URL expired at the given time
+func (e *inMemoryCacheEntry) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// InMemoryCacheStorage implements the UrlCacheStorage interface keeping the URLs in the process memory,
+// evicting the least recently used ones beyond the max entries. The expired URLs are removed when read.
+// It is intended for tests and single-node setups, as the URLs are lost when the process stops.
+type InMemoryCacheStorage struct {
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element                       // Elements of the recency list, indexed by key
+	recency *list.List                                     // Entries from the most to the least recently used
+	files   map[definitions.CachedFile]map[string]struct{} // Keys of the URLs pointing to each file
+}
+
+var (
+	inMemoryCacheStorage     *InMemoryCacheStorage
+	inMemoryCacheStorageOnce sync.Once
+)
+
+// NewInMemoryCacheStorage creates an empty InMemoryCacheStorage holding up to the given URLs
+func NewInMemoryCacheStorage(maxEntries int) *InMemoryCacheStorage {
+	return &InMemoryCacheStorage{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+		files:      make(map[definitions.CachedFile]map[string]struct{}),
+	}
+}
+
+// GetInMemoryCacheStorage returns a singleton instance of InMemoryCacheStorage configured from the environment
+func GetInMemoryCacheStorage() definitions.UrlCacheStorage {
+	inMemoryCacheStorageOnce.Do(func() {
+		env := infrastructure.GetEnvironment()
+		inMemoryCacheStorage = NewInMemoryCacheStorage(env.UrlCacheMemoryMaxEntries)
+	})
+
+	return inMemoryCacheStorage
+}
+
+// Set stores the URL with an optional expiration time, evicting the least recently used URL if the cache is full
+func (s *InMemoryCacheStorage) Set(ctx context.Context, request definitions.SetURLCacheRequest) error {
+	entry := &inMemoryCacheEntry{
+		key:   request.Key,
+		value: request.Value,
+		file:  request.File,
+	}
+	if request.Expiration > 0 {
+		entry.expiresAt = time.Now().Add(time.Duration(request.Expiration) * time.Second)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, isCached := s.entries[request.Key]; isCached {
+		s.remove(element)
+	}
+
+	s.entries[entry.key] = s.recency.PushFront(entry)
+	if s.files[entry.file] == nil {
+		s.files[entry.file] = make(map[string]struct{})
+	}
+	s.files[entry.file][entry.key] = struct{}{}
+
+	for s.recency.Len() > s.maxEntries {
+		s.remove(s.recency.Back())
+	}
+
+	return nil
+}
+
+// Get retrieves the URL by key, nil if it is not cached or expired
+func (s *InMemoryCacheStorage) Get(ctx context.Context, key string) (*string, error) {
+	entry := s.lookup(key)
+	if entry == nil {
+		return nil, nil
+	}
+
+	return &entry.value, nil
+}
+
+// Delete removes the URL and unindexes it from its file
+func (s *InMemoryCacheStorage) Delete(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, isCached := s.entries[key]; isCached {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+// GetFile retrieves the file the cached URL points to, nil if it is not cached or expired
+func (s *InMemoryCacheStorage) GetFile(ctx context.Context, key string) (*definitions.CachedFile, error) {
+	entry := s.lookup(key)
+	if entry == nil {
+		return nil, nil
+	}
+
+	return &entry.file, nil
+}
+
+// DeleteByFile removes every cached URL pointing to the file, counting the ones that had not expired yet
+func (s *InMemoryCacheStorage) DeleteByFile(ctx context.Context, file definitions.CachedFile) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	deleted := 0
+	for key := range s.files[file] {
+		element := s.entries[key]
+		if !element.Value.(*inMemoryCacheEntry).isExpired(now) {
+			deleted++
+		}
+		s.remove(element)
+	}
+
+	return deleted, nil
+}
+
+// lookup returns the entry of the key, marking it as the most recently used, or nil if it is not cached or expired
+func (s *InMemoryCacheStorage) lookup(key string) *inMemoryCacheEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, isCached := s.entries[key]
+	if !isCached {
+		return nil
+	}
+
+	entry := element.Value.(*inMemoryCacheEntry)
+	if entry.isExpired(time.Now()) {
+		s.remove(element)
+		return nil
+	}
+	s.recency.MoveToFront(element)
+
+	return entry
+}
+
+// remove deletes the entry of the element and unindexes it from its file.
+// The caller must hold the mutex.
+func (s *InMemoryCacheStorage) remove(element *list.Element) {
+	entry := s.recency.Remove(element).(*inMemoryCacheEntry)
+	delete(s.entries, entry.key)
+
+	delete(s.files[entry.file], entry.key)
+	if len(s.files[entry.file]) == 0 {
+		delete(s.files, entry.file)
+	}
+}
@@ -0,0 +1,65 @@
+package implementations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInMemoryCacheStorage_LeastRecentlyUsed tests the least recently read URL is evicted first,
+// and evicted URLs are unindexed from their file
+func TestInMemoryCacheStorage_LeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	storage := NewInMemoryCacheStorage(2)
+	report := definitions.CachedFile{FileFolder: "reports", FilePath: "report.pdf"}
+	invoice := definitions.CachedFile{FileFolder: "reports", FilePath: "invoice.pdf"}
+
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "first", Value: "https://cdn.example.com/first", File: report})
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "second", Value: "https://cdn.example.com/second", File: invoice})
+
+	// Reading the first URL makes the second one the least recently used
+	first, _ := storage.Get(ctx, "first")
+	assert.NotNil(t, first, "First URL should be cached")
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "third", Value: "https://cdn.example.com/third", File: report})
+
+	second, _ := storage.Get(ctx, "second")
+	assert.Nil(t, second, "Least recently used URL should be evicted")
+	assert.NotContains(t, storage.files, invoice, "Evicted URL should be unindexed from its file")
+	assert.Len(t, storage.files[report], 2, "Remaining URLs should stay indexed")
+
+	// Replacing a URL does not count twice
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "third", Value: "https://cdn.example.com/third-v2", File: report})
+	assert.Equal(t, 2, storage.recency.Len(), "Replaced URL should not be duplicated")
+	first, _ = storage.Get(ctx, "first")
+	assert.NotNil(t, first, "Replacing a URL should not evict the others")
+}
+
+// TestInMemoryCacheStorage_TTL tests the expired URLs are removed when read and not counted when purged
+func TestInMemoryCacheStorage_TTL(t *testing.T) {
+	ctx := context.Background()
+	storage := NewInMemoryCacheStorage(10)
+	report := definitions.CachedFile{FileFolder: "reports", FilePath: "report.pdf"}
+
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "expired", Value: "https://cdn.example.com/expired", Expiration: 60, File: report})
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "alive", Value: "https://cdn.example.com/alive", Expiration: 60, File: report})
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "forever", Value: "https://cdn.example.com/forever", File: report})
+
+	// Move the expiration of the first URL to the past instead of waiting for it
+	storage.entries["expired"].Value.(*inMemoryCacheEntry).expiresAt = time.Now().Add(-time.Second)
+	assert.True(t, storage.entries["forever"].Value.(*inMemoryCacheEntry).expiresAt.IsZero(), "URL without expiration should never expire")
+
+	deleted, err := storage.DeleteByFile(ctx, report)
+	assert.NoError(t, err, "Purging the URLs of a file should succeed")
+	assert.Equal(t, 2, deleted, "Only the URLs not expired yet should be counted")
+	assert.Empty(t, storage.entries, "Every URL of the file should be removed")
+	assert.Empty(t, storage.files, "File should be unindexed")
+
+	_ = storage.Set(ctx, definitions.SetURLCacheRequest{Key: "expired", Value: "https://cdn.example.com/expired", Expiration: 60, File: report})
+	storage.entries["expired"].Value.(*inMemoryCacheEntry).expiresAt = time.Now().Add(-time.Second)
+	file, _ := storage.GetFile(ctx, "expired")
+	assert.Nil(t, file, "File of an expired URL should not be returned")
+	assert.NotContains(t, storage.entries, "expired", "Expired URL should be removed when read")
+}
@@ -0,0 +1,91 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+)
+
+// InMemoryDeduplicatedFileStorage implements the DeduplicatedFileStorage interface keeping the copies in the process memory.
+// It is intended for tests and single-node setups, as the copies are lost when the process stops,
+// so the deduplicated files copied before a restart are only deleted once they expire.
+type InMemoryDeduplicatedFileStorage struct {
+	mutex   sync.Mutex
+	sources map[definitions.FileCopy]definitions.DeduplicatedFile
+	copies  map[definitions.DeduplicatedFile]int
+}
+
+var (
+	inMemoryDeduplicatedFileStorage     definitions.DeduplicatedFileStorage
+	inMemoryDeduplicatedFileStorageOnce sync.Once
+)
+
+// NewInMemoryDeduplicatedFileStorage creates a new, empty InMemoryDeduplicatedFileStorage
+func NewInMemoryDeduplicatedFileStorage() definitions.DeduplicatedFileStorage {
+	return &InMemoryDeduplicatedFileStorage{
+		sources: make(map[definitions.FileCopy]definitions.DeduplicatedFile),
+		copies:  make(map[definitions.DeduplicatedFile]int),
+	}
+}
+
+// GetInMemoryDeduplicatedFileStorage returns a singleton instance of InMemoryDeduplicatedFileStorage
+func GetInMemoryDeduplicatedFileStorage() definitions.DeduplicatedFileStorage {
+	inMemoryDeduplicatedFileStorageOnce.Do(func() {
+		inMemoryDeduplicatedFileStorage = NewInMemoryDeduplicatedFileStorage()
+	})
+
+	return inMemoryDeduplicatedFileStorage
+}
+
+// AddCopy records the file as a copy of the deduplicated file, returning the previous source if no copy of it is left
+func (s *InMemoryDeduplicatedFileStorage) AddCopy(
+	ctx context.Context,
+	source definitions.DeduplicatedFile,
+	fileCopy definitions.FileCopy,
+) (*definitions.DeduplicatedFile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, isCopy := s.sources[fileCopy]
+	if isCopy && previous == source {
+		return nil, nil
+	}
+
+	s.sources[fileCopy] = source
+	s.copies[source]++
+	if !isCopy {
+		return nil, nil
+	}
+
+	return s.release(previous), nil
+}
+
+// RemoveCopy removes the record of the copy, returning its source if no copy of it is left
+func (s *InMemoryDeduplicatedFileStorage) RemoveCopy(
+	ctx context.Context,
+	fileCopy definitions.FileCopy,
+) (*definitions.DeduplicatedFile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	source, isCopy := s.sources[fileCopy]
+	if !isCopy {
+		return nil, nil
+	}
+
+	delete(s.sources, fileCopy)
+	return s.release(source), nil
+}
+
+// release removes a copy from the count of the source, returning the source if no copy of it is left.
+// The caller must hold the mutex.
+func (s *InMemoryDeduplicatedFileStorage) release(source definitions.DeduplicatedFile) *definitions.DeduplicatedFile {
+	s.copies[source]--
+	if s.copies[source] > 0 {
+		return nil
+	}
+
+	delete(s.copies, source)
+	return &source
+}
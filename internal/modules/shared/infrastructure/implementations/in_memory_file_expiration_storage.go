@@ -0,0 +1,104 @@
+package implementations
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+)
+
+// InMemoryFileExpirationStorage implements the FileExpirationStorage interface keeping the expirations in the process memory.
+// It is intended for tests and single-node setups, as the expirations are lost when the process stops,
+// so the files uploaded before a restart are kept forever.
+type InMemoryFileExpirationStorage struct {
+	mutex       sync.Mutex
+	expirations map[definitions.ExpiringFile]time.Time
+}
+
+var (
+	inMemoryFileExpirationStorage     definitions.FileExpirationStorage
+	inMemoryFileExpirationStorageOnce sync.Once
+)
+
+// NewInMemoryFileExpirationStorage creates a new, empty InMemoryFileExpirationStorage
+func NewInMemoryFileExpirationStorage() definitions.FileExpirationStorage {
+	return &InMemoryFileExpirationStorage{
+		expirations: make(map[definitions.ExpiringFile]time.Time),
+	}
+}
+
+// GetInMemoryFileExpirationStorage returns a singleton instance of InMemoryFileExpirationStorage
+func GetInMemoryFileExpirationStorage() definitions.FileExpirationStorage {
+	inMemoryFileExpirationStorageOnce.Do(func() {
+		inMemoryFileExpirationStorage = NewInMemoryFileExpirationStorage()
+	})
+
+	return inMemoryFileExpirationStorage
+}
+
+// Schedule records the file to be deleted at the given time, replacing its previous expiration if any
+func (s *InMemoryFileExpirationStorage) Schedule(ctx context.Context, file definitions.ExpiringFile, expiresAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.expirations[file] = expiresAt
+	return nil
+}
+
+// Extend records the file to be deleted at the given time, unless it is already scheduled to be deleted later
+func (s *InMemoryFileExpirationStorage) Extend(ctx context.Context, file definitions.ExpiringFile, expiresAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if current, exists := s.expirations[file]; !exists || expiresAt.After(current) {
+		s.expirations[file] = expiresAt
+	}
+	return nil
+}
+
+// Unschedule removes the file from the record
+func (s *InMemoryFileExpirationStorage) Unschedule(ctx context.Context, file definitions.ExpiringFile) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.expirations, file)
+	return nil
+}
+
+// ListExpired returns up to limit files whose expiration time is not after now, the oldest first
+func (s *InMemoryFileExpirationStorage) ListExpired(ctx context.Context, now time.Time, limit int) ([]definitions.ExpiringFile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	files := make([]definitions.ExpiringFile, 0)
+	for file, expiresAt := range s.expirations {
+		if !expiresAt.After(now) {
+			files = append(files, file)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return s.expirations[files[i]].Before(s.expirations[files[j]])
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+
+	return files, nil
+}
+
+// Claim removes the file from the record only if it is still expired, returning whether it did
+func (s *InMemoryFileExpirationStorage) Claim(ctx context.Context, file definitions.ExpiringFile, now time.Time) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	expiresAt, exists := s.expirations[file]
+	if !exists || expiresAt.After(now) {
+		return false, nil
+	}
+
+	delete(s.expirations, file)
+	return true, nil
+}
@@ -0,0 +1,101 @@
+package implementations
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// inMemoryJobEntry is a job state kept in memory
+type inMemoryJobEntry struct {
+	job       definitions.Job
+	expiresAt time.Time // Zero if the job is kept forever
+}
+
+// InMemoryJobStorage implements the JobStorage interface keeping jobs in the process memory.
+// Each job is kept for the retention time since it was last set, like in Redis.
+// It is intended for tests and single-node setups, as jobs are lost when the process stops.
+type InMemoryJobStorage struct {
+	retention time.Duration
+
+	mutex   sync.Mutex
+	jobs    map[string]*list.Element // Elements of the recency list, indexed by job ID
+	recency *list.List               // Entries from the least to the most recently set
+}
+
+var (
+	inMemoryJobStorage     definitions.JobStorage
+	inMemoryJobStorageOnce sync.Once
+)
+
+// NewInMemoryJobStorage creates a new, empty InMemoryJobStorage keeping each job for the given retention,
+// or forever if it is not positive
+func NewInMemoryJobStorage(retention time.Duration) definitions.JobStorage {
+	return &InMemoryJobStorage{
+		retention: retention,
+		jobs:      make(map[string]*list.Element),
+		recency:   list.New(),
+	}
+}
+
+// GetInMemoryJobStorage returns a singleton instance of InMemoryJobStorage configured from the environment
+func GetInMemoryJobStorage() definitions.JobStorage {
+	inMemoryJobStorageOnce.Do(func() {
+		env := infrastructure.GetEnvironment()
+		inMemoryJobStorage = NewInMemoryJobStorage(time.Duration(env.JobRetentionSeconds) * time.Second)
+	})
+
+	return inMemoryJobStorage
+}
+
+// Set stores the job state, keeping it for the configured retention time and removing the expired jobs
+func (s *InMemoryJobStorage) Set(ctx context.Context, job definitions.Job) error {
+	entry := &inMemoryJobEntry{job: job}
+	now := time.Now()
+	if s.retention > 0 {
+		entry.expiresAt = now.Add(s.retention)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, exists := s.jobs[job.ID]; exists {
+		s.recency.Remove(element)
+	}
+	s.jobs[job.ID] = s.recency.PushBack(entry)
+
+	// The jobs expire in the order they were set, so the expired ones are at the front
+	for element := s.recency.Front(); element != nil; element = s.recency.Front() {
+		expired := element.Value.(*inMemoryJobEntry)
+		if expired.expiresAt.IsZero() || now.Before(expired.expiresAt) {
+			break
+		}
+		s.recency.Remove(element)
+		delete(s.jobs, expired.job.ID)
+	}
+
+	return nil
+}
+
+// Get retrieves a job by its ID, returning nil if it does not exist or expired
+func (s *InMemoryJobStorage) Get(ctx context.Context, id string) (*definitions.Job, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, exists := s.jobs[id]
+	if !exists {
+		return nil, nil
+	}
+
+	entry := element.Value.(*inMemoryJobEntry)
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		return nil, nil
+	}
+
+	job := entry.job
+	return &job, nil
+}
@@ -0,0 +1,20 @@
+package implementations
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// GetJobStorage returns the JobStorage implementation selected by the JOB_STORAGE_DRIVER environment variable
+func GetJobStorage() definitions.JobStorage {
+	jobStorageDriver := infrastructure.GetEnvironment().JobStorageDriver
+
+	switch jobStorageDriver {
+	case infrastructure.JOB_STORAGE_DRIVER_REDIS:
+		return GetRedisJobStorage()
+	case infrastructure.JOB_STORAGE_DRIVER_MEMORY:
+		return GetInMemoryJobStorage()
+	default:
+		panic("Unknown job storage driver: " + jobStorageDriver)
+	}
+}
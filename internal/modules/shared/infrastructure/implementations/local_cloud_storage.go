@@ -0,0 +1,293 @@
+package implementations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// LOCAL_FILES_ROUTE_PREFIX is the route the local files are served from
+	LOCAL_FILES_ROUTE_PREFIX = "/files"
+
+	// Query parameters of the signed local file URLs
+	LOCAL_FILE_EXPIRES_QUERY_PARAM   = "expires"
+	LOCAL_FILE_SIGNATURE_QUERY_PARAM = "signature"
+)
+
+// LocalCloudStorage implements the CloudStorage interface on the local filesystem.
+// Files are written under the root path, using the file folder as directory and the file path as name,
+// and are served by the server itself under the files route, optionally behind signed URLs.
+type LocalCloudStorage struct {
+	rootPath            string
+	signingSecret       string        // Secret signing the URLs, the URLs are not signed if empty
+	signedURLExpiration time.Duration // Time a signed URL is valid
+}
+
+var (
+	localCloudStorage     *LocalCloudStorage
+	localCloudStorageOnce sync.Once
+)
+
+// GetLocalCloudStorage returns a singleton instance of LocalCloudStorage configured in the environment
+func GetLocalCloudStorage() *LocalCloudStorage {
+	localCloudStorageOnce.Do(func() {
+		env := infrastructure.GetEnvironment()
+
+		localCloudStorage = NewLocalCloudStorage(
+			env.LocalStorageRootPath,
+			env.LocalStorageSigningSecret,
+			time.Duration(env.LocalStorageSignedURLExpirationSeconds)*time.Second,
+		)
+	})
+
+	return localCloudStorage
+}
+
+// NewLocalCloudStorage creates a LocalCloudStorage writing under the given root path
+func NewLocalCloudStorage(rootPath string, signingSecret string, signedURLExpiration time.Duration) *LocalCloudStorage {
+	return &LocalCloudStorage{
+		rootPath:            rootPath,
+		signingSecret:       signingSecret,
+		signedURLExpiration: signedURLExpiration,
+	}
+}
+
+// resolvePath returns the path of the file in the filesystem.
+// Both the folder and the file path must stay inside their parent, so the root cannot be escaped,
+// otherwise a FILE_PATH_NOT_VALID domain error is returned.
+func (s *LocalCloudStorage) resolvePath(fileFolder string, filePath string) (string, error) {
+	if !filepath.IsLocal(fileFolder) || !filepath.IsLocal(filePath) {
+		code := sharedErrors.FILE_PATH_NOT_VALID_ERROR_CODE
+		return "", sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+			Code:    &code,
+			Message: "The file directory and name must be relative and cannot escape the storage root",
+			Metadata: map[string]any{
+				"directory": fileFolder,
+				"fileName":  filePath,
+			},
+		})
+	}
+
+	return filepath.Join(s.rootPath, fileFolder, filePath), nil
+}
+
+// UploadFile writes the file under the root path
+func (s *LocalCloudStorage) UploadFile(ctx context.Context, request definitions.UploadFileRequest) (err error) {
+	_, span := tracer.Start(ctx, "LocalCloudStorage.UploadFile", trace.WithAttributes(
+		attribute.String("storage.folder", request.FileFolder),
+		attribute.String("storage.path", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	path, err := s.resolvePath(request.FileFolder, request.FilePath)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating the file directory: %w", err)
+	}
+
+	// Write to a temporary file first so the file is never served half written
+	temporaryFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(temporaryFile.Name())
+		}
+	}()
+
+	if _, err = io.Copy(temporaryFile, request.FileReader); err != nil {
+		_ = temporaryFile.Close()
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	if err = temporaryFile.Close(); err != nil {
+		return fmt.Errorf("error closing file: %w", err)
+	}
+	if err = os.Rename(temporaryFile.Name(), path); err != nil {
+		return fmt.Errorf("error moving file to its path: %w", err)
+	}
+
+	return nil
+}
+
+// FileExists checks if the file exists under the root path
+func (s *LocalCloudStorage) FileExists(ctx context.Context, request definitions.FileExistsRequest) (bool, error) {
+	path, err := s.resolvePath(request.FileFolder, request.FilePath)
+	if err != nil {
+		return false, err
+	}
+
+	return isRegularFile(path)
+}
+
+// GetDownloadURL returns the URL the file is served from.
+// In public mode the URL is signed with the default expiration only if there is a signing secret,
+// while in presigned mode the secret is required and the URL is signed with the requested expiration.
+func (s *LocalCloudStorage) GetDownloadURL(ctx context.Context, request definitions.GetDownloadURLRequest) (*definitions.DownloadURL, error) {
+	if _, err := s.resolvePath(request.FileFolder, request.FilePath); err != nil {
+		return nil, err
+	}
+
+	if request.Mode != definitions.DOWNLOAD_URL_MODE_PRESIGNED {
+		return s.fileURL(request.PublicURLPrefix, request.FileFolder, request.FilePath, s.signedURLExpiration), nil
+	}
+
+	if !s.RequiresSignature() {
+		return nil, fmt.Errorf("a signing secret is required to sign local file URLs")
+	}
+
+	return s.fileURL(request.PublicURLPrefix, request.FileFolder, request.FilePath, request.Expiration), nil
+}
+
+// CopyFile writes a copy of the file, ignoring the object options as on uploads
+func (s *LocalCloudStorage) CopyFile(ctx context.Context, request definitions.CopyFileRequest) error {
+	sourcePath, err := s.resolvePath(request.SourceFileFolder, request.SourceFilePath)
+	if err != nil {
+		return err
+	}
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("error opening source file: %w", err)
+	}
+	defer source.Close()
+
+	return s.UploadFile(ctx, definitions.UploadFileRequest{
+		FileReader:  source,
+		FileFolder:  request.FileFolder,
+		FilePath:    request.FilePath,
+		FileOptions: request.FileOptions,
+	})
+}
+
+// DeleteFile removes the file from the root path, succeeding if it does not exist
+func (s *LocalCloudStorage) DeleteFile(ctx context.Context, request definitions.DeleteFileRequest) error {
+	path, err := s.resolvePath(request.FileFolder, request.FilePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting file: %w", err)
+	}
+
+	return nil
+}
+
+// isRegularFile checks if the path exists and is a regular file (E.g, not a directory)
+func isRegularFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return info.Mode().IsRegular(), nil
+}
+
+// fileURL returns the URL the file is served from, signed for the given expiration if there is a signing secret
+func (s *LocalCloudStorage) fileURL(publicURLPrefix string, fileFolder string, filePath string, expiration time.Duration) *definitions.DownloadURL {
+	fileURL := fmt.Sprintf(
+		"%s%s/%s/%s",
+		publicURLPrefix,
+		LOCAL_FILES_ROUTE_PREFIX,
+		escapeURLPath(fileFolder),
+		escapeURLPath(filePath),
+	)
+
+	if !s.RequiresSignature() {
+		return &definitions.DownloadURL{URL: fileURL}
+	}
+
+	// The signature has a precision of seconds, so the expiration is truncated to not report a later one
+	expiresAt := time.Unix(time.Now().Add(expiration).Unix(), 0)
+	query := url.Values{}
+	query.Set(LOCAL_FILE_EXPIRES_QUERY_PARAM, strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set(LOCAL_FILE_SIGNATURE_QUERY_PARAM, s.sign(fileFolder, filePath, expiresAt.Unix()))
+
+	return &definitions.DownloadURL{
+		URL:       fileURL + "?" + query.Encode(),
+		ExpiresAt: &expiresAt,
+	}
+}
+
+// escapeURLPath escapes every segment of the slash-separated path
+func escapeURLPath(path string) string {
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		segments[idx] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// sign returns the hex HMAC-SHA256 signature of the file and expiration time
+func (s *LocalCloudStorage) sign(fileFolder string, filePath string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, []byte(s.signingSecret))
+	mac.Write([]byte(fileFolder + "/" + filePath + ":" + strconv.FormatInt(expiresAt, 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequiresSignature returns whether the served URLs must be signed
+func (s *LocalCloudStorage) RequiresSignature() bool {
+	return s.signingSecret != ""
+}
+
+// VerifySignature checks the signature matches the file and the expiration time did not pass
+func (s *LocalCloudStorage) VerifySignature(fileFolder string, filePath string, expiresAt int64, signature string) bool {
+	if time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	expectedSignature := s.sign(fileFolder, filePath, expiresAt)
+	return hmac.Equal([]byte(expectedSignature), []byte(signature))
+}
+
+// ResolveFilePath returns the path of the stored file in the filesystem, or nil if it does not exist
+func (s *LocalCloudStorage) ResolveFilePath(fileFolder string, filePath string) (*string, error) {
+	// Paths escaping the root are never stored, so they are reported as missing
+	path, err := s.resolvePath(fileFolder, filePath)
+	if err != nil {
+		return nil, nil
+	}
+
+	exists, err := isRegularFile(path)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+// RootPath returns the directory holding the stored files
+func (s *LocalCloudStorage) RootPath() string {
+	return s.rootPath
+}
@@ -0,0 +1,173 @@
+package implementations
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PROMETHEUS_NAMESPACE prefixes the names of all the metrics
+const PROMETHEUS_NAMESPACE = "serpentarius"
+
+// PrometheusMetricsRecorder implements the MetricsRecorder interface using Prometheus collectors.
+// The collectors are registered in a dedicated registry exposed by Handler.
+type PrometheusMetricsRecorder struct {
+	registry *prometheus.Registry
+
+	itemRenderDuration  *prometheus.HistogramVec
+	mergeDuration       prometheus.Histogram
+	uploadDuration      prometheus.Histogram
+	uploadBytes         prometheus.Histogram
+	cacheLookups        *prometheus.CounterVec
+	poolWaitDuration    prometheus.Histogram
+	browserLaunches     prometheus.Counter
+	browserClosures     prometheus.Counter
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+}
+
+var (
+	prometheusMetricsRecorder     *PrometheusMetricsRecorder
+	prometheusMetricsRecorderOnce sync.Once
+)
+
+// GetPrometheusMetricsRecorder returns a singleton instance of PrometheusMetricsRecorder
+func GetPrometheusMetricsRecorder() *PrometheusMetricsRecorder {
+	prometheusMetricsRecorderOnce.Do(func() {
+		prometheusMetricsRecorder = newPrometheusMetricsRecorder()
+	})
+
+	return prometheusMetricsRecorder
+}
+
+// newPrometheusMetricsRecorder creates the collectors and registers them along with the Go runtime ones
+func newPrometheusMetricsRecorder() *PrometheusMetricsRecorder {
+	r := &PrometheusMetricsRecorder{
+		registry: prometheus.NewRegistry(),
+		itemRenderDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "item_render_duration_seconds",
+			Help:      "Time spent rendering a single PDF item.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"outcome"}),
+		mergeDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "merge_duration_seconds",
+			Help:      "Time spent merging the rendered items into a single PDF.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		uploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "upload_duration_seconds",
+			Help:      "Time spent uploading a file to cloud storage.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		uploadBytes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "upload_bytes",
+			Help:      "Size of the files uploaded to cloud storage.",
+			Buckets:   prometheus.ExponentialBuckets(16*1024, 4, 8), // 16KiB to 256MiB
+		}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "cache_lookups_total",
+			Help:      "Lookups of generated PDFs in the cache by result (hit, miss or stale).",
+		}, []string{"result"}),
+		poolWaitDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "browser_pool_wait_duration_seconds",
+			Help:      "Time spent waiting for a browser page.",
+			Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30},
+		}),
+		browserLaunches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "browser_launches_total",
+			Help:      "Browsers launched by the pool.",
+		}),
+		browserClosures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "browser_closures_total",
+			Help:      "Browsers closed by the pool.",
+		}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: PROMETHEUS_NAMESPACE,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time spent handling HTTP requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	r.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.itemRenderDuration,
+		r.mergeDuration,
+		r.uploadDuration,
+		r.uploadBytes,
+		r.cacheLookups,
+		r.poolWaitDuration,
+		r.browserLaunches,
+		r.browserClosures,
+		r.httpRequests,
+		r.httpRequestDuration,
+	)
+
+	return r
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus text format
+func (r *PrometheusMetricsRecorder) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveItemRender records the time spent rendering a single PDF item
+func (r *PrometheusMetricsRecorder) ObserveItemRender(duration time.Duration, outcome string) {
+	r.itemRenderDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// ObserveMerge records the time spent merging the rendered items into a single PDF
+func (r *PrometheusMetricsRecorder) ObserveMerge(duration time.Duration) {
+	r.mergeDuration.Observe(duration.Seconds())
+}
+
+// ObserveUpload records the time spent uploading a file to cloud storage and its size
+func (r *PrometheusMetricsRecorder) ObserveUpload(duration time.Duration, bytes int64) {
+	r.uploadDuration.Observe(duration.Seconds())
+	r.uploadBytes.Observe(float64(bytes))
+}
+
+// IncCacheLookup counts a lookup of a generated PDF in the cache by its result
+func (r *PrometheusMetricsRecorder) IncCacheLookup(result string) {
+	r.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// ObservePoolWait records the time spent waiting for a browser page
+func (r *PrometheusMetricsRecorder) ObservePoolWait(duration time.Duration) {
+	r.poolWaitDuration.Observe(duration.Seconds())
+}
+
+// IncBrowserLaunch counts a launched browser
+func (r *PrometheusMetricsRecorder) IncBrowserLaunch() {
+	r.browserLaunches.Inc()
+}
+
+// IncBrowserClose counts a closed browser
+func (r *PrometheusMetricsRecorder) IncBrowserClose() {
+	r.browserClosures.Inc()
+}
+
+// ObserveHTTPRequest records an HTTP request by its method, route template and status code
+func (r *PrometheusMetricsRecorder) ObserveHTTPRequest(method string, route string, statusCode int, duration time.Duration) {
+	r.httpRequests.WithLabelValues(method, route, strconv.Itoa(statusCode)).Inc()
+	r.httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
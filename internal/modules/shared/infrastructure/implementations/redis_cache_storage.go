@@ -2,19 +2,38 @@ package implementations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
-	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
-	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// REDIS_URL_CACHE_FILE_KEY_PREFIX prefixes the keys holding the file each cached URL points to
+	REDIS_URL_CACHE_FILE_KEY_PREFIX = "url-cache-file:"
+	// REDIS_URL_CACHE_INDEX_KEY_PREFIX prefixes the sorted sets indexing the cached URLs of each file, scored by their expiration Unix time
+	REDIS_URL_CACHE_INDEX_KEY_PREFIX = "url-cache-index:"
+)
+
+// indexCachedURLScript adds the key to the index of the file, pruning the expired keys,
+// and makes the index expire with its longest-lived key
+var indexCachedURLScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[3])
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+if last[2] == "inf" then
+	return redis.call("PERSIST", KEYS[1])
+end
+return redis.call("EXPIREAT", KEYS[1], last[2])
+`)
+
 // RedisCacheStorage implements the UrlCacheStorage interface for Redis
 type RedisCacheStorage struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 var (
@@ -26,65 +45,67 @@ var (
 func GetRedisCacheStorage() definitions.UrlCacheStorage {
 	redisOnce.Do(func() {
 		redisCacheStorage = &RedisCacheStorage{
-			client: createRedisClient(),
+			client: GetRedisClient(),
 		}
 	})
 
 	return redisCacheStorage
 }
 
-// createRedisClient creates a shared Redis client instance
-func createRedisClient() *redis.Client {
-	env := infrastructure.GetEnvironment()
-
-	// Create the Redis client
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", env.RedisHost, env.RedisPort),
-		Password: env.RedisPassword,
-		DB:       env.RedisDB,
-	})
-
-	// Test the connection
-	ctx := context.Background()
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		sharedUtilities.GetLogger().
-			WithError(err).
-			Error("Failed to connect to Redis")
-		panic("Unable to connect to Redis: " + err.Error())
-	}
-
-	sharedUtilities.GetLogger().
-		WithField("host", env.RedisHost).
-		WithField("port", env.RedisPort).
-		Info("Redis cache client initialized")
-
-	return client
+// getFileKey returns the key holding the file the cached URL points to
+func getFileKey(key string) string {
+	return REDIS_URL_CACHE_FILE_KEY_PREFIX + key
 }
 
-// Set stores a key-value pair in the Redis cache with an optional expiration time
-func (r *RedisCacheStorage) Set(request definitions.SetURLCacheRequest) error {
-	ctx := context.Background()
+// getFileIndexKey returns the key of the sorted set indexing the cached URLs of the file
+func getFileIndexKey(file definitions.CachedFile) string {
+	return REDIS_URL_CACHE_INDEX_KEY_PREFIX + file.FileFolder + "/" + file.FilePath
+}
 
+// Set stores a key-value pair in the Redis cache with an optional expiration time,
+// and indexes the key by the file the URL points to
+func (r *RedisCacheStorage) Set(ctx context.Context, request definitions.SetURLCacheRequest) error {
 	// Set expiration time if provided
 	var expiration time.Duration
+	indexScore := "+inf"
 	if request.Expiration > 0 {
 		expiration = time.Duration(request.Expiration) * time.Second
+		indexScore = strconv.FormatInt(time.Now().Add(expiration).Unix(), 10)
 	}
 
-	// Store the key-value pair
-	err := r.client.Set(ctx, request.Key, request.Value, expiration).Err()
+	file, err := json.Marshal(request.File)
+	if err != nil {
+		return fmt.Errorf("error serializing cached file: %w", err)
+	}
+
+	// Store the key-value pair and the file it points to
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, request.Key, request.Value, expiration)
+		pipe.Set(ctx, getFileKey(request.Key), file, expiration)
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("error setting cache key: %w", err)
 	}
 
+	// Index the key by its file
+	err = indexCachedURLScript.Run(
+		ctx,
+		r.client,
+		[]string{getFileIndexKey(request.File)},
+		request.Key,
+		indexScore,
+		time.Now().Unix(),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("error indexing cache key: %w", err)
+	}
+
 	return nil
 }
 
 // Get retrieves a value from the Redis cache by key
-func (r *RedisCacheStorage) Get(key string) (*string, error) {
-	ctx := context.Background()
-
+func (r *RedisCacheStorage) Get(ctx context.Context, key string) (*string, error) {
 	// Get the value from Redis
 	value, err := r.client.Get(ctx, key).Result()
 
@@ -101,15 +122,92 @@ func (r *RedisCacheStorage) Get(key string) (*string, error) {
 	return &value, nil
 }
 
-// Delete removes a key from the Redis cache
-func (r *RedisCacheStorage) Delete(key string) error {
-	ctx := context.Background()
+// GetExpiration returns the remaining lifetime of the cached URL, 0 if it never expires or negative if it is not cached
+func (r *RedisCacheStorage) GetExpiration(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("error getting cache key expiration: %w", err)
+	}
 
-	// Delete the key from Redis
-	err := r.client.Del(ctx, key).Err()
+	// Redis answers -1 for keys without expiration and -2 for missing keys
+	if ttl == -1 {
+		return 0, nil
+	}
+	if ttl < 0 {
+		return -1, nil
+	}
+
+	return ttl, nil
+}
+
+// Delete removes a key from the Redis cache and from the index of its file
+func (r *RedisCacheStorage) Delete(ctx context.Context, key string) error {
+	file, err := r.GetFile(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	// Delete the key and its file from Redis
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.Del(ctx, getFileKey(key))
+		if file != nil {
+			pipe.ZRem(ctx, getFileIndexKey(*file), key)
+		}
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("error deleting cache key: %w", err)
 	}
 
 	return nil
 }
+
+// GetFile retrieves the file the cached URL points to
+func (r *RedisCacheStorage) GetFile(ctx context.Context, key string) (*definitions.CachedFile, error) {
+	value, err := r.client.Get(ctx, getFileKey(key)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting cached file: %w", err)
+	}
+
+	var file definitions.CachedFile
+	if err := json.Unmarshal(value, &file); err != nil {
+		return nil, fmt.Errorf("error deserializing cached file: %w", err)
+	}
+
+	return &file, nil
+}
+
+// DeleteByFile removes every cached URL indexed by the file, and the index itself
+func (r *RedisCacheStorage) DeleteByFile(ctx context.Context, file definitions.CachedFile) (int, error) {
+	indexKey := getFileIndexKey(file)
+	keys, err := r.client.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("error getting cache keys of file: %w", err)
+	}
+
+	// Delete the keys one by one, so they can live in different cluster slots
+	deletions := make([]*redis.IntCmd, 0, len(keys))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			deletions = append(deletions, pipe.Del(ctx, key))
+			pipe.Del(ctx, getFileKey(key))
+		}
+		pipe.Del(ctx, indexKey)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error deleting cache keys of file: %w", err)
+	}
+
+	// Count only the keys that had not expired yet
+	deleted := 0
+	for _, deletion := range deletions {
+		deleted += int(deletion.Val())
+	}
+
+	return deleted, nil
+}
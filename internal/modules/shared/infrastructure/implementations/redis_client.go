@@ -0,0 +1,138 @@
+package implementations
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	redisClient     redis.UniversalClient
+	redisClientOnce sync.Once
+)
+
+// GetRedisClient returns the Redis client shared by all Redis-backed implementations
+func GetRedisClient() redis.UniversalClient {
+	redisClientOnce.Do(func() {
+		redisClient = createRedisClient()
+	})
+
+	return redisClient
+}
+
+// createRedisClient creates a shared Redis client instance, connected to a single node,
+// through the sentinels or to a cluster depending on the environment.
+// A failing connection is only logged, as the client reconnects on demand and its users handle the errors.
+func createRedisClient() redis.UniversalClient {
+	env := infrastructure.GetEnvironment()
+
+	options, err := NewRedisOptions(env)
+	if err != nil {
+		panic("Unable to configure Redis: " + err.Error())
+	}
+
+	// Create the Redis client
+	client := redis.NewUniversalClient(options)
+
+	// Test the connection
+	ctx := context.Background()
+	_, err = client.Ping(ctx).Result()
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			Error("Failed to connect to Redis, the connection will be retried on demand")
+	}
+
+	sharedUtilities.GetLogger().
+		WithField("addresses", strings.Join(options.Addrs, ",")).
+		WithField("masterName", options.MasterName).
+		WithField("clusterMode", options.IsClusterMode).
+		WithField("tls", options.TLSConfig != nil).
+		Info("Redis cache client initialized")
+
+	return client
+}
+
+// NewRedisOptions returns the options of the universal Redis client configured in the environment.
+// The client connects through the sentinels if there is a master name, to a cluster if the cluster mode is enabled
+// or there are several addresses, and to a single node otherwise.
+func NewRedisOptions(env *infrastructure.EnvironmentSpec) (*redis.UniversalOptions, error) {
+	addresses := env.RedisAddresses
+	if len(addresses) == 0 {
+		addresses = []string{fmt.Sprintf("%s:%s", env.RedisHost, env.RedisPort)}
+	}
+
+	options := &redis.UniversalOptions{
+		Addrs:            addresses,
+		MasterName:       env.RedisMasterName,
+		IsClusterMode:    env.RedisClusterMode,
+		Username:         env.RedisUsername,
+		Password:         env.RedisPassword,
+		DB:               env.RedisDB,
+		SentinelUsername: env.RedisSentinelUsername,
+		SentinelPassword: env.RedisSentinelPassword,
+		PoolSize:         env.RedisPoolSize,
+		MinIdleConns:     env.RedisMinIdleConns,
+		MaxIdleConns:     env.RedisMaxIdleConns,
+		PoolTimeout:      time.Duration(env.RedisPoolTimeoutSeconds) * time.Second,
+		ConnMaxIdleTime:  time.Duration(env.RedisConnMaxIdleTimeSeconds) * time.Second,
+		ConnMaxLifetime:  time.Duration(env.RedisConnMaxLifetimeSeconds) * time.Second,
+	}
+
+	// Redis Cluster has a single database
+	if env.RedisMasterName == "" && (env.RedisClusterMode || len(addresses) > 1) {
+		options.IsClusterMode = true
+		options.DB = 0
+	}
+
+	if env.RedisTLSEnabled {
+		tlsConfig, err := newRedisTLSConfig(env)
+		if err != nil {
+			return nil, err
+		}
+		options.TLSConfig = tlsConfig
+	}
+
+	return options, nil
+}
+
+// newRedisTLSConfig returns the TLS configuration verifying the server with the configured CAs, if any,
+// and presenting the client certificate, if any
+func newRedisTLSConfig(env *infrastructure.EnvironmentSpec) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: env.RedisTLSServerName,
+	}
+
+	if env.RedisTLSCaFile != "" {
+		caCertificates, err := os.ReadFile(env.RedisTLSCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading Redis TLS CA file: %w", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCertificates) {
+			return nil, errors.New("no certificates found in Redis TLS CA file")
+		}
+	}
+
+	if env.RedisTLSCertFile != "" || env.RedisTLSKeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(env.RedisTLSCertFile, env.RedisTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading Redis TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,137 @@
+package implementations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// REDIS_DEDUPLICATED_FILE_SOURCES_KEY is the hash holding the deduplicated file each copy was made from.
+	// It shares its hash tag with the copy counts, so both are updated by the same script in Redis Cluster.
+	REDIS_DEDUPLICATED_FILE_SOURCES_KEY = "{deduplicated-files}:sources"
+	// REDIS_DEDUPLICATED_FILE_COPIES_KEY is the hash holding the number of copies of each deduplicated file
+	REDIS_DEDUPLICATED_FILE_COPIES_KEY = "{deduplicated-files}:copies"
+)
+
+// addDeduplicatedFileCopyScript points the copy to the source and counts it, uncounting it from its previous source,
+// which is returned if no copy of it is left
+var addDeduplicatedFileCopyScript = redis.NewScript(`
+local previous = redis.call("HGET", KEYS[1], ARGV[1])
+if previous == ARGV[2] then
+	return false
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("HINCRBY", KEYS[2], ARGV[2], 1)
+if previous and redis.call("HINCRBY", KEYS[2], previous, -1) <= 0 then
+	redis.call("HDEL", KEYS[2], previous)
+	return previous
+end
+return false
+`)
+
+// removeDeduplicatedFileCopyScript removes the copy and uncounts it from its source, which is returned if no copy of it is left
+var removeDeduplicatedFileCopyScript = redis.NewScript(`
+local source = redis.call("HGET", KEYS[1], ARGV[1])
+if not source then
+	return false
+end
+redis.call("HDEL", KEYS[1], ARGV[1])
+if redis.call("HINCRBY", KEYS[2], source, -1) <= 0 then
+	redis.call("HDEL", KEYS[2], source)
+	return source
+end
+return false
+`)
+
+// RedisDeduplicatedFileStorage implements the DeduplicatedFileStorage interface with two Redis hashes
+type RedisDeduplicatedFileStorage struct {
+	client redis.UniversalClient
+}
+
+var (
+	redisDeduplicatedFileStorage     *RedisDeduplicatedFileStorage
+	redisDeduplicatedFileStorageOnce sync.Once
+)
+
+// GetRedisDeduplicatedFileStorage returns a singleton instance of RedisDeduplicatedFileStorage
+func GetRedisDeduplicatedFileStorage() definitions.DeduplicatedFileStorage {
+	redisDeduplicatedFileStorageOnce.Do(func() {
+		redisDeduplicatedFileStorage = &RedisDeduplicatedFileStorage{
+			client: GetRedisClient(),
+		}
+	})
+
+	return redisDeduplicatedFileStorage
+}
+
+// AddCopy records the file as a copy of the deduplicated file, returning the previous source if no copy of it is left
+func (r *RedisDeduplicatedFileStorage) AddCopy(
+	ctx context.Context,
+	source definitions.DeduplicatedFile,
+	fileCopy definitions.FileCopy,
+) (*definitions.DeduplicatedFile, error) {
+	copyMember, err := json.Marshal(fileCopy)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing file copy: %w", err)
+	}
+	sourceMember, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing deduplicated file: %w", err)
+	}
+
+	released, err := addDeduplicatedFileCopyScript.Run(
+		ctx,
+		r.client,
+		[]string{REDIS_DEDUPLICATED_FILE_SOURCES_KEY, REDIS_DEDUPLICATED_FILE_COPIES_KEY},
+		copyMember,
+		sourceMember,
+	).Text()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("error recording file copy: %w", err)
+	}
+
+	return deserializeReleasedDeduplicatedFile(released)
+}
+
+// RemoveCopy removes the record of the copy, returning its source if no copy of it is left
+func (r *RedisDeduplicatedFileStorage) RemoveCopy(
+	ctx context.Context,
+	fileCopy definitions.FileCopy,
+) (*definitions.DeduplicatedFile, error) {
+	copyMember, err := json.Marshal(fileCopy)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing file copy: %w", err)
+	}
+
+	released, err := removeDeduplicatedFileCopyScript.Run(
+		ctx,
+		r.client,
+		[]string{REDIS_DEDUPLICATED_FILE_SOURCES_KEY, REDIS_DEDUPLICATED_FILE_COPIES_KEY},
+		copyMember,
+	).Text()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("error removing file copy: %w", err)
+	}
+
+	return deserializeReleasedDeduplicatedFile(released)
+}
+
+// deserializeReleasedDeduplicatedFile returns the deduplicated file returned by a script, nil if none was
+func deserializeReleasedDeduplicatedFile(member string) (*definitions.DeduplicatedFile, error) {
+	if member == "" {
+		return nil, nil
+	}
+
+	var file definitions.DeduplicatedFile
+	if err := json.Unmarshal([]byte(member), &file); err != nil {
+		return nil, fmt.Errorf("error deserializing deduplicated file: %w", err)
+	}
+
+	return &file, nil
+}
@@ -0,0 +1,145 @@
+package implementations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/redis/go-redis/v9"
+)
+
+// REDIS_FILE_EXPIRATIONS_KEY is the sorted set holding the expiring files, scored by their expiration Unix time
+const REDIS_FILE_EXPIRATIONS_KEY = "file-expirations"
+
+// claimExpiredFileScript removes the member only if its score is not after the given time
+var claimExpiredFileScript = redis.NewScript(`
+local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if score and tonumber(score) <= tonumber(ARGV[2]) then
+	return redis.call("ZREM", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// RedisFileExpirationStorage implements the FileExpirationStorage interface with a Redis sorted set
+type RedisFileExpirationStorage struct {
+	client redis.UniversalClient
+}
+
+var (
+	redisFileExpirationStorage     *RedisFileExpirationStorage
+	redisFileExpirationStorageOnce sync.Once
+)
+
+// GetRedisFileExpirationStorage returns a singleton instance of RedisFileExpirationStorage
+func GetRedisFileExpirationStorage() definitions.FileExpirationStorage {
+	redisFileExpirationStorageOnce.Do(func() {
+		redisFileExpirationStorage = &RedisFileExpirationStorage{
+			client: GetRedisClient(),
+		}
+	})
+
+	return redisFileExpirationStorage
+}
+
+// serializeExpiringFile returns the sorted set member identifying the file
+func serializeExpiringFile(file definitions.ExpiringFile) (string, error) {
+	member, err := json.Marshal(file)
+	if err != nil {
+		return "", fmt.Errorf("error serializing expiring file: %w", err)
+	}
+
+	return string(member), nil
+}
+
+// Schedule records the file to be deleted at the given time, replacing its previous expiration if any
+func (r *RedisFileExpirationStorage) Schedule(ctx context.Context, file definitions.ExpiringFile, expiresAt time.Time) error {
+	member, err := serializeExpiringFile(file)
+	if err != nil {
+		return err
+	}
+
+	err = r.client.ZAdd(ctx, REDIS_FILE_EXPIRATIONS_KEY, redis.Z{
+		Score:  float64(expiresAt.Unix()),
+		Member: member,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("error scheduling file expiration: %w", err)
+	}
+
+	return nil
+}
+
+// Extend records the file to be deleted at the given time, unless it is already scheduled to be deleted later
+func (r *RedisFileExpirationStorage) Extend(ctx context.Context, file definitions.ExpiringFile, expiresAt time.Time) error {
+	member, err := serializeExpiringFile(file)
+	if err != nil {
+		return err
+	}
+
+	// GT only updates the score if it grows, while new members are added as usual
+	err = r.client.ZAddGT(ctx, REDIS_FILE_EXPIRATIONS_KEY, redis.Z{
+		Score:  float64(expiresAt.Unix()),
+		Member: member,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("error extending file expiration: %w", err)
+	}
+
+	return nil
+}
+
+// Unschedule removes the file from the record
+func (r *RedisFileExpirationStorage) Unschedule(ctx context.Context, file definitions.ExpiringFile) error {
+	member, err := serializeExpiringFile(file)
+	if err != nil {
+		return err
+	}
+
+	if err := r.client.ZRem(ctx, REDIS_FILE_EXPIRATIONS_KEY, member).Err(); err != nil {
+		return fmt.Errorf("error unscheduling file expiration: %w", err)
+	}
+
+	return nil
+}
+
+// ListExpired returns up to limit files whose expiration time is not after now, the oldest first
+func (r *RedisFileExpirationStorage) ListExpired(ctx context.Context, now time.Time, limit int) ([]definitions.ExpiringFile, error) {
+	members, err := r.client.ZRangeByScore(ctx, REDIS_FILE_EXPIRATIONS_KEY, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error listing expired files: %w", err)
+	}
+
+	files := make([]definitions.ExpiringFile, 0, len(members))
+	for _, member := range members {
+		var file definitions.ExpiringFile
+		if err := json.Unmarshal([]byte(member), &file); err != nil {
+			return nil, fmt.Errorf("error deserializing expiring file: %w", err)
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// Claim removes the file from the record only if it is still expired, returning whether it did
+func (r *RedisFileExpirationStorage) Claim(ctx context.Context, file definitions.ExpiringFile, now time.Time) (bool, error) {
+	member, err := serializeExpiringFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := claimExpiredFileScript.Run(ctx, r.client, []string{REDIS_FILE_EXPIRATIONS_KEY}, member, now.Unix()).Int()
+	if err != nil {
+		return false, fmt.Errorf("error claiming expired file: %w", err)
+	}
+
+	return removed == 1, nil
+}
@@ -0,0 +1,80 @@
+package implementations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	"github.com/redis/go-redis/v9"
+)
+
+// REDIS_JOB_KEY_PREFIX namespaces the job keys to avoid collisions with the URL cache
+const REDIS_JOB_KEY_PREFIX = "job:"
+
+// RedisJobStorage implements the JobStorage interface for Redis
+type RedisJobStorage struct {
+	client    redis.UniversalClient
+	retention time.Duration
+}
+
+var (
+	redisJobStorage     *RedisJobStorage
+	redisJobStorageOnce sync.Once
+)
+
+// GetRedisJobStorage returns a singleton instance of RedisJobStorage
+func GetRedisJobStorage() definitions.JobStorage {
+	redisJobStorageOnce.Do(func() {
+		redisJobStorage = &RedisJobStorage{
+			client:    GetRedisClient(),
+			retention: time.Duration(infrastructure.GetEnvironment().JobRetentionSeconds) * time.Second,
+		}
+	})
+
+	return redisJobStorage
+}
+
+// Set stores the job state, keeping it for the configured retention time
+func (r *RedisJobStorage) Set(ctx context.Context, job definitions.Job) error {
+	// Serialize the job
+	serializedJob, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("error serializing job: %w", err)
+	}
+
+	// Store the serialized job
+	err = r.client.Set(ctx, REDIS_JOB_KEY_PREFIX+job.ID, serializedJob, r.retention).Err()
+	if err != nil {
+		return fmt.Errorf("error setting job: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a job by its ID, returning nil if it does not exist
+func (r *RedisJobStorage) Get(ctx context.Context, id string) (*definitions.Job, error) {
+	// Get the serialized job from Redis
+	serializedJob, err := r.client.Get(ctx, REDIS_JOB_KEY_PREFIX+id).Bytes()
+
+	// Handle key not found (nil error)
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	// Handle other errors
+	if err != nil {
+		return nil, fmt.Errorf("error getting job: %w", err)
+	}
+
+	// Deserialize the job
+	var job definitions.Job
+	if err := json.Unmarshal(serializedJob, &job); err != nil {
+		return nil, fmt.Errorf("error deserializing job: %w", err)
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,170 @@
+package implementations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// REDIS_LOCK_KEY_PREFIX prefixes the keys holding the token of the owner of each lock
+	REDIS_LOCK_KEY_PREFIX = "lock:"
+	// REDIS_LOCK_FAILURE_KEY_PREFIX prefixes the keys holding the failure recorded by the last owner of each lock
+	REDIS_LOCK_FAILURE_KEY_PREFIX = "lock-failure:"
+)
+
+// renewLockScript extends the lease of the lock only if it is still held by the token
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript frees the lock only if it is still held by the token, recording the failure
+// for as long as the lease if there is one, and clearing the failure of a previous owner otherwise
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if ARGV[2] ~= "" then
+	redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[3])
+else
+	redis.call("DEL", KEYS[2])
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// abandonLockScript frees the lock only if it is still held by the token, leaving the failure untouched
+var abandonLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// RedisLockStorage implements the LockStorage interface with Redis keys holding a random token of their owner
+type RedisLockStorage struct {
+	client redis.UniversalClient
+}
+
+var (
+	redisLockStorage     *RedisLockStorage
+	redisLockStorageOnce sync.Once
+)
+
+// GetRedisLockStorage returns a singleton instance of RedisLockStorage
+func GetRedisLockStorage() definitions.LockStorage {
+	redisLockStorageOnce.Do(func() {
+		redisLockStorage = &RedisLockStorage{
+			client: GetRedisClient(),
+		}
+	})
+
+	return redisLockStorage
+}
+
+// getLockKeys returns the keys of the lock and its failure. The key is their hash tag,
+// so both are in the same slot of a Redis Cluster and can be used by the same script.
+func getLockKeys(key string) []string {
+	return []string{
+		REDIS_LOCK_KEY_PREFIX + "{" + key + "}",
+		REDIS_LOCK_FAILURE_KEY_PREFIX + "{" + key + "}",
+	}
+}
+
+// Acquire takes the lock of the key if it is free, returning nil if another owner holds it
+func (r *RedisLockStorage) Acquire(ctx context.Context, key string, lease time.Duration) (definitions.Lock, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return nil, fmt.Errorf("error generating lock token: %w", err)
+	}
+	token := hex.EncodeToString(randomBytes)
+
+	keys := getLockKeys(key)
+	err := r.client.SetArgs(ctx, keys[0], token, redis.SetArgs{Mode: "NX", TTL: lease}).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring lock: %w", err)
+	}
+
+	return &redisLock{client: r.client, keys: keys, token: token, lease: lease}, nil
+}
+
+// GetFailure returns the failure recorded by the last owner of the lock of the key, nil if it succeeded
+func (r *RedisLockStorage) GetFailure(ctx context.Context, key string) (*definitions.LockFailure, error) {
+	value, err := r.client.Get(ctx, getLockKeys(key)[1]).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting lock failure: %w", err)
+	}
+
+	var failure definitions.LockFailure
+	if err := json.Unmarshal([]byte(value), &failure); err != nil {
+		return nil, fmt.Errorf("error deserializing lock failure: %w", err)
+	}
+
+	return &failure, nil
+}
+
+// redisLock is a lock held in Redis, identified by the token stored in its key
+type redisLock struct {
+	client redis.UniversalClient
+	keys   []string
+	token  string
+	lease  time.Duration
+}
+
+// Renew extends the lease of the lock, failing if the lock was lost
+func (l *redisLock) Renew(ctx context.Context, lease time.Duration) error {
+	renewed, err := renewLockScript.Run(ctx, l.client, l.keys[:1], l.token, lease.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("error renewing lock: %w", err)
+	}
+	if renewed == 0 {
+		return errors.New("lock was lost")
+	}
+
+	l.lease = lease
+	return nil
+}
+
+// Release frees the lock if it is still held, recording the failure for as long as the lease
+func (l *redisLock) Release(ctx context.Context, failure *definitions.LockFailure) error {
+	serializedFailure := ""
+	if failure != nil {
+		value, err := json.Marshal(failure)
+		if err != nil {
+			return fmt.Errorf("error serializing lock failure: %w", err)
+		}
+		serializedFailure = string(value)
+	}
+
+	err := releaseLockScript.Run(ctx, l.client, l.keys, l.token, serializedFailure, l.lease.Milliseconds()).Err()
+	if err != nil {
+		return fmt.Errorf("error releasing lock: %w", err)
+	}
+
+	return nil
+}
+
+// Abandon frees the lock if it is still held, keeping the failure recorded by the previous owner
+func (l *redisLock) Abandon(ctx context.Context) error {
+	if err := abandonLockScript.Run(ctx, l.client, l.keys[:1], l.token).Err(); err != nil {
+		return fmt.Errorf("error abandoning lock: %w", err)
+	}
+
+	return nil
+}
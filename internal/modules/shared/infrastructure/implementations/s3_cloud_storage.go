@@ -4,52 +4,105 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
 	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
 )
 
-// S3CloudStorage implements the CloudStorage interface for AWS S3
+// S3CloudStorage implements the CloudStorage interface for AWS S3.
+// Files are uploaded with the upload manager, which streams the large ones in multipart uploads.
 type S3CloudStorage struct {
-	client *s3.Client
+	client        *s3.Client
+	uploader      *manager.Uploader
+	presignClient *s3.PresignClient
 }
 
+// S3_MAX_PRESIGN_EXPIRATION is the maximum lifetime of a SigV4 presigned URL
+const S3_MAX_PRESIGN_EXPIRATION = 7 * 24 * time.Hour
+
 var (
 	s3CloudStorage *S3CloudStorage
 	once           sync.Once
+
+	s3Client     *s3.Client
+	s3ClientOnce sync.Once
 )
 
 // GetS3CloudStorage returns a singleton instance of S3CloudStorage
 func GetS3CloudStorage() definitions.CloudStorage {
 	once.Do(func() {
-		s3CloudStorage = &S3CloudStorage{
-			client: createS3Client(),
-		}
+		env := infrastructure.GetEnvironment()
+
+		s3CloudStorage = NewS3CloudStorage(
+			GetS3Client(),
+			int64(env.AwsS3UploadPartSizeMb)*1024*1024,
+			env.AwsS3UploadConcurrency,
+		)
 	})
 
 	return s3CloudStorage
 }
 
+// NewS3CloudStorage creates an S3CloudStorage using the given client.
+// Files larger than the part size are uploaded in parts of that size, with the given parts in parallel.
+func NewS3CloudStorage(client *s3.Client, uploadPartSize int64, uploadConcurrency int) *S3CloudStorage {
+	if uploadPartSize < manager.MinUploadPartSize {
+		panic(fmt.Sprintf("The S3 upload part size must be at least %d bytes", manager.MinUploadPartSize))
+	}
+	if uploadConcurrency < 1 {
+		panic("The S3 upload concurrency must be at least 1")
+	}
+
+	return &S3CloudStorage{
+		client: client,
+		uploader: manager.NewUploader(client, func(u *manager.Uploader) {
+			u.PartSize = uploadPartSize
+			u.Concurrency = uploadConcurrency
+		}),
+		presignClient: s3.NewPresignClient(client),
+	}
+}
+
+// GetS3Client returns the S3 client shared by every component talking to S3
+func GetS3Client() *s3.Client {
+	s3ClientOnce.Do(func() {
+		s3Client = createS3Client()
+	})
+
+	return s3Client
+}
+
 // createS3Client creates a shared S3 client instance
 func createS3Client() *s3.Client {
 	env := infrastructure.GetEnvironment()
 
-	// Load the AWS SDK config
-	cfg, err := config.LoadDefaultConfig(context.Background(),
+	// Load the AWS SDK config, using the configured keys if any or the default credentials chain otherwise
+	configOptions := []func(*config.LoadOptions) error{
 		config.WithRegion(env.AwsRegion),
-		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+	}
+	if env.AwsAccessKeyID != "" && env.AwsSecretAccessKey != "" {
+		configOptions = append(configOptions, config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
 			return aws.Credentials{
 				AccessKeyID:     env.AwsAccessKeyID,
 				SecretAccessKey: env.AwsSecretAccessKey,
 			}, nil
-		})),
-	)
+		})))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), configOptions...)
 	if err != nil {
 		panic("Unable to load AWS SDK config: " + err.Error())
 	}
@@ -65,27 +118,67 @@ func createS3Client() *s3.Client {
 	return client
 }
 
-// UploadFile uploads a file to S3 and returns the URL
-func (s *S3CloudStorage) UploadFile(request definitions.UploadFileRequest) (string, error) {
-	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+// UploadFile uploads a file to S3, streaming it in parts if it is larger than the part size.
+// Readers implementing io.ReaderAt and io.Seeker (E.g, files) are read in place instead of buffering each part.
+func (s *S3CloudStorage) UploadFile(ctx context.Context, request definitions.UploadFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "S3CloudStorage.UploadFile", trace.WithAttributes(
+		attribute.String("s3.bucket", request.FileFolder),
+		attribute.String("s3.key", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	_, err = s.uploader.Upload(ctx, buildPutObjectInput(request))
+
+	return err
+}
+
+// buildPutObjectInput converts the upload request to the S3 input, leaving the empty options to the bucket defaults
+func buildPutObjectInput(request definitions.UploadFileRequest) *s3.PutObjectInput {
+	input := &s3.PutObjectInput{
 		Bucket:      aws.String(request.FileFolder),
 		Key:         aws.String(request.FilePath),
 		Body:        request.FileReader,
 		ContentType: aws.String(request.ContentType),
-	})
+		Metadata:    request.Metadata,
+	}
 
-	if err != nil {
-		return "", err
+	if len(request.Tags) > 0 {
+		input.Tagging = aws.String(encodeS3Tags(request.Tags))
+	}
+	if request.CacheControl != "" {
+		input.CacheControl = aws.String(request.CacheControl)
+	}
+	if request.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(request.ContentDisposition)
+	}
+	if request.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(request.ServerSideEncryption)
+	}
+	if request.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(request.KMSKeyID)
+	}
+	if request.StorageClass != "" {
+		input.StorageClass = types.StorageClass(request.StorageClass)
+	}
+
+	return input
+}
+
+// encodeS3Tags encodes the tags as URL query parameters, as S3 expects them
+func encodeS3Tags(tags map[string]string) string {
+	values := url.Values{}
+	for key, value := range tags {
+		values.Set(key, value)
 	}
 
-	// Construct the public URL
-	publicURL := fmt.Sprintf("%s/%s/%s", request.PublicURLPrefix, request.FileFolder, request.FilePath)
-	return publicURL, nil
+	return values.Encode()
 }
 
 // FileExists checks if a file exists in the S3 bucket
-func (s *S3CloudStorage) FileExists(request definitions.FileExistsRequest) (bool, error) {
-	_, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+func (s *S3CloudStorage) FileExists(ctx context.Context, request definitions.FileExistsRequest) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(request.FileFolder),
 		Key:    aws.String(request.FilePath),
 	})
@@ -103,3 +196,98 @@ func (s *S3CloudStorage) FileExists(request definitions.FileExistsRequest) (bool
 
 	return true, nil
 }
+
+// GetDownloadURL returns the public URL of the file, or a presigned GET URL valid for at most seven days
+func (s *S3CloudStorage) GetDownloadURL(ctx context.Context, request definitions.GetDownloadURLRequest) (*definitions.DownloadURL, error) {
+	if request.Mode != definitions.DOWNLOAD_URL_MODE_PRESIGNED {
+		return buildPublicDownloadURL(request), nil
+	}
+
+	expiration := min(request.Expiration, S3_MAX_PRESIGN_EXPIRATION)
+	expiresAt := time.Now().Add(expiration)
+
+	presignedRequest, err := s.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(request.FileFolder),
+		Key:    aws.String(request.FilePath),
+	}, s3.WithPresignExpires(expiration))
+	if err != nil {
+		return nil, fmt.Errorf("error presigning S3 URL: %w", err)
+	}
+
+	return &definitions.DownloadURL{
+		URL:       presignedRequest.URL,
+		ExpiresAt: &expiresAt,
+	}, nil
+}
+
+// CopyFile copies the file with a server-side copy, replacing its metadata and tags with the requested ones
+func (s *S3CloudStorage) CopyFile(ctx context.Context, request definitions.CopyFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "S3CloudStorage.CopyFile", trace.WithAttributes(
+		attribute.String("s3.source_bucket", request.SourceFileFolder),
+		attribute.String("s3.source_key", request.SourceFilePath),
+		attribute.String("s3.bucket", request.FileFolder),
+		attribute.String("s3.key", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	_, err = s.client.CopyObject(ctx, buildCopyObjectInput(request))
+
+	return err
+}
+
+// buildCopyObjectInput converts the copy request to the S3 input, leaving the empty options to the bucket defaults
+func buildCopyObjectInput(request definitions.CopyFileRequest) *s3.CopyObjectInput {
+	// The copy source is the URL-encoded "{bucket}/{key}"
+	copySource := (&url.URL{Path: request.SourceFileFolder + "/" + request.SourceFilePath}).EscapedPath()
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(request.FileFolder),
+		Key:               aws.String(request.FilePath),
+		CopySource:        aws.String(copySource),
+		MetadataDirective: types.MetadataDirectiveReplace,
+		TaggingDirective:  types.TaggingDirectiveReplace,
+		ContentType:       aws.String(request.ContentType),
+		Metadata:          request.Metadata,
+	}
+
+	if len(request.Tags) > 0 {
+		input.Tagging = aws.String(encodeS3Tags(request.Tags))
+	}
+	if request.CacheControl != "" {
+		input.CacheControl = aws.String(request.CacheControl)
+	}
+	if request.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(request.ContentDisposition)
+	}
+	if request.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(request.ServerSideEncryption)
+	}
+	if request.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(request.KMSKeyID)
+	}
+	if request.StorageClass != "" {
+		input.StorageClass = types.StorageClass(request.StorageClass)
+	}
+
+	return input
+}
+
+// DeleteFile deletes the file from the S3 bucket, S3 reports success even if it does not exist
+func (s *S3CloudStorage) DeleteFile(ctx context.Context, request definitions.DeleteFileRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "S3CloudStorage.DeleteFile", trace.WithAttributes(
+		attribute.String("s3.bucket", request.FileFolder),
+		attribute.String("s3.key", request.FilePath),
+	))
+	defer func() {
+		sharedUtilities.EndSpan(span, err)
+	}()
+
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(request.FileFolder),
+		Key:    aws.String(request.FilePath),
+	})
+
+	return err
+}
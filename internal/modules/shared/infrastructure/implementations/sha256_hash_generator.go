@@ -0,0 +1,38 @@
+package implementations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+)
+
+// Sha256HashGenerator implements the HashGenerator interface using the SHA-256 algorithm,
+// so the hashes are collision-resistant enough to identify the requests of different clients
+type Sha256HashGenerator struct{}
+
+var (
+	sha256HashGenerator *Sha256HashGenerator
+	sha256HashOnce      sync.Once
+)
+
+// GetSha256HashGenerator returns a singleton instance of Sha256HashGenerator
+func GetSha256HashGenerator() definitions.HashGenerator {
+	sha256HashOnce.Do(func() {
+		sha256HashGenerator = &Sha256HashGenerator{}
+	})
+
+	return sha256HashGenerator
+}
+
+// GenerateHash generates the hexadecimal SHA-256 hash of the given input string
+func (s *Sha256HashGenerator) GenerateHash(input string) (string, error) {
+	if input == "" {
+		return "", fmt.Errorf("input string cannot be empty")
+	}
+
+	hash := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,201 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+)
+
+// expiringUrlCacheStorage is a UrlCacheStorage telling how long its URLs live
+type expiringUrlCacheStorage interface {
+	// GetExpiration returns the remaining lifetime of the URL, 0 if it never expires or negative if it is not cached
+	GetExpiration(ctx context.Context, key string) (time.Duration, error)
+}
+
+// TieredCacheStorage implements the UrlCacheStorage interface with a local cache in front of a remote one,
+// so the hot URLs are read from memory. The local URLs expire sooner than the remote ones, which bounds how long
+// a replica serves a URL removed by another one. The errors of the remote cache are logged and treated as misses,
+// so an outage does not fail the requests.
+type TieredCacheStorage struct {
+	local           definitions.UrlCacheStorage
+	remote          definitions.UrlCacheStorage
+	localExpiration int64 // Seconds the URLs are kept in the local cache at most, as long as in the remote one if 0
+}
+
+var (
+	tieredCacheStorage     *TieredCacheStorage
+	tieredCacheStorageOnce sync.Once
+)
+
+// NewTieredCacheStorage creates a TieredCacheStorage reading the local cache before the remote one,
+// which keeps the URLs up to the given seconds, or as long as the remote one if 0
+func NewTieredCacheStorage(
+	local definitions.UrlCacheStorage,
+	remote definitions.UrlCacheStorage,
+	localExpiration int64,
+) *TieredCacheStorage {
+	return &TieredCacheStorage{
+		local:           local,
+		remote:          remote,
+		localExpiration: localExpiration,
+	}
+}
+
+// GetTieredCacheStorage returns a singleton instance of TieredCacheStorage with an in-memory cache in front of Redis
+func GetTieredCacheStorage() definitions.UrlCacheStorage {
+	tieredCacheStorageOnce.Do(func() {
+		env := infrastructure.GetEnvironment()
+
+		tieredCacheStorage = NewTieredCacheStorage(
+			NewInMemoryCacheStorage(env.UrlCacheMemoryMaxEntries),
+			GetRedisCacheStorage(),
+			env.UrlCacheLocalTTLSeconds,
+		)
+	})
+
+	return tieredCacheStorage
+}
+
+// Set stores the URL in both caches, expiring it sooner in the local one
+func (s *TieredCacheStorage) Set(ctx context.Context, request definitions.SetURLCacheRequest) error {
+	localRequest := request
+	if s.localExpiration > 0 && (request.Expiration <= 0 || s.localExpiration < request.Expiration) {
+		localRequest.Expiration = s.localExpiration
+	}
+	if err := s.local.Set(ctx, localRequest); err != nil {
+		return err
+	}
+
+	if err := s.remote.Set(ctx, request); err != nil {
+		logRemoteCacheError(err, request.Key, "Failed to set URL in remote cache")
+	}
+
+	return nil
+}
+
+// Get retrieves the URL from the local cache, or from the remote one, keeping it in the local cache afterwards
+func (s *TieredCacheStorage) Get(ctx context.Context, key string) (*string, error) {
+	value, err := s.local.Get(ctx, key)
+	if err != nil || value != nil {
+		return value, err
+	}
+
+	value, err = s.remote.Get(ctx, key)
+	if err != nil {
+		logRemoteCacheError(err, key, "Failed to get URL from remote cache")
+		return nil, nil
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	s.keepLocally(ctx, key, *value)
+
+	return value, nil
+}
+
+// keepLocally stores the URL read from the remote cache in the local one, so it does not outlive the remote URL.
+// The URL is not kept if its remaining lifetime or its file, needed to purge it locally, are unknown.
+func (s *TieredCacheStorage) keepLocally(ctx context.Context, key string, value string) {
+	remote, knowsExpiration := s.remote.(expiringUrlCacheStorage)
+	if !knowsExpiration {
+		return
+	}
+
+	remaining, err := remote.GetExpiration(ctx, key)
+	if err != nil {
+		logRemoteCacheError(err, key, "Failed to get URL expiration from remote cache")
+		return
+	}
+	if remaining < 0 {
+		return
+	}
+
+	expiration := s.localExpiration
+	if remaining > 0 {
+		remainingSeconds := int64(remaining / time.Second)
+		if remainingSeconds <= 0 {
+			return
+		}
+		if expiration <= 0 || remainingSeconds < expiration {
+			expiration = remainingSeconds
+		}
+	}
+
+	file, err := s.remote.GetFile(ctx, key)
+	if err != nil {
+		logRemoteCacheError(err, key, "Failed to get cached file from remote cache")
+		return
+	}
+	if file == nil {
+		return
+	}
+
+	_ = s.local.Set(ctx, definitions.SetURLCacheRequest{
+		Key:        key,
+		Value:      value,
+		Expiration: expiration,
+		File:       *file,
+	})
+}
+
+// Delete removes the URL from both caches
+func (s *TieredCacheStorage) Delete(ctx context.Context, key string) error {
+	if err := s.local.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	if err := s.remote.Delete(ctx, key); err != nil {
+		logRemoteCacheError(err, key, "Failed to delete URL from remote cache")
+	}
+
+	return nil
+}
+
+// GetFile retrieves the file the cached URL points to from the local cache, or from the remote one
+func (s *TieredCacheStorage) GetFile(ctx context.Context, key string) (*definitions.CachedFile, error) {
+	file, err := s.local.GetFile(ctx, key)
+	if err != nil || file != nil {
+		return file, err
+	}
+
+	file, err = s.remote.GetFile(ctx, key)
+	if err != nil {
+		logRemoteCacheError(err, key, "Failed to get cached file from remote cache")
+		return nil, nil
+	}
+
+	return file, nil
+}
+
+// DeleteByFile removes every cached URL pointing to the file from both caches.
+// It returns the URLs removed from the remote cache, which holds them all, or the local ones if it is unavailable.
+func (s *TieredCacheStorage) DeleteByFile(ctx context.Context, file definitions.CachedFile) (int, error) {
+	localDeleted, err := s.local.DeleteByFile(ctx, file)
+	if err != nil {
+		return 0, err
+	}
+
+	remoteDeleted, err := s.remote.DeleteByFile(ctx, file)
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("file", file.FileFolder+"/"+file.FilePath).
+			Warn("Failed to delete URLs of file from remote cache")
+		return localDeleted, nil
+	}
+
+	return max(localDeleted, remoteDeleted), nil
+}
+
+// logRemoteCacheError logs the error of the remote cache, which is treated as a miss
+func logRemoteCacheError(err error, key string, message string) {
+	sharedUtilities.GetLogger().
+		WithError(err).
+		WithField("key", key).
+		Warn(message)
+}
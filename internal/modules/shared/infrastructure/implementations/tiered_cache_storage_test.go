@@ -0,0 +1,152 @@
+package implementations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRemoteCacheStorage is an in-memory remote cache that knows the lifetime of its URLs and can be made unavailable
+type fakeRemoteCacheStorage struct {
+	*InMemoryCacheStorage
+	remaining time.Duration // Lifetime returned for every cached URL
+	err       error         // Returned by every operation if set
+}
+
+// newFakeRemoteCacheStorage creates an empty fakeRemoteCacheStorage whose URLs live for the given time
+func newFakeRemoteCacheStorage(remaining time.Duration) *fakeRemoteCacheStorage {
+	return &fakeRemoteCacheStorage{InMemoryCacheStorage: NewInMemoryCacheStorage(10), remaining: remaining}
+}
+
+// Set stores the URL unless the cache is unavailable
+func (s *fakeRemoteCacheStorage) Set(ctx context.Context, request definitions.SetURLCacheRequest) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.InMemoryCacheStorage.Set(ctx, request)
+}
+
+// Get retrieves the URL unless the cache is unavailable
+func (s *fakeRemoteCacheStorage) Get(ctx context.Context, key string) (*string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.InMemoryCacheStorage.Get(ctx, key)
+}
+
+// Delete removes the URL unless the cache is unavailable
+func (s *fakeRemoteCacheStorage) Delete(ctx context.Context, key string) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.InMemoryCacheStorage.Delete(ctx, key)
+}
+
+// GetFile retrieves the file of the URL unless the cache is unavailable
+func (s *fakeRemoteCacheStorage) GetFile(ctx context.Context, key string) (*definitions.CachedFile, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.InMemoryCacheStorage.GetFile(ctx, key)
+}
+
+// DeleteByFile removes the URLs of the file unless the cache is unavailable
+func (s *fakeRemoteCacheStorage) DeleteByFile(ctx context.Context, file definitions.CachedFile) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.InMemoryCacheStorage.DeleteByFile(ctx, file)
+}
+
+// GetExpiration returns the configured lifetime of the cached URLs, negative if the URL is not cached
+func (s *fakeRemoteCacheStorage) GetExpiration(ctx context.Context, key string) (time.Duration, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	if value, _ := s.InMemoryCacheStorage.Get(ctx, key); value == nil {
+		return -1, nil
+	}
+	return s.remaining, nil
+}
+
+// TestTieredCacheStorage_KeepLocally tests the URLs read from the remote cache are kept locally
+// no longer than they live remotely nor than the local expiration
+func TestTieredCacheStorage_KeepLocally(t *testing.T) {
+	ctx := context.Background()
+	report := definitions.CachedFile{FileFolder: "reports", FilePath: "report.pdf"}
+
+	tests := []struct {
+		name               string
+		remaining          time.Duration
+		localExpiration    int64
+		expectedExpiration time.Duration
+	}{
+		{name: "remote URL expiring sooner", remaining: 30 * time.Second, localExpiration: 60, expectedExpiration: 30 * time.Second},
+		{name: "remote URL expiring later", remaining: time.Hour, localExpiration: 60, expectedExpiration: time.Minute},
+		{name: "remote URL never expiring", remaining: 0, localExpiration: 60, expectedExpiration: time.Minute},
+		{name: "no local expiration", remaining: 0, localExpiration: 0, expectedExpiration: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			local := NewInMemoryCacheStorage(10)
+			remote := newFakeRemoteCacheStorage(test.remaining)
+			_ = remote.Set(ctx, definitions.SetURLCacheRequest{Key: "report", Value: "https://cdn.example.com/report", File: report})
+			storage := NewTieredCacheStorage(local, remote, test.localExpiration)
+
+			value, err := storage.Get(ctx, "report")
+			assert.NoError(t, err, "Getting a remote URL should succeed")
+			assert.NotNil(t, value, "Remote URL should be returned")
+
+			if assert.Contains(t, local.entries, "report", "Remote URL should be kept locally") {
+				expiresAt := local.entries["report"].Value.(*inMemoryCacheEntry).expiresAt
+				if test.expectedExpiration == 0 {
+					assert.True(t, expiresAt.IsZero(), "Local URL should never expire")
+				} else {
+					assert.WithinDuration(t, time.Now().Add(test.expectedExpiration), expiresAt, time.Second, "Local expiration should match")
+				}
+			}
+		})
+	}
+}
+
+// TestTieredCacheStorage_SubSecondRemaining tests a remote URL about to expire is not kept locally
+func TestTieredCacheStorage_SubSecondRemaining(t *testing.T) {
+	ctx := context.Background()
+	local := NewInMemoryCacheStorage(10)
+	remote := newFakeRemoteCacheStorage(500 * time.Millisecond)
+	_ = remote.Set(ctx, definitions.SetURLCacheRequest{Key: "report", Value: "https://cdn.example.com/report"})
+	storage := NewTieredCacheStorage(local, remote, 60)
+
+	value, _ := storage.Get(ctx, "report")
+	assert.NotNil(t, value, "Remote URL should be returned")
+	assert.NotContains(t, local.entries, "report", "URL about to expire should not be kept locally")
+}
+
+// TestTieredCacheStorage_RemoteErrors tests the remote errors are treated as misses, falling back to the local cache
+func TestTieredCacheStorage_RemoteErrors(t *testing.T) {
+	ctx := context.Background()
+	report := definitions.CachedFile{FileFolder: "reports", FilePath: "report.pdf"}
+	local := NewInMemoryCacheStorage(10)
+	remote := newFakeRemoteCacheStorage(0)
+	remote.err = errors.New("connection refused")
+	storage := NewTieredCacheStorage(local, remote, 60)
+
+	assert.NoError(t, storage.Set(ctx, definitions.SetURLCacheRequest{Key: "report", Value: "https://cdn.example.com/report", File: report}), "Setting a URL should succeed without the remote cache")
+
+	file, err := storage.GetFile(ctx, "report")
+	assert.NoError(t, err, "Getting a local file should succeed")
+	assert.Equal(t, &report, file, "Local file should be returned")
+
+	missing, err := storage.GetFile(ctx, "missing")
+	assert.NoError(t, err, "Remote failures should be misses")
+	assert.Nil(t, missing, "Missing file should not be returned")
+
+	deleted, err := storage.DeleteByFile(ctx, report)
+	assert.NoError(t, err, "Purging a file should succeed without the remote cache")
+	assert.Equal(t, 1, deleted, "Local URLs should be counted")
+}
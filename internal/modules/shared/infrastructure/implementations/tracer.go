@@ -0,0 +1,6 @@
+package implementations
+
+import "go.opentelemetry.io/otel"
+
+// tracer creates the spans of the shared implementations
+var tracer = otel.Tracer("github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations")
@@ -0,0 +1,22 @@
+package implementations
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+)
+
+// GetUrlCacheStorage returns the UrlCacheStorage implementation selected by the URL_CACHE_DRIVER environment variable
+func GetUrlCacheStorage() definitions.UrlCacheStorage {
+	urlCacheDriver := infrastructure.GetEnvironment().UrlCacheDriver
+
+	switch urlCacheDriver {
+	case infrastructure.URL_CACHE_DRIVER_REDIS:
+		return GetRedisCacheStorage()
+	case infrastructure.URL_CACHE_DRIVER_MEMORY:
+		return GetInMemoryCacheStorage()
+	case infrastructure.URL_CACHE_DRIVER_TIERED:
+		return GetTieredCacheStorage()
+	default:
+		panic("Unknown URL cache driver: " + urlCacheDriver)
+	}
+}
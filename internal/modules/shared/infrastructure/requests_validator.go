@@ -34,6 +34,14 @@ func GetUserFriendlyValidationErrorMessage(err validator.FieldError) string {
 		return "Value must be greater than or equal to " + err.Param() + " field"
 	case "http_url":
 		return "Must be a valid http URL"
+	case "required_without":
+		return "This field is required when " + err.Param() + " is not present"
+	case "required_without_all":
+		return "This field is required when none of " + err.Param() + " are present"
+	case "excluded_with":
+		return "This field cannot be present along with any of " + err.Param()
+	case "excluded_without":
+		return "This field can only be present along with " + err.Param()
 	default:
 		return "Invalid value"
 	}
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	TRACING_EXPORTER_NONE   = "none"   // Spans are not recorded, but the incoming trace context is still propagated
+	TRACING_EXPORTER_STDOUT = "stdout" // Spans are printed to the standard output, useful for local runs
+	TRACING_EXPORTER_OTLP   = "otlp"   // Spans are sent to an OTLP collector over HTTP
+)
+
+var tracingOnce sync.Once
+
+// SetupTracing configures the global OpenTelemetry tracer provider according to the environment
+// and the W3C trace context propagator, so the traces of the callers are continued.
+// Only the first call has effect.
+func SetupTracing() {
+	tracingOnce.Do(func() {
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		))
+
+		env := GetEnvironment()
+		if env.TracingExporter == TRACING_EXPORTER_NONE {
+			return
+		}
+
+		exporter, err := createSpanExporter(env)
+		if err != nil {
+			log.Fatal("[ERROR] ", err.Error())
+		}
+
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(env.TracingSampleRatio))),
+			sdktrace.WithResource(resource.NewSchemaless(
+				semconv.ServiceName(env.TracingServiceName),
+			)),
+		))
+	})
+}
+
+// createSpanExporter creates the exporter selected in the environment
+func createSpanExporter(env *EnvironmentSpec) (sdktrace.SpanExporter, error) {
+	switch env.TracingExporter {
+	case TRACING_EXPORTER_STDOUT:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case TRACING_EXPORTER_OTLP:
+		// The standard OTEL_EXPORTER_OTLP_* variables are used for anything not set here
+		options := make([]otlptracehttp.Option, 0)
+		if env.TracingOtlpEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(env.TracingOtlpEndpoint))
+		}
+		if env.TracingOtlpInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(context.Background(), options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", env.TracingExporter)
+	}
+}
@@ -0,0 +1,110 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/domain/dto"
+)
+
+// DeleteExpiredFilesUseCase is the use case for deleting the uploaded files whose retention passed.
+type DeleteExpiredFilesUseCase struct {
+	// CloudStorage is the interface for the storage holding the files
+	CloudStorage sharedDefinitions.CloudStorage
+	// FileExpirationStorage is the interface for the record of the expiring files
+	FileExpirationStorage sharedDefinitions.FileExpirationStorage
+	// DeduplicatedFileStorage is the interface for the record of the files copied from each deduplicated PDF
+	DeduplicatedFileStorage sharedDefinitions.DeduplicatedFileStorage
+	// BatchSize is the number of expired files listed at once
+	BatchSize int
+}
+
+// Execute deletes every file expired at the time of the call, in batches.
+// Each file is claimed before being deleted, so files uploaded again in the meantime are kept,
+// and scheduled again if the deletion fails, so it is retried in the next execution.
+func (u *DeleteExpiredFilesUseCase) Execute(ctx context.Context) (*dto.DeletedExpiredFiles, error) {
+	now := time.Now()
+	result := &dto.DeletedExpiredFiles{}
+
+	for {
+		files, err := u.FileExpirationStorage.ListExpired(ctx, now, u.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("error listing expired files: %w", err)
+		}
+
+		batchFailures := 0
+		for _, file := range files {
+			deleted, err := u.deleteExpiredFile(ctx, file, now)
+			if err != nil {
+				sharedUtilities.GetLogger().
+					WithError(err).
+					WithField("file_folder", file.FileFolder).
+					WithField("file_path", file.FilePath).
+					Error("Failed to delete expired file")
+
+				batchFailures++
+				continue
+			}
+
+			if deleted {
+				result.DeletedFiles++
+			}
+		}
+		result.FailedFiles += batchFailures
+
+		// The failed files are scheduled again, so they would be listed forever in this execution
+		if len(files) == 0 || len(files) < u.BatchSize || batchFailures > 0 {
+			return result, nil
+		}
+	}
+}
+
+// deleteExpiredFile claims and deletes the file, returning whether it was deleted by this execution
+func (u *DeleteExpiredFilesUseCase) deleteExpiredFile(
+	ctx context.Context,
+	file sharedDefinitions.ExpiringFile,
+	now time.Time,
+) (bool, error) {
+	claimed, err := u.FileExpirationStorage.Claim(ctx, file, now)
+	if err != nil {
+		return false, fmt.Errorf("error claiming expired file: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	err = u.CloudStorage.DeleteFile(ctx, sharedDefinitions.DeleteFileRequest{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err == nil {
+		u.removeFileCopy(ctx, file)
+		return true, nil
+	}
+
+	// Schedule the file again, so the deletion is retried
+	if scheduleErr := u.FileExpirationStorage.Schedule(ctx, file, now); scheduleErr != nil {
+		return false, fmt.Errorf("error deleting file (%w) and scheduling it again: %w", err, scheduleErr)
+	}
+
+	return false, fmt.Errorf("error deleting file: %w", err)
+}
+
+// removeFileCopy forgets the deleted file if it was copied from a deduplicated PDF.
+// The deduplicated PDF expires along with its last copy, so it is deleted on its own.
+func (u *DeleteExpiredFilesUseCase) removeFileCopy(ctx context.Context, file sharedDefinitions.ExpiringFile) {
+	_, err := u.DeduplicatedFileStorage.RemoveCopy(ctx, sharedDefinitions.FileCopy{
+		FileFolder: file.FileFolder,
+		FilePath:   file.FilePath,
+	})
+	if err != nil {
+		sharedUtilities.GetLogger().
+			WithError(err).
+			WithField("file_folder", file.FileFolder).
+			WithField("file_path", file.FilePath).
+			Warn("Failed to remove the copy record of the expired file")
+	}
+}
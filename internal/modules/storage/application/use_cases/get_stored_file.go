@@ -0,0 +1,41 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/storage/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/domain/dto"
+	storageErrors "github.com/PChaparro/serpentarius/internal/modules/storage/domain/errors"
+)
+
+// GetStoredFileUseCase is the use case for serving a file stored by the server itself.
+type GetStoredFileUseCase struct {
+	// LocalFileStorage is the interface for the storage holding the files
+	LocalFileStorage definitions.LocalFileStorage
+}
+
+// Execute returns the path of the stored file in the filesystem.
+// When the storage requires signed URLs, the signature is verified before looking for the file.
+func (u *GetStoredFileUseCase) Execute(ctx context.Context, request dto.GetStoredFileRequest) (string, error) {
+	if u.LocalFileStorage.RequiresSignature() {
+		if request.ExpiresAt == nil || request.Signature == nil {
+			return "", storageErrors.NewFileSignatureNotValidError("The URL is not signed")
+		}
+
+		if !u.LocalFileStorage.VerifySignature(request.FileFolder, request.FilePath, *request.ExpiresAt, *request.Signature) {
+			return "", storageErrors.NewFileSignatureNotValidError("The signature does not match or expired")
+		}
+	}
+
+	path, err := u.LocalFileStorage.ResolveFilePath(request.FileFolder, request.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("error resolving stored file: %w", err)
+	}
+
+	if path == nil {
+		return "", storageErrors.NewStoredFileNotFoundError(request.FileFolder, request.FilePath)
+	}
+
+	return *path, nil
+}
@@ -0,0 +1,11 @@
+package definitions
+
+// LocalFileStorage is the interface for the storages whose files are served by the server itself
+type LocalFileStorage interface {
+	// RequiresSignature returns whether the files can only be served through signed URLs
+	RequiresSignature() bool
+	// VerifySignature checks the signature matches the file and the expiration time did not pass
+	VerifySignature(fileFolder string, filePath string, expiresAt int64, signature string) bool
+	// ResolveFilePath returns the path of the stored file in the filesystem, or nil if it does not exist
+	ResolveFilePath(fileFolder string, filePath string) (*string, error)
+}
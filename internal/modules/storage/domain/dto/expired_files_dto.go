@@ -0,0 +1,7 @@
+package dto
+
+// DeletedExpiredFiles represents the result of a cleanup of the expired files
+type DeletedExpiredFiles struct {
+	DeletedFiles int // Files deleted from the storage
+	FailedFiles  int // Files that could not be deleted, retried in the next cleanup
+}
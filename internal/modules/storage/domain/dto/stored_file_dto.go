@@ -0,0 +1,9 @@
+package dto
+
+// GetStoredFileRequest represents the request for serving a stored file
+type GetStoredFileRequest struct {
+	FileFolder string
+	FilePath   string
+	ExpiresAt  *int64  // Expiration time of the signed URL, as a Unix timestamp
+	Signature  *string // Signature of the signed URL
+}
@@ -0,0 +1,30 @@
+package errors
+
+import (
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+)
+
+// NewStoredFileNotFoundError creates the error returned when a stored file does not exist
+func NewStoredFileNotFoundError(fileFolder string, filePath string) sharedErrors.DomainError {
+	code := sharedErrors.STORED_FILE_NOT_FOUND_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "The requested file does not exist",
+		Metadata: map[string]any{
+			"directory": fileFolder,
+			"fileName":  filePath,
+		},
+	})
+}
+
+// NewFileSignatureNotValidError creates the error returned when a signed file URL is missing, tampered or expired
+func NewFileSignatureNotValidError(reason string) sharedErrors.DomainError {
+	code := sharedErrors.FILE_SIGNATURE_NOT_VALID_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "The file URL signature is not valid",
+		Metadata: map[string]any{
+			"reason": reason,
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/storage/application/use_cases"
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteExpiredFilesController handles the manual cleanup of the expired files.
+type DeleteExpiredFilesController struct {
+	UseCase use_cases.DeleteExpiredFilesUseCase
+}
+
+// Handle processes the request to delete the expired files right away.
+func (controller *DeleteExpiredFilesController) Handle(c *gin.Context) {
+	result, err := controller.UseCase.Execute(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Expired files deleted successfully",
+		"deletedFiles": result.DeletedFiles,
+		"failedFiles":  result.FailedFiles,
+	})
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/domain/dto"
+	storageErrors "github.com/PChaparro/serpentarius/internal/modules/storage/domain/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// GetStoredFileController handles the download of the files stored by the server itself.
+type GetStoredFileController struct {
+	UseCase use_cases.GetStoredFileUseCase
+}
+
+// Handle serves the file at "/{directory}/{fileName}", where the file name can contain slashes.
+// The "expires" and "signature" query parameters are forwarded for the signed URLs.
+func (controller *GetStoredFileController) Handle(c *gin.Context) {
+	// The first segment is the directory and the rest is the file name
+	fileFolder, filePath, found := strings.Cut(strings.TrimPrefix(c.Param("filepath"), "/"), "/")
+	if !found || fileFolder == "" || filePath == "" {
+		_ = c.Error(storageErrors.NewStoredFileNotFoundError(fileFolder, filePath))
+		return
+	}
+
+	request := dto.GetStoredFileRequest{
+		FileFolder: fileFolder,
+		FilePath:   filePath,
+	}
+
+	if rawExpiresAt, ok := c.GetQuery(sharedImplementations.LOCAL_FILE_EXPIRES_QUERY_PARAM); ok {
+		expiresAt, err := strconv.ParseInt(rawExpiresAt, 10, 64)
+		if err != nil {
+			_ = c.Error(storageErrors.NewFileSignatureNotValidError("The expiration time is not valid"))
+			return
+		}
+
+		request.ExpiresAt = &expiresAt
+	}
+
+	if signature, ok := c.GetQuery(sharedImplementations.LOCAL_FILE_SIGNATURE_QUERY_PARAM); ok {
+		request.Signature = &signature
+	}
+
+	// Call the use case
+	path, err := controller.UseCase.Execute(c.Request.Context(), request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.File(path)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/infrastructure/http/controllers"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/infrastructure/implementations"
+	"github.com/gin-gonic/gin"
+)
+
+// StorageRouter handles the routing for the storage module
+type StorageRouter struct{}
+
+// RegisterRootRoutes implements the RootRouterRegistry interface to serve the local files outside the API prefix.
+// The files are only served when they are stored in the local filesystem.
+func (sr *StorageRouter) RegisterRootRoutes(r *gin.RouterGroup) {
+	if sharedInfrastructure.GetEnvironment().StorageDriver != sharedInfrastructure.STORAGE_DRIVER_LOCAL {
+		return
+	}
+
+	getStoredFileController := &controllers.GetStoredFileController{
+		UseCase: use_cases.GetStoredFileUseCase{
+			LocalFileStorage: sharedImplementations.GetLocalCloudStorage(),
+		},
+	}
+	r.GET(sharedImplementations.LOCAL_FILES_ROUTE_PREFIX+"/*filepath", getStoredFileController.Handle)
+	r.HEAD(sharedImplementations.LOCAL_FILES_ROUTE_PREFIX+"/*filepath", getStoredFileController.Handle)
+}
+
+// RegisterRoutes implements the RouterRegistry interface to register the admin routes of the storage module.
+// It also starts the janitor deleting the expired files in the background.
+func (sr *StorageRouter) RegisterRoutes(r *gin.RouterGroup) {
+	env := sharedInfrastructure.GetEnvironment()
+
+	deleteExpiredFilesUseCase := use_cases.DeleteExpiredFilesUseCase{
+		CloudStorage:            sharedImplementations.GetCloudStorage(),
+		FileExpirationStorage:   sharedImplementations.GetFileExpirationStorage(),
+		DeduplicatedFileStorage: sharedImplementations.GetDeduplicatedFileStorage(),
+		BatchSize:               env.FileCleanupBatchSize,
+	}
+	implementations.GetExpiredFilesJanitor(&deleteExpiredFilesUseCase)
+
+	// Register the admin routes, all of them require authentication
+	adminGroup := r.Group("/admin", sharedMiddlewares.AuthMiddleware())
+
+	deleteExpiredFilesController := &controllers.DeleteExpiredFilesController{
+		UseCase: deleteExpiredFilesUseCase,
+	}
+	adminGroup.POST("/storage/cleanup", deleteExpiredFilesController.Handle)
+}
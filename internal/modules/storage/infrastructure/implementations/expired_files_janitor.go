@@ -0,0 +1,75 @@
+package implementations
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedUtilities "github.com/PChaparro/serpentarius/internal/modules/shared/utilities"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/application/use_cases"
+)
+
+// ExpiredFilesJanitor periodically deletes the uploaded files whose retention passed
+type ExpiredFilesJanitor struct {
+	useCase  *use_cases.DeleteExpiredFilesUseCase
+	interval time.Duration
+}
+
+var (
+	expiredFilesJanitor     *ExpiredFilesJanitor
+	expiredFilesJanitorOnce sync.Once
+)
+
+// GetExpiredFilesJanitor returns the singleton instance of the janitor.
+// It starts running on the first call with the interval of the environment, unless the interval is zero.
+func GetExpiredFilesJanitor(useCase *use_cases.DeleteExpiredFilesUseCase) *ExpiredFilesJanitor {
+	expiredFilesJanitorOnce.Do(func() {
+		env := sharedInfrastructure.GetEnvironment()
+
+		expiredFilesJanitor = &ExpiredFilesJanitor{
+			useCase:  useCase,
+			interval: time.Duration(env.FileCleanupIntervalSeconds) * time.Second,
+		}
+
+		if expiredFilesJanitor.interval <= 0 {
+			sharedUtilities.GetLogger().Info("Expired files janitor disabled")
+			return
+		}
+
+		go expiredFilesJanitor.run()
+
+		sharedUtilities.GetLogger().
+			WithField("interval_seconds", env.FileCleanupIntervalSeconds).
+			Info("Started expired files janitor")
+	})
+
+	return expiredFilesJanitor
+}
+
+// run deletes the expired files on every tick of the interval
+func (j *ExpiredFilesJanitor) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		// A cleanup cannot take longer than the interval, so they never overlap
+		ctx, cancel := context.WithTimeout(context.Background(), j.interval)
+		result, err := j.useCase.Execute(ctx)
+		cancel()
+
+		if err != nil {
+			sharedUtilities.GetLogger().
+				WithError(err).
+				Error("Failed to delete expired files")
+			continue
+		}
+
+		if result.DeletedFiles > 0 || result.FailedFiles > 0 {
+			sharedUtilities.GetLogger().
+				WithField("deleted_files", result.DeletedFiles).
+				WithField("failed_files", result.FailedFiles).
+				Info("Deleted expired files")
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	templateErrors "github.com/PChaparro/serpentarius/internal/modules/template/domain/errors"
+)
+
+// templateIDPattern restricts the template IDs to values that are safe to use in URLs and storage keys
+var templateIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// CreateTemplateVersionUseCase is the use case for storing a new version of a template.
+type CreateTemplateVersionUseCase struct {
+	// TemplateStorage is the interface for template storage operations
+	TemplateStorage definitions.TemplateStorage
+	// TemplateEngine is the interface for rendering templates
+	TemplateEngine definitions.TemplateEngine
+}
+
+// Execute validates the content and stores it as the next version of the template.
+func (u *CreateTemplateVersionUseCase) Execute(ctx context.Context, id string, content string) (*dto.Template, error) {
+	if !templateIDPattern.MatchString(id) {
+		return nil, templateErrors.NewTemplateNotValidError(
+			"The template ID must have between 1 and 64 letters, numbers, dashes or underscores",
+		)
+	}
+
+	// Make sure the template can be parsed before storing it
+	if err := u.TemplateEngine.Validate(content); err != nil {
+		return nil, templateErrors.NewTemplateNotValidError(err.Error())
+	}
+
+	template, err := u.TemplateStorage.Create(ctx, id, content)
+	if err != nil {
+		return nil, fmt.Errorf("error storing template: %w", err)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,33 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	templateErrors "github.com/PChaparro/serpentarius/internal/modules/template/domain/errors"
+)
+
+// DeleteTemplateUseCase is the use case for deleting all the versions of a template.
+type DeleteTemplateUseCase struct {
+	// TemplateStorage is the interface for template storage operations
+	TemplateStorage definitions.TemplateStorage
+}
+
+// Execute deletes the template, failing if it does not exist.
+func (u *DeleteTemplateUseCase) Execute(ctx context.Context, id string) error {
+	template, err := u.TemplateStorage.Get(ctx, id, nil)
+	if err != nil {
+		return fmt.Errorf("error getting template: %w", err)
+	}
+
+	if template == nil {
+		return templateErrors.NewTemplateNotFoundError(id, nil)
+	}
+
+	if err := u.TemplateStorage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("error deleting template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	templateErrors "github.com/PChaparro/serpentarius/internal/modules/template/domain/errors"
+)
+
+// GetTemplateUseCase is the use case for retrieving a version of a template.
+type GetTemplateUseCase struct {
+	// TemplateStorage is the interface for template storage operations
+	TemplateStorage definitions.TemplateStorage
+}
+
+// Execute returns the given version of the template, or the latest one if version is nil.
+func (u *GetTemplateUseCase) Execute(ctx context.Context, id string, version *int) (*dto.Template, error) {
+	template, err := u.TemplateStorage.Get(ctx, id, version)
+	if err != nil {
+		return nil, fmt.Errorf("error getting template: %w", err)
+	}
+
+	if template == nil {
+		return nil, templateErrors.NewTemplateNotFoundError(id, version)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,24 @@
+package use_cases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+)
+
+// ListTemplatesUseCase is the use case for listing the stored templates.
+type ListTemplatesUseCase struct {
+	// TemplateStorage is the interface for template storage operations
+	TemplateStorage definitions.TemplateStorage
+}
+
+// Execute returns the IDs of all the stored templates.
+func (u *ListTemplatesUseCase) Execute(ctx context.Context) ([]string, error) {
+	ids, err := u.TemplateStorage.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+
+	return ids, nil
+}
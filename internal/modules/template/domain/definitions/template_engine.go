@@ -0,0 +1,9 @@
+package definitions
+
+// TemplateEngine is the interface for rendering templates into HTML
+type TemplateEngine interface {
+	// Validate checks the content can be parsed as a template
+	Validate(content string) error
+	// Render executes the template content with the given data and returns the resulting HTML
+	Render(content string, data map[string]any) (string, error)
+}
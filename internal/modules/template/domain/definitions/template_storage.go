@@ -0,0 +1,22 @@
+package definitions
+
+import (
+	"context"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+)
+
+// TemplateStorage is the interface for storing versioned templates.
+// Versions are never reused, even after a template is deleted, so a
+// template ID and version always identify the same content.
+type TemplateStorage interface {
+	// Create stores the content as a new version of the template and returns it
+	Create(ctx context.Context, id string, content string) (*dto.Template, error)
+	// Get returns the given version of the template, or the latest one if version is nil.
+	// It returns nil if the template or version does not exist.
+	Get(ctx context.Context, id string, version *int) (*dto.Template, error)
+	// List returns the IDs of all the stored templates
+	List(ctx context.Context) ([]string, error)
+	// Delete removes all the versions of the template
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// Template represents a version of a named HTML template
+type Template struct {
+	ID        string
+	Version   int
+	Content   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+)
+
+// NewTemplateNotFoundError creates the error returned when a template or version does not exist
+func NewTemplateNotFoundError(id string, version *int) sharedErrors.DomainError {
+	code := sharedErrors.TEMPLATE_NOT_FOUND_ERROR_CODE
+	metadata := map[string]any{
+		"template": id,
+	}
+	if version != nil {
+		metadata["version"] = *version
+	}
+
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:     &code,
+		Message:  "The requested template does not exist",
+		Metadata: metadata,
+	})
+}
+
+// NewTemplateNotValidError creates the error returned when a template cannot be stored
+func NewTemplateNotValidError(reason string) sharedErrors.DomainError {
+	code := sharedErrors.TEMPLATE_NOT_VALID_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "The template is not valid",
+		Metadata: map[string]any{
+			"reason": reason,
+		},
+	})
+}
+
+// NewTemplateRenderFailedError creates the error returned when a template cannot be rendered with the given data
+func NewTemplateRenderFailedError(id string, version int, reason string) sharedErrors.DomainError {
+	code := sharedErrors.TEMPLATE_RENDER_FAILED_ERROR_CODE
+	return sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+		Code:    &code,
+		Message: "The template could not be rendered with the given data",
+		Metadata: map[string]any{
+			"template": id,
+			"version":  version,
+			"reason":   reason,
+		},
+	})
+}
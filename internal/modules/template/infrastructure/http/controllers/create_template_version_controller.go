@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/PChaparro/serpentarius/internal/modules/template/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/http/requests"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTemplateVersionController handles the creation of new template versions.
+type CreateTemplateVersionController struct {
+	UseCase use_cases.CreateTemplateVersionUseCase
+}
+
+// Handle processes the request to store a new version of a template.
+func (controller *CreateTemplateVersionController) Handle(c *gin.Context) {
+	// Get validated request from context
+	req := sharedMiddlewares.GetValidatedRequest(c).(*requests.CreateTemplateVersionRequest)
+
+	// Call the use case
+	template, err := controller.UseCase.Execute(c.Request.Context(), c.Param("id"), req.Content)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Template version created successfully",
+		"template": templateToResponse(template),
+	})
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/application/use_cases"
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteTemplateController handles the deletion of templates.
+type DeleteTemplateController struct {
+	UseCase use_cases.DeleteTemplateUseCase
+}
+
+// Handle processes the request to delete all the versions of a template.
+func (controller *DeleteTemplateController) Handle(c *gin.Context) {
+	// Call the use case
+	if err := controller.UseCase.Execute(c.Request.Context(), c.Param("id")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted successfully",
+	})
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/application/use_cases"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTemplateController handles the retrieval of templates.
+type GetTemplateController struct {
+	UseCase use_cases.GetTemplateUseCase
+}
+
+// Handle processes the request to get a template, optionally filtered by the "version" query parameter.
+func (controller *GetTemplateController) Handle(c *gin.Context) {
+	var version *int
+
+	// Parse the requested version, if any
+	if rawVersion, ok := c.GetQuery("version"); ok {
+		parsedVersion, err := strconv.Atoi(rawVersion)
+		if err != nil || parsedVersion < 1 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": "Validation failed",
+				"errors":  []string{"Field 'version': Value must be greater than 0"},
+			})
+			return
+		}
+
+		version = &parsedVersion
+	}
+
+	// Call the use case
+	template, err := controller.UseCase.Execute(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Template retrieved successfully",
+		"template": templateToResponse(template),
+	})
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/application/use_cases"
+	"github.com/gin-gonic/gin"
+)
+
+// ListTemplatesController handles the listing of templates.
+type ListTemplatesController struct {
+	UseCase use_cases.ListTemplatesUseCase
+}
+
+// Handle processes the request to list the IDs of the stored templates.
+func (controller *ListTemplatesController) Handle(c *gin.Context) {
+	// Call the use case
+	ids, err := controller.UseCase.Execute(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Templates retrieved successfully",
+		"templates": ids,
+	})
+}
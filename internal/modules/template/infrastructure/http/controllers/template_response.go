@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// templateToResponse converts a template to its JSON representation
+func templateToResponse(template *dto.Template) gin.H {
+	return gin.H{
+		"id":        template.ID,
+		"version":   template.Version,
+		"content":   template.Content,
+		"createdAt": template.CreatedAt,
+	}
+}
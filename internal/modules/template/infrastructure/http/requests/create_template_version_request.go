@@ -0,0 +1,6 @@
+package requests
+
+// CreateTemplateVersionRequest represents the request to store a new version of a template
+type CreateTemplateVersionRequest struct {
+	Content string `json:"content" validate:"required"`
+}
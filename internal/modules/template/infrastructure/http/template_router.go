@@ -0,0 +1,56 @@
+package http
+
+import (
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/PChaparro/serpentarius/internal/modules/template/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/http/controllers"
+	"github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/http/requests"
+	"github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/implementations"
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateRouter handles the routing for the template module
+type TemplateRouter struct{}
+
+// RegisterRoutes implements the RouterRegistry interface to register all routes for the template module
+func (tr *TemplateRouter) RegisterRoutes(r *gin.RouterGroup) {
+	// Register the template routes, all of them require authentication
+	templateGroup := r.Group("/templates", sharedMiddlewares.AuthMiddleware())
+
+	// List templates
+	listTemplatesController := &controllers.ListTemplatesController{
+		UseCase: use_cases.ListTemplatesUseCase{
+			TemplateStorage: implementations.GetTemplateStorage(),
+		},
+	}
+	templateGroup.GET("", listTemplatesController.Handle)
+
+	// Create a new template version
+	createTemplateVersionController := &controllers.CreateTemplateVersionController{
+		UseCase: use_cases.CreateTemplateVersionUseCase{
+			TemplateStorage: implementations.GetTemplateStorage(),
+			TemplateEngine:  implementations.GetHTMLTemplateEngine(),
+		},
+	}
+	templateGroup.PUT(
+		"/:id",
+		sharedMiddlewares.RequestValidationMiddleware(requests.CreateTemplateVersionRequest{}),
+		createTemplateVersionController.Handle,
+	)
+
+	// Get a template version
+	getTemplateController := &controllers.GetTemplateController{
+		UseCase: use_cases.GetTemplateUseCase{
+			TemplateStorage: implementations.GetTemplateStorage(),
+		},
+	}
+	templateGroup.GET("/:id", getTemplateController.Handle)
+
+	// Delete a template
+	deleteTemplateController := &controllers.DeleteTemplateController{
+		UseCase: use_cases.DeleteTemplateUseCase{
+			TemplateStorage: implementations.GetTemplateStorage(),
+		},
+	}
+	templateGroup.DELETE("/:id", deleteTemplateController.Handle)
+}
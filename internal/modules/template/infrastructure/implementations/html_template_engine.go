@@ -0,0 +1,59 @@
+package implementations
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sync"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+)
+
+// HTMLTemplateEngine implements the TemplateEngine interface using Go's html/template package,
+// which escapes the data according to the context it is rendered in
+type HTMLTemplateEngine struct{}
+
+var (
+	htmlTemplateEngine     *HTMLTemplateEngine
+	htmlTemplateEngineOnce sync.Once
+)
+
+// GetHTMLTemplateEngine returns a singleton instance of HTMLTemplateEngine
+func GetHTMLTemplateEngine() definitions.TemplateEngine {
+	htmlTemplateEngineOnce.Do(func() {
+		htmlTemplateEngine = &HTMLTemplateEngine{}
+	})
+
+	return htmlTemplateEngine
+}
+
+// Validate checks the content can be parsed as an html/template
+func (e *HTMLTemplateEngine) Validate(content string) error {
+	_, err := e.parse(content)
+	return err
+}
+
+// Render executes the template content with the given data
+func (e *HTMLTemplateEngine) Render(content string, data map[string]any) (string, error) {
+	parsedTemplate, err := e.parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	if err := parsedTemplate.Execute(&output, data); err != nil {
+		return "", fmt.Errorf("error executing template: %w", err)
+	}
+
+	return output.String(), nil
+}
+
+// parse parses the content failing on missing keys, so typos in the data are not silently ignored
+func (e *HTMLTemplateEngine) parse(content string) (*template.Template, error) {
+	parsedTemplate, err := template.New("template").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+
+	return parsedTemplate, nil
+}
@@ -0,0 +1,105 @@
+package implementations
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+)
+
+// InMemoryTemplateStorage implements the TemplateStorage interface keeping the templates in the process memory.
+// The latest version of each template is kept when it is deleted so versions are never reused.
+// It is intended for tests and single-node setups, as the templates are lost when the process stops.
+type InMemoryTemplateStorage struct {
+	mutex          sync.RWMutex
+	latestVersions map[string]int                  // Latest version of each template, deleted or not
+	templates      map[string]map[int]dto.Template // Versions of each stored template
+}
+
+var (
+	inMemoryTemplateStorage     definitions.TemplateStorage
+	inMemoryTemplateStorageOnce sync.Once
+)
+
+// NewInMemoryTemplateStorage creates a new, empty InMemoryTemplateStorage
+func NewInMemoryTemplateStorage() definitions.TemplateStorage {
+	return &InMemoryTemplateStorage{
+		latestVersions: make(map[string]int),
+		templates:      make(map[string]map[int]dto.Template),
+	}
+}
+
+// GetInMemoryTemplateStorage returns a singleton instance of InMemoryTemplateStorage
+func GetInMemoryTemplateStorage() definitions.TemplateStorage {
+	inMemoryTemplateStorageOnce.Do(func() {
+		inMemoryTemplateStorage = NewInMemoryTemplateStorage()
+	})
+
+	return inMemoryTemplateStorage
+}
+
+// Create stores the content as a new version of the template
+func (s *InMemoryTemplateStorage) Create(ctx context.Context, id string, content string) (*dto.Template, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latestVersions[id]++
+	template := dto.Template{
+		ID:        id,
+		Version:   s.latestVersions[id],
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if s.templates[id] == nil {
+		s.templates[id] = make(map[int]dto.Template)
+	}
+	s.templates[id][template.Version] = template
+
+	return &template, nil
+}
+
+// Get returns the given version of the template, or the latest one if version is nil
+func (s *InMemoryTemplateStorage) Get(ctx context.Context, id string, version *int) (*dto.Template, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Resolve the latest version if none was requested
+	requestedVersion := s.latestVersions[id]
+	if version != nil {
+		requestedVersion = *version
+	}
+
+	template, exists := s.templates[id][requestedVersion]
+	if !exists {
+		return nil, nil
+	}
+
+	return &template, nil
+}
+
+// List returns the IDs of all the stored templates sorted alphabetically
+func (s *InMemoryTemplateStorage) List(ctx context.Context) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ids := make([]string, 0, len(s.templates))
+	for id := range s.templates {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Delete removes all the versions of the template, keeping its latest version
+func (s *InMemoryTemplateStorage) Delete(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.templates, id)
+	return nil
+}
@@ -0,0 +1,213 @@
+package implementations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/dto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// REDIS_TEMPLATE_IDS_KEY is the set holding the IDs of the stored templates
+	REDIS_TEMPLATE_IDS_KEY = "template:ids"
+	// REDIS_TEMPLATE_KEY_PREFIX namespaces the template keys
+	REDIS_TEMPLATE_KEY_PREFIX = "template:"
+	// REDIS_TEMPLATE_MAX_TRANSACTION_ATTEMPTS is how many times a transaction is retried while other clients change the template
+	REDIS_TEMPLATE_MAX_TRANSACTION_ATTEMPTS = 10
+)
+
+// redisTemplate is the serialized form of a template version
+type redisTemplate struct {
+	ID        string    `json:"id"`
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RedisTemplateStorage implements the TemplateStorage interface for Redis.
+// Each version is stored under its own key, and a counter per template keeps the latest version.
+// The counter is kept when a template is deleted so versions are never reused.
+// The keys of a template share a hash tag, so they are written in the same transaction in Redis Cluster.
+type RedisTemplateStorage struct {
+	client redis.UniversalClient
+}
+
+var (
+	redisTemplateStorage     *RedisTemplateStorage
+	redisTemplateStorageOnce sync.Once
+)
+
+// GetRedisTemplateStorage returns a singleton instance of RedisTemplateStorage
+func GetRedisTemplateStorage() definitions.TemplateStorage {
+	redisTemplateStorageOnce.Do(func() {
+		redisTemplateStorage = &RedisTemplateStorage{
+			client: sharedImplementations.GetRedisClient(),
+		}
+	})
+
+	return redisTemplateStorage
+}
+
+// templateKeyPrefix returns the prefix of the keys of the template, whose hash tag keeps them in the same cluster slot
+func templateKeyPrefix(id string) string {
+	return REDIS_TEMPLATE_KEY_PREFIX + "{" + id + "}:"
+}
+
+// latestVersionKey returns the key of the counter holding the latest version of the template
+func latestVersionKey(id string) string {
+	return templateKeyPrefix(id) + "latest"
+}
+
+// versionKey returns the key holding the given version of the template
+func versionKey(id string, version int) string {
+	return templateKeyPrefix(id) + strconv.Itoa(version)
+}
+
+// watch runs the transaction watching the latest version of the template, retrying it while other clients change it
+func (r *RedisTemplateStorage) watch(ctx context.Context, id string, transaction func(tx *redis.Tx) error) error {
+	for range REDIS_TEMPLATE_MAX_TRANSACTION_ATTEMPTS {
+		err := r.client.Watch(ctx, transaction, latestVersionKey(id))
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+
+	return redis.TxFailedErr
+}
+
+// Create stores the content as a new version of the template.
+// The version and the latest version counter are written in the same transaction, so the latest version always exists.
+func (r *RedisTemplateStorage) Create(ctx context.Context, id string, content string) (*dto.Template, error) {
+	var template redisTemplate
+	err := r.watch(ctx, id, func(tx *redis.Tx) error {
+		latestVersion, err := tx.Get(ctx, latestVersionKey(id)).Int()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("error getting latest template version: %w", err)
+		}
+
+		template = redisTemplate{
+			ID:        id,
+			Version:   latestVersion + 1,
+			Content:   content,
+			CreatedAt: time.Now().UTC(),
+		}
+		serializedTemplate, err := json.Marshal(template)
+		if err != nil {
+			return fmt.Errorf("error serializing template: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, versionKey(id, template.Version), serializedTemplate, 0)
+			pipe.Set(ctx, latestVersionKey(id), template.Version, 0)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error storing template: %w", err)
+	}
+
+	// Register the template ID once it is stored, as the set lives in another cluster slot.
+	// If the ID cannot be registered, creating the template again does it.
+	if err := r.client.SAdd(ctx, REDIS_TEMPLATE_IDS_KEY, id).Err(); err != nil {
+		return nil, fmt.Errorf("error registering template ID: %w", err)
+	}
+
+	return template.toDTO(), nil
+}
+
+// Get returns the given version of the template, or the latest one if version is nil
+func (r *RedisTemplateStorage) Get(ctx context.Context, id string, version *int) (*dto.Template, error) {
+	// Resolve the latest version if none was requested
+	if version == nil {
+		latestVersion, err := r.client.Get(ctx, latestVersionKey(id)).Int()
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting latest template version: %w", err)
+		}
+
+		version = &latestVersion
+	}
+
+	serializedTemplate, err := r.client.Get(ctx, versionKey(id, *version)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting template: %w", err)
+	}
+
+	var template redisTemplate
+	if err := json.Unmarshal(serializedTemplate, &template); err != nil {
+		return nil, fmt.Errorf("error deserializing template: %w", err)
+	}
+
+	return template.toDTO(), nil
+}
+
+// List returns the IDs of all the stored templates sorted alphabetically
+func (r *RedisTemplateStorage) List(ctx context.Context) ([]string, error) {
+	ids, err := r.client.SMembers(ctx, REDIS_TEMPLATE_IDS_KEY).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Delete removes all the versions of the template in the same transaction, keeping its version counter.
+// The template ID is unregistered first, as the set lives in another cluster slot, so a template
+// is never listed without its versions. If deleting them fails, deleting the template again does it.
+func (r *RedisTemplateStorage) Delete(ctx context.Context, id string) error {
+	if err := r.client.SRem(ctx, REDIS_TEMPLATE_IDS_KEY, id).Err(); err != nil {
+		return fmt.Errorf("error unregistering template ID: %w", err)
+	}
+
+	err := r.watch(ctx, id, func(tx *redis.Tx) error {
+		latestVersion, err := tx.Get(ctx, latestVersionKey(id)).Int()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error getting latest template version: %w", err)
+		}
+
+		keys := make([]string, 0, latestVersion)
+		for version := 1; version <= latestVersion; version++ {
+			keys = append(keys, versionKey(id, version))
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, keys...)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting template versions: %w", err)
+	}
+
+	return nil
+}
+
+// toDTO converts the serialized template to its domain representation
+func (t redisTemplate) toDTO() *dto.Template {
+	return &dto.Template{
+		ID:        t.ID,
+		Version:   t.Version,
+		Content:   t.Content,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package implementations
+
+import (
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	"github.com/PChaparro/serpentarius/internal/modules/template/domain/definitions"
+)
+
+// GetTemplateStorage returns the TemplateStorage implementation selected by the TEMPLATE_STORAGE_DRIVER environment variable
+func GetTemplateStorage() definitions.TemplateStorage {
+	templateStorageDriver := sharedInfrastructure.GetEnvironment().TemplateStorageDriver
+
+	switch templateStorageDriver {
+	case sharedInfrastructure.TEMPLATE_STORAGE_DRIVER_REDIS:
+		return GetRedisTemplateStorage()
+	case sharedInfrastructure.TEMPLATE_STORAGE_DRIVER_MEMORY:
+		return GetInMemoryTemplateStorage()
+	default:
+		panic("Unknown template storage driver: " + templateStorageDriver)
+	}
+}
@@ -0,0 +1,406 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gcsStorage "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
+// assertCloudStorageBackend uploads a file to the backend, checks it exists with its content type, copies it and deletes it
+func assertCloudStorageBackend(
+	t *testing.T,
+	storage sharedDefinitions.CloudStorage,
+	server *testUtilities.FakeStorageServer,
+) {
+	exists, err := storage.FileExists(context.Background(), sharedDefinitions.FileExistsRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+	})
+	assert.NoError(t, err, "Checking a missing file should succeed")
+	assert.False(t, exists, "File should not exist before the upload")
+
+	err = storage.UploadFile(context.Background(), sharedDefinitions.UploadFileRequest{
+		FileReader:  strings.NewReader("%PDF-1.7"),
+		FileFolder:  "reports",
+		FilePath:    "2025/monthly.pdf",
+		FileOptions: sharedDefinitions.FileOptions{ContentType: "application/pdf"},
+	})
+	assert.NoError(t, err, "Upload should succeed")
+
+	downloadURL, err := storage.GetDownloadURL(context.Background(), sharedDefinitions.GetDownloadURLRequest{
+		FileFolder:      "reports",
+		FilePath:        "2025/monthly.pdf",
+		Mode:            sharedDefinitions.DOWNLOAD_URL_MODE_PUBLIC,
+		PublicURLPrefix: "https://cdn.example.com",
+	})
+	assert.NoError(t, err, "Getting the public URL should succeed")
+	assert.Equal(t, "https://cdn.example.com/reports/2025/monthly.pdf", downloadURL.URL, "URL should be built from the public URL prefix")
+	assert.Nil(t, downloadURL.ExpiresAt, "Public URLs should not expire")
+
+	object, stored := server.Object("reports", "2025/monthly.pdf")
+	assert.True(t, stored, "File should be stored in the server")
+	assert.Equal(t, "%PDF-1.7", string(object.Content), "Stored content should match the uploaded one")
+	assert.Equal(t, "application/pdf", object.ContentType, "Stored content type should match the uploaded one")
+
+	exists, err = storage.FileExists(context.Background(), sharedDefinitions.FileExistsRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+	})
+	assert.NoError(t, err, "Checking an uploaded file should succeed")
+	assert.True(t, exists, "File should exist after the upload")
+
+	err = storage.CopyFile(context.Background(), sharedDefinitions.CopyFileRequest{
+		SourceFileFolder: "reports",
+		SourceFilePath:   "2025/monthly.pdf",
+		FileFolder:       "archive",
+		FilePath:         "2025/monthly copy.pdf",
+		FileOptions:      sharedDefinitions.FileOptions{ContentType: "application/pdf"},
+	})
+	assert.NoError(t, err, "Copy should succeed")
+
+	copied, stored := server.Object("archive", "2025/monthly copy.pdf")
+	assert.True(t, stored, "Copy should be stored in the server")
+	assert.Equal(t, "%PDF-1.7", string(copied.Content), "Copied content should match the source one")
+	assert.Equal(t, "application/pdf", copied.ContentType, "Copied content type should match the requested one")
+
+	err = storage.DeleteFile(context.Background(), sharedDefinitions.DeleteFileRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+	})
+	assert.NoError(t, err, "Deleting an uploaded file should succeed")
+
+	_, stored = server.Object("reports", "2025/monthly.pdf")
+	assert.False(t, stored, "File should be removed from the server")
+
+	err = storage.DeleteFile(context.Background(), sharedDefinitions.DeleteFileRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+	})
+	assert.NoError(t, err, "Deleting a missing file should succeed")
+}
+
+// newFakeGCSClient creates a Cloud Storage client sending the requests to the fake server
+func newFakeGCSClient(t *testing.T, server *testUtilities.FakeStorageServer) *gcsStorage.Client {
+	client, err := gcsStorage.NewClient(
+		context.Background(),
+		option.WithEndpoint(server.Server.URL+"/storage/v1/"),
+		option.WithHTTPClient(server.Server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("Could not create Cloud Storage client: %v", err)
+	}
+
+	return client
+}
+
+// TestGCSCloudStorage tests files are uploaded, checked and deleted through the Cloud Storage JSON API
+func TestGCSCloudStorage(t *testing.T) {
+	server := testUtilities.NewFakeGCSServer()
+	defer server.Close()
+
+	storage := sharedImplementations.NewGCSCloudStorage(newFakeGCSClient(t, server), nil)
+	assertCloudStorageBackend(t, storage, server)
+}
+
+// assertPresignedDownloadURL presigns a URL and checks it expires as requested and contains the signature query parameters
+func assertPresignedDownloadURL(
+	t *testing.T,
+	storage sharedDefinitions.CloudStorage,
+	expiration time.Duration,
+	expectedExpiration time.Duration,
+	signatureQueryParams ...string,
+) {
+	downloadURL, err := storage.GetDownloadURL(context.Background(), sharedDefinitions.GetDownloadURLRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+		Mode:       sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED,
+		Expiration: expiration,
+	})
+	if !assert.NoError(t, err, "Presigning should succeed") {
+		return
+	}
+
+	if assert.NotNil(t, downloadURL.ExpiresAt, "Presigned URLs should report their expiration") {
+		assert.WithinDuration(t, time.Now().Add(expectedExpiration), *downloadURL.ExpiresAt, 5*time.Second, "Expiration should match the signed one")
+	}
+
+	parsedURL, err := url.Parse(downloadURL.URL)
+	assert.NoError(t, err, "Presigned URL should be valid")
+	assert.Contains(t, parsedURL.Path, "reports/2025/monthly.pdf", "Presigned URL should point to the object")
+	for _, param := range signatureQueryParams {
+		assert.NotEmptyf(t, parsedURL.Query().Get(param), "Presigned URL should contain the %s query parameter", param)
+	}
+}
+
+// TestS3CloudStorage_PresignedURL tests S3 URLs are presigned locally, capped to the SigV4 maximum lifetime
+func TestS3CloudStorage_PresignedURL(t *testing.T) {
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("https://s3.example.com"),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	storage := sharedImplementations.NewS3CloudStorage(client, manager.MinUploadPartSize, 1)
+
+	assertPresignedDownloadURL(t, storage, time.Hour, time.Hour, "X-Amz-Signature", "X-Amz-Expires")
+	assertPresignedDownloadURL(t, storage, 30*24*time.Hour, sharedImplementations.S3_MAX_PRESIGN_EXPIRATION, "X-Amz-Signature")
+}
+
+// TestGCSCloudStorage_PresignedURL tests Cloud Storage URLs are signed with the service account key
+func TestGCSCloudStorage_PresignedURL(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Could not generate RSA key: %v", err)
+	}
+	encodedKey, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	serviceAccountKey, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "renderer@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedKey})),
+	})
+
+	signingKey, err := google.JWTConfigFromJSON(serviceAccountKey)
+	if err != nil {
+		t.Fatalf("Could not load service account key: %v", err)
+	}
+	client, err := gcsStorage.NewClient(context.Background(), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("Could not create Cloud Storage client: %v", err)
+	}
+
+	unsignedStorage := sharedImplementations.NewGCSCloudStorage(client, nil)
+	_, err = unsignedStorage.GetDownloadURL(context.Background(), sharedDefinitions.GetDownloadURLRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+		Mode:       sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED,
+		Expiration: time.Hour,
+	})
+	assert.Error(t, err, "Presigning should fail without a service account key")
+
+	storage := sharedImplementations.NewGCSCloudStorage(client, signingKey)
+	assertPresignedDownloadURL(t, storage, time.Hour, time.Hour, "X-Goog-Signature", "X-Goog-Credential")
+	assertPresignedDownloadURL(t, storage, 30*24*time.Hour, sharedImplementations.GCS_MAX_SIGNED_URL_EXPIRATION, "X-Goog-Signature")
+}
+
+// TestAzureBlobCloudStorage_PresignedURL tests blob URLs get a read-only service SAS
+func TestAzureBlobCloudStorage_PresignedURL(t *testing.T) {
+	server := testUtilities.NewFakeAzureBlobServer()
+	defer server.Close()
+
+	client, err := azblob.NewClientFromConnectionString(server.FakeAzureConnectionString(), nil)
+	if err != nil {
+		t.Fatalf("Could not create Azure Blob client: %v", err)
+	}
+
+	storage := sharedImplementations.NewAzureBlobCloudStorage(client)
+	assertPresignedDownloadURL(t, storage, time.Hour, time.Hour, "sig", "se", "sp")
+}
+
+// TestS3CloudStorage tests files are uploaded, checked and deleted through the S3 API
+func TestS3CloudStorage(t *testing.T) {
+	server := testUtilities.NewFakeS3Server()
+	defer server.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.Server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	storage := sharedImplementations.NewS3CloudStorage(client, manager.MinUploadPartSize, 1)
+	assertCloudStorageBackend(t, storage, server)
+}
+
+// TestAzureBlobCloudStorage tests files are uploaded, checked and deleted through the Blob service API
+func TestAzureBlobCloudStorage(t *testing.T) {
+	server := testUtilities.NewFakeAzureBlobServer()
+	defer server.Close()
+
+	client, err := azblob.NewClientFromConnectionString(server.FakeAzureConnectionString(), nil)
+	if err != nil {
+		t.Fatalf("Could not create Azure Blob client: %v", err)
+	}
+
+	storage := sharedImplementations.NewAzureBlobCloudStorage(client)
+	assertCloudStorageBackend(t, storage, server)
+}
+
+// newObjectOptionsUploadRequest returns an upload request setting every object option
+func newObjectOptionsUploadRequest(storageClass string) sharedDefinitions.UploadFileRequest {
+	return sharedDefinitions.UploadFileRequest{
+		FileReader: strings.NewReader("%PDF-1.7"),
+		FileFolder: "reports",
+		FilePath:   "2025/monthly.pdf",
+		FileOptions: sharedDefinitions.FileOptions{
+			ContentType:          "application/pdf",
+			Metadata:             map[string]string{"tenant": "acme"},
+			Tags:                 map[string]string{"document-type": "invoice"},
+			CacheControl:         "private, max-age=3600",
+			ContentDisposition:   `attachment; filename="monthly.pdf"`,
+			ServerSideEncryption: sharedDefinitions.SERVER_SIDE_ENCRYPTION_KMS,
+			KMSKeyID:             "alias/reports",
+			StorageClass:         storageClass,
+		},
+	}
+}
+
+// TestS3CloudStorage_ObjectOptions tests the metadata, tags, headers, encryption and storage class reach S3
+func TestS3CloudStorage_ObjectOptions(t *testing.T) {
+	server := testUtilities.NewFakeS3Server()
+	defer server.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.Server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	storage := sharedImplementations.NewS3CloudStorage(client, manager.MinUploadPartSize, 1)
+
+	err := storage.UploadFile(context.Background(), newObjectOptionsUploadRequest("STANDARD_IA"))
+	assert.NoError(t, err, "Upload should succeed")
+
+	object, stored := server.Object("reports", "2025/monthly.pdf")
+	if !assert.True(t, stored, "File should be stored in the server") {
+		return
+	}
+	assert.Equal(t, "acme", object.Headers.Get("X-Amz-Meta-Tenant"), "Metadata should be sent")
+	assert.Equal(t, "document-type=invoice", object.Headers.Get("X-Amz-Tagging"), "Tags should be sent")
+	assert.Equal(t, "private, max-age=3600", object.Headers.Get("Cache-Control"), "Cache-Control should be sent")
+	assert.Equal(t, `attachment; filename="monthly.pdf"`, object.Headers.Get("Content-Disposition"), "Content-Disposition should be sent")
+	assert.Equal(t, "aws:kms", object.Headers.Get("X-Amz-Server-Side-Encryption"), "Server-side encryption should be sent")
+	assert.Equal(t, "alias/reports", object.Headers.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"), "KMS key ID should be sent")
+	assert.Equal(t, "STANDARD_IA", object.Headers.Get("X-Amz-Storage-Class"), "Storage class should be sent")
+}
+
+// TestAzureBlobCloudStorage_ObjectOptions tests the metadata, tags, headers and access tier reach Azure Blob Storage
+func TestAzureBlobCloudStorage_ObjectOptions(t *testing.T) {
+	server := testUtilities.NewFakeAzureBlobServer()
+	defer server.Close()
+
+	client, err := azblob.NewClientFromConnectionString(server.FakeAzureConnectionString(), nil)
+	if err != nil {
+		t.Fatalf("Could not create Azure Blob client: %v", err)
+	}
+	storage := sharedImplementations.NewAzureBlobCloudStorage(client)
+
+	err = storage.UploadFile(context.Background(), newObjectOptionsUploadRequest("Cool"))
+	assert.NoError(t, err, "Upload should succeed")
+
+	object, stored := server.Object("reports", "2025/monthly.pdf")
+	if !assert.True(t, stored, "File should be stored in the server") {
+		return
+	}
+	assert.Equal(t, "acme", object.Headers.Get("X-Ms-Meta-Tenant"), "Metadata should be sent")
+	assert.Equal(t, "document-type=invoice", object.Headers.Get("X-Ms-Tags"), "Tags should be sent")
+	assert.Equal(t, "private, max-age=3600", object.Headers.Get("X-Ms-Blob-Cache-Control"), "Cache-Control should be sent")
+	assert.Equal(t, `attachment; filename="monthly.pdf"`, object.Headers.Get("X-Ms-Blob-Content-Disposition"), "Content-Disposition should be sent")
+	assert.Equal(t, "Cool", object.Headers.Get("X-Ms-Access-Tier"), "Access tier should be sent")
+}
+
+// assertGCSObjectOptions asserts the object resource holds the metadata, headers and storage class of the request
+func assertGCSObjectOptions(t *testing.T, object testUtilities.FakeStoredObject) {
+	assert.Equal(t, "application/pdf", object.ContentType, "Content type should be sent")
+	assert.Equal(t, map[string]any{"tenant": "acme"}, object.Resource["metadata"], "Metadata should be sent")
+	assert.Equal(t, "private, max-age=3600", object.Resource["cacheControl"], "Cache-Control should be sent")
+	assert.Equal(t, `attachment; filename="monthly.pdf"`, object.Resource["contentDisposition"], "Content-Disposition should be sent")
+	assert.Equal(t, "NEARLINE", object.Resource["storageClass"], "Storage class should be sent")
+}
+
+// TestGCSCloudStorage_ObjectOptions tests the metadata, headers and storage class reach Cloud Storage on uploads and copies
+func TestGCSCloudStorage_ObjectOptions(t *testing.T) {
+	server := testUtilities.NewFakeGCSServer()
+	defer server.Close()
+
+	storage := sharedImplementations.NewGCSCloudStorage(newFakeGCSClient(t, server), nil)
+	uploadRequest := newObjectOptionsUploadRequest("NEARLINE")
+
+	err := storage.UploadFile(context.Background(), uploadRequest)
+	assert.NoError(t, err, "Upload should succeed")
+
+	object, stored := server.Object("reports", "2025/monthly.pdf")
+	if assert.True(t, stored, "File should be stored in the server") {
+		assert.Equal(t, "%PDF-1.7", string(object.Content), "Stored content should match the uploaded one")
+		assertGCSObjectOptions(t, object)
+	}
+
+	err = storage.CopyFile(context.Background(), sharedDefinitions.CopyFileRequest{
+		SourceFileFolder: "reports",
+		SourceFilePath:   "2025/monthly.pdf",
+		FileFolder:       "archive",
+		FilePath:         "2025/monthly.pdf",
+		FileOptions:      uploadRequest.FileOptions,
+	})
+	assert.NoError(t, err, "Copy should succeed")
+	assert.Equal(t, 2, server.RewriteRequests, "Copy should continue the rewrite until it is done")
+
+	copied, stored := server.Object("archive", "2025/monthly.pdf")
+	if assert.True(t, stored, "Copy should be stored in the server") {
+		assert.Equal(t, "%PDF-1.7", string(copied.Content), "Copied content should match the source one")
+		assertGCSObjectOptions(t, copied)
+	}
+}
+
+// TestS3CloudStorage_MultipartUpload tests files larger than the part size are streamed in several parts
+func TestS3CloudStorage_MultipartUpload(t *testing.T) {
+	server := testUtilities.NewFakeS3Server()
+	defer server.Close()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.Server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	storage := sharedImplementations.NewS3CloudStorage(client, manager.MinUploadPartSize, 2)
+
+	// Write the file to disk, as the generator does with the merged PDF
+	content := bytes.Repeat([]byte("%PDF-1.7 "), int(manager.MinUploadPartSize)*2/9+1024)
+	filePath := filepath.Join(t.TempDir(), "large.pdf")
+	if err := os.WriteFile(filePath, content, 0o600); err != nil {
+		t.Fatalf("Could not write file: %v", err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatalf("Could not open file: %v", err)
+	}
+	defer file.Close()
+
+	err = storage.UploadFile(context.Background(), sharedDefinitions.UploadFileRequest{
+		FileReader:  file,
+		FileFolder:  "reports",
+		FilePath:    "large.pdf",
+		FileOptions: sharedDefinitions.FileOptions{ContentType: "application/pdf"},
+	})
+	assert.NoError(t, err, "Upload should succeed")
+
+	assert.Equal(t, []int{3}, server.MultipartUploadParts, "File should be uploaded in three parts")
+	object, stored := server.Object("reports", "large.pdf")
+	if assert.True(t, stored, "File should be stored in the server") {
+		assert.True(t, bytes.Equal(content, object.Content), "Stored content should match the uploaded one")
+		assert.Equal(t, "application/pdf", object.ContentType, "Stored content type should match the uploaded one")
+	}
+}
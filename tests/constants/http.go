@@ -1,5 +1,12 @@
 package constants
 
 const (
-	GENERATE_PDF_RETURNING_URL_ENDPOINT = "/api/v1/pdf/url"
+	GENERATE_PDF_RETURNING_URL_ENDPOINT    = "/api/v1/pdf/url"
+	GENERATE_PDF_RETURNING_STREAM_ENDPOINT = "/api/v1/pdf/stream"
+	PDF_GENERATION_JOBS_ENDPOINT           = "/api/v1/pdf/jobs"
+	TEMPLATES_ENDPOINT                     = "/api/v1/templates"
+	ADMIN_POOL_ENDPOINT                    = "/api/v1/admin/pool"
+	HEALTHZ_ENDPOINT                       = "/healthz"
+	READYZ_ENDPOINT                        = "/readyz"
+	METRICS_ENDPOINT                       = "/metrics"
 )
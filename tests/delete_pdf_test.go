@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/controllers"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/http/requests"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// deletePDFTestDependencies are the in-memory dependencies shared by the generation and deletion use cases
+type deletePDFTestDependencies struct {
+	cloudStorage      *testUtilities.FakeCloudStorage
+	cacheStorage      *testUtilities.FakeURLCacheStorage
+	expirationStorage *testUtilities.FakeFileExpirationStorage
+	copyStorage       sharedDefinitions.DeduplicatedFileStorage
+	renderCache       *testUtilities.FakeRenderCache
+	generator         *testUtilities.FakePDFGenerator
+}
+
+// newDeletePDFTestDependencies returns empty in-memory dependencies
+func newDeletePDFTestDependencies() deletePDFTestDependencies {
+	return deletePDFTestDependencies{
+		cloudStorage:      testUtilities.NewFakeCloudStorage(),
+		cacheStorage:      testUtilities.NewFakeURLCacheStorage(),
+		expirationStorage: testUtilities.NewFakeFileExpirationStorage(),
+		copyStorage:       sharedImplementations.NewInMemoryDeduplicatedFileStorage(),
+		renderCache:       testUtilities.NewFakeRenderCache(),
+		generator:         &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")},
+	}
+}
+
+// generate uploads and caches a PDF with the given content, returning the generation result
+func (d deletePDFTestDependencies) generate(t *testing.T, bodyHTML string) *dto.PDFURL {
+	return d.generateFile(t, bodyHTML, "revoked.pdf", false)
+}
+
+// generateFile uploads and caches a PDF with the given content under the file name, returning the generation result
+func (d deletePDFTestDependencies) generateFile(t *testing.T, bodyHTML string, fileName string, deduplicate bool) *dto.PDFURL {
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(d.generator),
+		testUtilities.WithCloudStorage(d.cloudStorage),
+		testUtilities.WithURLCacheStorage(d.cacheStorage),
+		testUtilities.WithRenderCache(d.renderCache),
+		testUtilities.WithFileExpirationStorage(d.expirationStorage),
+		testUtilities.WithDeduplicatedFileStorage(d.copyStorage),
+		testUtilities.WithDefaultRetention(3600),
+	)
+
+	result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithBodyHTML(bodyHTML),
+		testUtilities.WithFileName(fileName),
+		testUtilities.WithDeduplication(deduplicate),
+	))
+	assert.NoError(t, err, "Generation should succeed")
+	return result
+}
+
+// deleteUseCase returns the deletion use case with the given dependencies
+func (d deletePDFTestDependencies) deleteUseCase() use_cases.DeletePDFUseCase {
+	return use_cases.DeletePDFUseCase{
+		CloudStorage:            d.cloudStorage,
+		URLCacheStorage:         d.cacheStorage,
+		FileExpirationStorage:   d.expirationStorage,
+		DeduplicatedFileStorage: d.copyStorage,
+		RenderCache:             d.renderCache,
+	}
+}
+
+// deduplicatedFiles returns the stored deduplicated PDFs
+func (d deletePDFTestDependencies) deduplicatedFiles() []string {
+	var files []string
+	for file := range d.cloudStorage.Files {
+		if strings.HasPrefix(file, "reports/"+use_cases.DEDUPLICATED_FILES_PREFIX) {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// newDeletePDFTestRouter returns a router serving the delete endpoint with the given dependencies
+func newDeletePDFTestRouter(d deletePDFTestDependencies) *gin.Engine {
+	router := gin.New()
+	router.Use(sharedMiddlewares.ErrorHandlerMiddleware())
+	controller := &controllers.DeletePDFController{
+		UseCase: d.deleteUseCase(),
+	}
+	router.DELETE(
+		"/api/v1/pdf",
+		sharedMiddlewares.RequestValidationMiddleware(requests.DeletePDFRequest{}),
+		controller.Handle,
+	)
+
+	return router
+}
+
+// TestDeletePDF_ByFileName tests the file is deleted and every cached URL pointing to it is purged
+func TestDeletePDF_ByFileName(t *testing.T) {
+	dependencies := newDeletePDFTestDependencies()
+	dependencies.generate(t, "<p>First</p>")
+	dependencies.generate(t, "<p>Second</p>")
+	assert.Len(t, dependencies.cacheStorage.Entries, 2, "Both requests should be cached")
+
+	w := testUtilities.SendToAPI(testUtilities.APIRequest{
+		Router: newDeletePDFTestRouter(dependencies),
+		Method: http.MethodDelete,
+		URL:    "/api/v1/pdf",
+		Body:   `{"directory": "reports", "fileName": "revoked.pdf"}`,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 (got %d)", w.Code)
+
+	response, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+	assert.Equal(t, float64(2), response.(map[string]any)["purgedCacheEntries"], "Should purge both cached URLs")
+
+	assert.NotContains(t, dependencies.cloudStorage.Files, "reports/revoked.pdf", "File should be deleted")
+	assert.Empty(t, dependencies.cacheStorage.Entries, "Cached URLs should be purged")
+	assert.Empty(t, dependencies.expirationStorage.Expirations, "File expiration should be unscheduled")
+}
+
+// TestDeletePDF_ByHash tests the file is resolved from the hash returned when it was generated
+func TestDeletePDF_ByHash(t *testing.T) {
+	dependencies := newDeletePDFTestDependencies()
+	result := dependencies.generate(t, "<p>Hashed</p>")
+	assert.NotEmpty(t, result.Hash, "Generation should return the hash")
+
+	body, _ := json.Marshal(map[string]string{"hash": result.Hash})
+	w := testUtilities.SendToAPI(testUtilities.APIRequest{
+		Router: newDeletePDFTestRouter(dependencies),
+		Method: http.MethodDelete,
+		URL:    "/api/v1/pdf",
+		Body:   string(body),
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 (got %d)", w.Code)
+
+	response, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+	assert.Equal(t, "reports", response.(map[string]any)["directory"], "Should return the directory of the file")
+	assert.Equal(t, "revoked.pdf", response.(map[string]any)["fileName"], "Should return the name of the file")
+
+	assert.NotContains(t, dependencies.cloudStorage.Files, "reports/revoked.pdf", "File should be deleted")
+	assert.Empty(t, dependencies.cacheStorage.Entries, "Cached URL should be purged")
+}
+
+// TestDeletePDF_UnknownHash tests a hash that is not cached cannot be resolved
+func TestDeletePDF_UnknownHash(t *testing.T) {
+	w := testUtilities.SendToAPI(testUtilities.APIRequest{
+		Router: newDeletePDFTestRouter(newDeletePDFTestDependencies()),
+		Method: http.MethodDelete,
+		URL:    "/api/v1/pdf",
+		Body:   `{"hash": "unknown"}`,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusNotFound, w.Code, "Should return 404 (got %d)", w.Code)
+}
+
+// TestDeletePDF_InvalidRequest tests the file must be identified either by location or by hash
+func TestDeletePDF_InvalidRequest(t *testing.T) {
+	router := newDeletePDFTestRouter(newDeletePDFTestDependencies())
+
+	for _, body := range []string{
+		`{}`,
+		`{"directory": "reports"}`,
+		`{"directory": "reports", "fileName": "revoked.pdf", "hash": "abc"}`,
+	} {
+		w := testUtilities.SendToAPI(testUtilities.APIRequest{
+			Router: router,
+			Method: http.MethodDelete,
+			URL:    "/api/v1/pdf",
+			Body:   body,
+			Auth:   testUtilities.AuthOptions{Skip: true},
+		})
+		assert.Equalf(t, http.StatusBadRequest, w.Code, "Should return 400 for %s (got %d)", body, w.Code)
+	}
+}
+
+// TestDeletePDF_RegeneratedAfterDeletion tests a deleted PDF is generated again instead of served from the cache
+func TestDeletePDF_RegeneratedAfterDeletion(t *testing.T) {
+	dependencies := newDeletePDFTestDependencies()
+	dependencies.generate(t, "<p>Regenerated</p>")
+
+	useCase := dependencies.deleteUseCase()
+	_, err := useCase.Execute(context.Background(), &dto.DeletePDFDTO{Directory: "reports", FileName: "revoked.pdf"})
+	assert.NoError(t, err, "Deletion should succeed")
+
+	result := dependencies.generate(t, "<p>Regenerated</p>")
+	assert.False(t, result.CacheHit, "Deleted PDF should not be served from the cache")
+	assert.Contains(t, dependencies.cloudStorage.Files, "reports/revoked.pdf", "File should be uploaded again")
+	assert.Contains(
+		t,
+		dependencies.expirationStorage.Expirations,
+		sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "revoked.pdf"},
+		"File expiration should be scheduled again",
+	)
+	assert.WithinDuration(
+		t,
+		time.Now().Add(time.Hour),
+		dependencies.expirationStorage.Expirations[sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "revoked.pdf"}],
+		2*time.Second,
+		"File should expire after the default retention",
+	)
+}
+
+// TestDeletePDF_DeduplicatedLastCopy tests the deduplicated PDF is deleted along with its last copy
+func TestDeletePDF_DeduplicatedLastCopy(t *testing.T) {
+	dependencies := newDeletePDFTestDependencies()
+	dependencies.generateFile(t, "<p>Shared</p>", "first.pdf", true)
+	dependencies.generateFile(t, "<p>Shared</p>", "second.pdf", true)
+	assert.Len(t, dependencies.deduplicatedFiles(), 1, "Both files should be copied from one deduplicated PDF")
+	assert.Len(t, dependencies.renderCache.Entries, 1, "Rendered PDF should be cached")
+
+	useCase := dependencies.deleteUseCase()
+	_, err := useCase.Execute(context.Background(), &dto.DeletePDFDTO{Directory: "reports", FileName: "first.pdf"})
+	assert.NoError(t, err, "Deleting the first copy should succeed")
+	assert.Len(t, dependencies.deduplicatedFiles(), 1, "Deduplicated PDF should be kept while a copy is left")
+
+	_, err = useCase.Execute(context.Background(), &dto.DeletePDFDTO{Directory: "reports", FileName: "second.pdf"})
+	assert.NoError(t, err, "Deleting the last copy should succeed")
+	assert.Empty(t, dependencies.deduplicatedFiles(), "Deduplicated PDF should be deleted along with its last copy")
+	assert.Empty(t, dependencies.renderCache.Entries, "Rendered PDF should be purged along with the last copy")
+	for file := range dependencies.expirationStorage.Expirations {
+		assert.NotContains(t, file.FilePath, use_cases.DEDUPLICATED_FILES_PREFIX, "Deduplicated PDF expiration should be unscheduled")
+	}
+
+	result := dependencies.generateFile(t, "<p>Shared</p>", "third.pdf", true)
+	assert.False(t, result.Deduplicated, "Identical request should not copy the deleted PDF")
+	assert.Equal(t, 2, dependencies.generator.GetCalls(), "Identical request should render the PDF again")
+}
+
+// TestDeletePDF_DeduplicatedReplacedCopy tests the deduplicated PDF is deleted once its last copy is replaced
+func TestDeletePDF_DeduplicatedReplacedCopy(t *testing.T) {
+	dependencies := newDeletePDFTestDependencies()
+	dependencies.generateFile(t, "<p>Old</p>", "report.pdf", true)
+	oldFiles := dependencies.deduplicatedFiles()
+
+	dependencies.generateFile(t, "<p>New</p>", "report.pdf", true)
+	newFiles := dependencies.deduplicatedFiles()
+	if assert.Len(t, newFiles, 1, "Only the deduplicated PDF of the new content should be kept") {
+		assert.NotEqual(t, oldFiles, newFiles, "Deduplicated PDF of the old content should be deleted")
+	}
+}
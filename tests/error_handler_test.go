@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newErrorHandlerTestRouter returns a router whose only route fails with the error of the request context
+func newErrorHandlerTestRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(sharedMiddlewares.ErrorHandlerMiddleware())
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		_ = c.Error(c.Request.Context().Err())
+	})
+
+	return router
+}
+
+// TestErrorHandler_ClientCanceled tests requests closed by the client get no body nor an internal error status
+func TestErrorHandler_ClientCanceled(t *testing.T) {
+	router := newErrorHandlerTestRouter()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, sharedMiddlewares.HTTP_STATUS_CLIENT_CLOSED_REQUEST, w.Code, "Canceled request should get the client closed request status")
+	assert.Empty(t, w.Body.String(), "Canceled request should get no body")
+}
+
+// TestErrorHandler_DeadlineExceeded tests requests that took too long get a gateway timeout
+func TestErrorHandler_DeadlineExceeded(t *testing.T) {
+	router := newErrorHandlerTestRouter()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	request := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code, "Request past its deadline should get a gateway timeout")
+}
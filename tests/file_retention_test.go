@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	storageUseCases "github.com/PChaparro/serpentarius/internal/modules/storage/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/infrastructure/http/controllers"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostPDFUrl_FileRetention tests uploads schedule the deletion of the file and the URL is not cached longer than the file lives
+func TestPostPDFUrl_FileRetention(t *testing.T) {
+	cacheStorage := testUtilities.NewFakeURLCacheStorage()
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithURLCacheStorage(cacheStorage),
+		testUtilities.WithFileExpirationStorage(expirationStorage),
+		testUtilities.WithDefaultRetention(600),
+	)
+	file := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "retention.pdf"}
+
+	// The default retention applies when the request does not set one
+	_, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("retention.pdf"),
+		testUtilities.WithExpiration(3600),
+	))
+	assert.NoError(t, err, "Request should succeed")
+	if assert.Contains(t, expirationStorage.Expirations, file, "File deletion should be scheduled") {
+		assert.WithinDuration(t, time.Now().Add(600*time.Second), expirationStorage.Expirations[file], 2*time.Second, "File should expire after the default retention")
+	}
+	for _, cacheExpiration := range cacheStorage.Expirations {
+		assert.LessOrEqual(t, cacheExpiration, int64(600), "Cache entry should not outlive the file")
+	}
+
+	// A zero retention keeps the file forever, even if it replaces an expiring one
+	clear(cacheStorage.Entries)
+	_, err = useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("retention.pdf"),
+		testUtilities.WithExpiration(3600),
+		testUtilities.WithRetention(0),
+	))
+	assert.NoError(t, err, "Request should succeed")
+	assert.NotContains(t, expirationStorage.Expirations, file, "File deletion should be unscheduled")
+}
+
+// TestPostPDFUrl_FileRetentionDisabled tests the expirations are not touched when the files are kept forever by default
+func TestPostPDFUrl_FileRetentionDisabled(t *testing.T) {
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	useCase := testUtilities.NewTestURLUseCase(testUtilities.WithFileExpirationStorage(expirationStorage))
+	file := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "retention.pdf"}
+	expiresAt := time.Now().Add(time.Hour)
+	_ = expirationStorage.Schedule(context.Background(), file, expiresAt)
+
+	_, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName("retention.pdf")))
+	assert.NoError(t, err, "Request should succeed")
+	assert.Equal(t, expiresAt, expirationStorage.Expirations[file], "Expiration should not be touched without a retention")
+}
+
+// TestPostPDFUrl_FileExpirationStorageFailure tests the PDFs are returned when their expiration cannot be recorded
+func TestPostPDFUrl_FileExpirationStorageFailure(t *testing.T) {
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	expirationStorage.Error = errors.New("connection refused")
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithFileExpirationStorage(expirationStorage),
+		testUtilities.WithDefaultRetention(600),
+	)
+
+	result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName("retention.pdf")))
+	if assert.NoError(t, err, "Request should succeed without the expiration storage") {
+		assert.Equal(t, "https://cdn.example.com/reports/retention.pdf", result.URL, "URL of the stored PDF should be returned")
+	}
+}
+
+// TestDeleteExpiredFiles tests the expired files are deleted and the others are kept
+func TestDeleteExpiredFiles(t *testing.T) {
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	cloudStorage.Files["reports/expired-1.pdf"] = []byte("%PDF-1.7")
+	cloudStorage.Files["reports/expired-2.pdf"] = []byte("%PDF-1.7")
+	cloudStorage.Files["reports/kept.pdf"] = []byte("%PDF-1.7")
+
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	expired := []sharedDefinitions.ExpiringFile{
+		{FileFolder: "reports", FilePath: "expired-1.pdf"},
+		{FileFolder: "reports", FilePath: "expired-2.pdf"},
+	}
+	kept := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "kept.pdf"}
+	for _, file := range expired {
+		_ = expirationStorage.Schedule(context.Background(), file, time.Now().Add(-time.Minute))
+	}
+	_ = expirationStorage.Schedule(context.Background(), kept, time.Now().Add(time.Hour))
+
+	useCase := storageUseCases.DeleteExpiredFilesUseCase{
+		CloudStorage:            cloudStorage,
+		FileExpirationStorage:   expirationStorage,
+		DeduplicatedFileStorage: sharedImplementations.NewInMemoryDeduplicatedFileStorage(),
+		BatchSize:               1,
+	}
+	result, err := useCase.Execute(context.Background())
+	assert.NoError(t, err, "Cleanup should succeed")
+	assert.Equal(t, 2, result.DeletedFiles, "Both expired files should be deleted")
+	assert.Equal(t, 0, result.FailedFiles, "No deletion should fail")
+
+	assert.NotContains(t, cloudStorage.Files, "reports/expired-1.pdf", "Expired file should be deleted")
+	assert.NotContains(t, cloudStorage.Files, "reports/expired-2.pdf", "Expired file should be deleted")
+	assert.Contains(t, cloudStorage.Files, "reports/kept.pdf", "File not expired yet should be kept")
+	assert.Contains(t, expirationStorage.Expirations, kept, "File not expired yet should stay scheduled")
+}
+
+// TestDeleteExpiredFiles_Failure tests the files that cannot be deleted are scheduled again
+func TestDeleteExpiredFiles_Failure(t *testing.T) {
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	cloudStorage.DeleteError = errors.New("storage unavailable")
+
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	file := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "expired.pdf"}
+	_ = expirationStorage.Schedule(context.Background(), file, time.Now().Add(-time.Minute))
+
+	useCase := storageUseCases.DeleteExpiredFilesUseCase{
+		CloudStorage:            cloudStorage,
+		FileExpirationStorage:   expirationStorage,
+		DeduplicatedFileStorage: sharedImplementations.NewInMemoryDeduplicatedFileStorage(),
+		BatchSize:               10,
+	}
+	result, err := useCase.Execute(context.Background())
+	assert.NoError(t, err, "Cleanup should succeed even if some deletions fail")
+	assert.Equal(t, 0, result.DeletedFiles, "No file should be deleted")
+	assert.Equal(t, 1, result.FailedFiles, "The deletion should fail")
+	assert.Contains(t, expirationStorage.Expirations, file, "File should be scheduled again to retry")
+}
+
+// TestPostStorageCleanup tests the admin endpoint deletes the expired files and reports the counts
+func TestPostStorageCleanup(t *testing.T) {
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	cloudStorage.Files["reports/expired.pdf"] = []byte("%PDF-1.7")
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	_ = expirationStorage.Schedule(
+		context.Background(),
+		sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "expired.pdf"},
+		time.Now().Add(-time.Minute),
+	)
+
+	router := gin.New()
+	router.Use(sharedMiddlewares.ErrorHandlerMiddleware())
+	controller := &controllers.DeleteExpiredFilesController{
+		UseCase: storageUseCases.DeleteExpiredFilesUseCase{
+			CloudStorage:            cloudStorage,
+			FileExpirationStorage:   expirationStorage,
+			DeduplicatedFileStorage: sharedImplementations.NewInMemoryDeduplicatedFileStorage(),
+			BatchSize:               10,
+		},
+	}
+	router.POST("/api/v1/admin/storage/cleanup", controller.Handle)
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    "/api/v1/admin/storage/cleanup",
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 (got %d)", w.Code)
+
+	response, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+	body := response.(map[string]any)
+	assert.Equal(t, float64(1), body["deletedFiles"], "Should report the deleted file")
+	assert.Equal(t, float64(0), body["failedFiles"], "Should report no failures")
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	sharedHTTP "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http"
+	testConstants "github.com/PChaparro/serpentarius/tests/constants"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostPDFStream_ValidDocument tests the API returns the PDF bytes with a valid document
+func TestPostPDFStream_ValidDocument(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	bodyBytes, err := testUtilities.ReadFileFromTestsDataDirectory("valid-stream-request.json")
+	if err != nil {
+		t.Fatalf("Could not read valid body file: %v", err)
+	}
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   string(bodyBytes),
+	})
+
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 with valid document (got %d)", w.Code)
+	assert.Equalf(t, "application/pdf", w.Header().Get("Content-Type"), "Should return a PDF content type (got %s)", w.Header().Get("Content-Type"))
+	assert.Equalf(t, `inline; filename=sales-report.pdf`, w.Header().Get("Content-Disposition"), "Should return the requested disposition (got %s)", w.Header().Get("Content-Disposition"))
+	assert.Equalf(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"), "Content-Length should match the body size (got %s)", w.Header().Get("Content-Length"))
+	assert.Truef(t, len(w.Body.Bytes()) > 4 && string(w.Body.Bytes()[:4]) == "%PDF", "Body should be a PDF document")
+}
+
+// TestPostPDFStream_InvalidDocument tests the API with an invalid document
+func TestPostPDFStream_InvalidDocument(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   `{"items": [{"bodyHTML": ""}], "config": {"disposition": "download"}}`,
+	})
+
+	// We expect a 400 Bad Request error for invalid input
+	assert.Equalf(t, http.StatusBadRequest, w.Code, "Should return 400 Bad Request with invalid document (got %d)", w.Code)
+
+	respAny, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+
+	resp, ok := respAny.(map[string]any)
+	assert.Truef(t, ok, "Response should be a JSON object (got: %T)", respAny)
+
+	errorsArr, ok := resp["errors"].([]any)
+	assert.Truef(t, ok, "Response should contain an 'errors' array (got: %v)", resp)
+	assert.Greaterf(t, len(errorsArr), 1, "'errors' array should have more than one element (got: %v)", errorsArr)
+}
+
+// TestPostPDFStream_PrivateRemoteURL tests the API refuses to render URLs pointing to private networks
+func TestPostPDFStream_PrivateRemoteURL(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   `{"items": [{"url": "http://127.0.0.1:3000/"}], "config": {"fileName": "internal.pdf"}}`,
+	})
+
+	assert.Equalf(t, http.StatusUnprocessableEntity, w.Code, "Should return 422 for a private URL (got %d)", w.Code)
+}
+
+// TestPostPDFStream_BodyHTMLAndURL tests the API refuses items with both inline HTML and a remote URL
+func TestPostPDFStream_BodyHTMLAndURL(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   `{"items": [{"bodyHTML": "<p>Hi</p>", "url": "https://example.com"}], "config": {"fileName": "both.pdf"}}`,
+	})
+
+	assert.Equalf(t, http.StatusBadRequest, w.Code, "Should return 400 when both bodyHTML and url are present (got %d)", w.Code)
+}
+
+// TestPostPDFStream_RenderTimeout tests the API gives up on items that never finish loading
+func TestPostPDFStream_RenderTimeout(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	// Start a server whose responses never finish
+	release := make(chan struct{})
+	hangingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hangingServer.Close()
+	defer close(release)
+
+	body := fmt.Sprintf(
+		`{"items": [{"bodyHTML": "<img src=\"%s/image.png\">", "config": {"waitTimeoutSeconds": 1}}], "config": {"fileName": "hanging.pdf"}}`,
+		hangingServer.URL,
+	)
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   body,
+	})
+
+	assert.Equalf(t, http.StatusGatewayTimeout, w.Code, "Should return 504 when the item exceeds its timeout (got %d)", w.Code)
+}
+
+// TestPostPDFStream_WaitForReadyFlag tests the API waits for the page to flag itself as ready
+func TestPostPDFStream_WaitForReadyFlag(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	html := `<div id=\"chart\"></div><script>setTimeout(() => { document.getElementById('chart').innerHTML = '<p id=\"drawn\">Drawn</p>'; window.serpentariusReady = true; }, 500);</script>`
+	body := fmt.Sprintf(
+		`{"items": [{"bodyHTML": "%s", "config": {"waitFor": {"selector": "#drawn", "readyFlag": true, "fonts": true}}}], "config": {"fileName": "chart.pdf"}}`,
+		html,
+	)
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   body,
+	})
+
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 once the page is ready (got %d)", w.Code)
+	assert.Truef(t, len(w.Body.Bytes()) > 4 && string(w.Body.Bytes()[:4]) == "%PDF", "Body should be a PDF document")
+}
+
+// TestPostPDFStream_WaitForExpressionTimeout tests the API gives up on expressions that never become truthy
+func TestPostPDFStream_WaitForExpressionTimeout(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   `{"items": [{"bodyHTML": "<p>Never ready</p>", "config": {"waitTimeoutSeconds": 1, "waitFor": {"expression": "window.neverDefined"}}}], "config": {"fileName": "never.pdf"}}`,
+	})
+
+	assert.Equalf(t, http.StatusGatewayTimeout, w.Code, "Should return 504 when the expression never becomes truthy (got %d)", w.Code)
+}
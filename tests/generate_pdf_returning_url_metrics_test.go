@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostPDFUrl_CacheMetrics tests the URL use case records the miss, hit and stale cache lookups and the uploads
+func TestPostPDFUrl_CacheMetrics(t *testing.T) {
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	metrics := testUtilities.NewFakeMetricsRecorder()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithCloudStorage(cloudStorage),
+		testUtilities.WithMetricsRecorder(metrics),
+	)
+
+	// First request misses the cache and uploads the file
+	result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithExpiration(60)))
+	assert.NoError(t, err, "First request should succeed")
+	assert.False(t, result.CacheHit, "First request should not be a cache hit")
+	assert.Equal(t, 1, metrics.CacheLookups[sharedDefinitions.CACHE_LOOKUP_MISS], "First request should be a cache miss")
+	assert.Equal(t, 1, metrics.Uploads, "First request should upload the file")
+	assert.Equal(t, int64(len("%PDF-1.7")), metrics.UploadBytes, "Upload bytes should match the file size")
+
+	// Second request hits the cache
+	result, err = useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithExpiration(60)))
+	assert.NoError(t, err, "Second request should succeed")
+	assert.True(t, result.CacheHit, "Second request should be a cache hit")
+	assert.Equal(t, 1, metrics.CacheLookups[sharedDefinitions.CACHE_LOOKUP_HIT], "Second request should be a cache hit")
+
+	// Third request finds a cached URL pointing to a deleted file
+	clear(cloudStorage.Files)
+	result, err = useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithExpiration(60)))
+	assert.NoError(t, err, "Third request should succeed")
+	assert.False(t, result.CacheHit, "Third request should not be a cache hit")
+	assert.Equal(t, 1, metrics.CacheLookups[sharedDefinitions.CACHE_LOOKUP_STALE], "Third request should evict a stale entry")
+	assert.Equal(t, 2, metrics.Uploads, "Third request should upload the file again")
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostPDFUrl_PresignedURL tests presigned URLs are returned and cached for as long as they are valid
+func TestPostPDFUrl_PresignedURL(t *testing.T) {
+	cacheStorage := testUtilities.NewFakeURLCacheStorage()
+	useCase := testUtilities.NewTestURLUseCase(testUtilities.WithURLCacheStorage(cacheStorage))
+
+	result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("presigned.pdf"),
+		testUtilities.WithURLMode(sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED),
+		testUtilities.WithExpiration(600),
+	))
+	assert.NoError(t, err, "Request should succeed")
+	assert.Equal(t, "https://signed.example.com/reports/presigned.pdf?signature=fake", result.URL, "Should return the presigned URL")
+
+	for _, cacheExpiration := range cacheStorage.Expirations {
+		assert.InDelta(t, 600, cacheExpiration, 1, "Cache entry should expire with the signature")
+	}
+}
+
+// TestPostPDFUrl_PresignedURLCappedCache tests the cache entry never outlives a signature shortened by the backend
+func TestPostPDFUrl_PresignedURLCappedCache(t *testing.T) {
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	cloudStorage.MaxPresignExpiration = 5 * time.Minute
+	cacheStorage := testUtilities.NewFakeURLCacheStorage()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithCloudStorage(cloudStorage),
+		testUtilities.WithURLCacheStorage(cacheStorage),
+	)
+
+	_, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("presigned.pdf"),
+		testUtilities.WithURLMode(sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED),
+		testUtilities.WithExpiration(3600),
+	))
+	assert.NoError(t, err, "Request should succeed")
+
+	assert.Len(t, cacheStorage.Expirations, 1, "The URL should be cached")
+	for _, cacheExpiration := range cacheStorage.Expirations {
+		assert.LessOrEqual(t, cacheExpiration, int64(300), "Cache entry should not outlive the signature")
+		assert.Greater(t, cacheExpiration, int64(0), "Cache entry should expire")
+	}
+}
+
+// TestPostPDFUrl_PresignedURLWithoutExpiration tests presigned URLs require a positive expiration
+func TestPostPDFUrl_PresignedURLWithoutExpiration(t *testing.T) {
+	useCase := testUtilities.NewTestURLUseCase()
+
+	_, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("presigned.pdf"),
+		testUtilities.WithURLMode(sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED),
+		testUtilities.WithExpiration(0),
+	))
+
+	domainErr, ok := err.(sharedErrors.DomainError)
+	if assert.True(t, ok, "Should return a domain error") {
+		assert.Equal(t, sharedErrors.PRESIGNED_URL_EXPIRATION_NOT_VALID_ERROR_CODE, domainErr.Code(), "Should report the expiration is not valid")
+	}
+}
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/health/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/health/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/health/infrastructure/http/controllers"
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedHTTP "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http"
+	testConstants "github.com/PChaparro/serpentarius/tests/constants"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHealth_Liveness tests the liveness probe does not require authentication
+func TestHealth_Liveness(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.HEALTHZ_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 while the process is up (got %d)", w.Code)
+}
+
+// TestHealth_Readiness tests the readiness probe reports every dependency
+func TestHealth_Readiness(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.READYZ_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 when all the dependencies are ready (got %d)", w.Code)
+
+	respAny, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+
+	resp, ok := respAny.(map[string]any)
+	assert.Truef(t, ok, "Response should be a JSON object (got: %T)", respAny)
+
+	dependencies, ok := resp["dependencies"].([]any)
+	assert.Truef(t, ok, "Response should contain a 'dependencies' array (got: %v)", resp)
+
+	names := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		names = append(names, dependency.(map[string]any)["name"].(string))
+	}
+
+	// S3 is only checked through the configured health check bucket
+	expectedNames := []string{"redis", "chromium"}
+	if sharedInfrastructure.GetEnvironment().HealthCheckS3Bucket != "" {
+		expectedNames = append(expectedNames, "s3")
+	}
+	assert.ElementsMatchf(t, expectedNames, names, "Should check every dependency that can be checked (got: %v)", names)
+}
+
+// failingHealthChecker is a HealthChecker whose dependency cannot be used
+type failingHealthChecker struct{}
+
+// Name returns the name of the dependency
+func (c failingHealthChecker) Name() string {
+	return "s3"
+}
+
+// Check fails with an error revealing the dependency location
+func (c failingHealthChecker) Check(ctx context.Context) error {
+	return errors.New("dial tcp 10.0.0.12:9000: connection refused (bucket internal-reports)")
+}
+
+// TestHealth_ReadinessHidesErrors tests the failing dependencies are reported without their errors
+func TestHealth_ReadinessHidesErrors(t *testing.T) {
+	controller := controllers.CheckReadinessController{
+		UseCase: use_cases.CheckReadinessUseCase{
+			Checkers: []definitions.HealthChecker{failingHealthChecker{}},
+			Timeout:  time.Second,
+		},
+	}
+	router := gin.New()
+	router.GET(testConstants.READYZ_ENDPOINT, controller.Handle)
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.READYZ_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusServiceUnavailable, w.Code, "Should return 503 when a dependency is not ready (got %d)", w.Code)
+	assert.NotContains(t, w.Body.String(), "10.0.0.12", "Should not reveal the dependency host")
+	assert.NotContains(t, w.Body.String(), "internal-reports", "Should not reveal the dependency bucket")
+
+	respAny, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+
+	resp, ok := respAny.(map[string]any)
+	if !assert.Truef(t, ok, "Response should be a JSON object (got: %T)", respAny) {
+		return
+	}
+
+	dependencies, ok := resp["dependencies"].([]any)
+	if assert.Truef(t, ok, "Response should contain a 'dependencies' array (got: %v)", resp) && assert.Len(t, dependencies, 1) {
+		dependency, _ := dependencies[0].(map[string]any)
+		assert.Equal(t, "s3", dependency["name"], "Should report the dependency name")
+		assert.Equal(t, false, dependency["ready"], "Should report the dependency as not ready")
+	}
+}
+
+// TestHealth_BrowserPool tests the browser pool endpoint requires authentication and exposes the pool state
+func TestHealth_BrowserPool(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.ADMIN_POOL_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusUnauthorized, w.Code, "Should return 401 without authentication (got %d)", w.Code)
+
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.ADMIN_POOL_ENDPOINT,
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 with authentication (got %d)", w.Code)
+
+	respAny, err := testUtilities.ParseJSONResponse(w)
+	assert.NoError(t, err, "Response should be valid JSON")
+
+	pool, ok := respAny.(map[string]any)["pool"].(map[string]any)
+	assert.Truef(t, ok, "Response should contain a 'pool' object (got: %v)", respAny)
+
+	for _, field := range []string{"browserCount", "availablePages", "waitingQueueLength", "browsers"} {
+		_, hasField := pool[field]
+		assert.Truef(t, hasField, "Pool should contain the '%s' field (got: %v)", field, pool)
+	}
+}
+
+// TestHealth_Metrics tests the Prometheus metrics are exposed, including the HTTP requests by route
+func TestHealth_Metrics(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	// Make a request so it is recorded
+	testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.HEALTHZ_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.METRICS_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 (got %d)", w.Code)
+	assert.Containsf(t, w.Body.String(), `serpentarius_http_requests_total{method="GET",route="/healthz",status="200"}`, "Should record the requests by route")
+}
+
+// TestMetrics_OwnListener tests the metrics router serves only the Prometheus metrics
+func TestMetrics_OwnListener(t *testing.T) {
+	router := sharedHTTP.RegisterMetricsRoutes()
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.METRICS_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 (got %d)", w.Code)
+
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.HEALTHZ_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusNotFound, w.Code, "Should not serve the API (got %d)", w.Code)
+}
+
+// TestMetrics_AuthRequired tests the metrics require the API authentication when configured
+func TestMetrics_AuthRequired(t *testing.T) {
+	env := sharedInfrastructure.GetEnvironment()
+	env.MetricsAuthRequired = true
+	defer func() {
+		env.MetricsAuthRequired = false
+	}()
+	router := sharedHTTP.RegisterMetricsRoutes()
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.METRICS_ENDPOINT,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusUnauthorized, w.Code, "Should return 401 without the Authorization header (got %d)", w.Code)
+
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.METRICS_ENDPOINT,
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 with the Authorization header (got %d)", w.Code)
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	templateImplementations "github.com/PChaparro/serpentarius/internal/modules/template/infrastructure/implementations"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInMemoryTemplateStorage tests the versions of a template are never reused, even after it is deleted
+func TestInMemoryTemplateStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := templateImplementations.NewInMemoryTemplateStorage()
+
+	_, _ = storage.Create(ctx, "invoice", "<p>v1</p>")
+	_, _ = storage.Create(ctx, "invoice", "<p>v2</p>")
+	_, _ = storage.Create(ctx, "receipt", "<p>v1</p>")
+
+	latest, err := storage.Get(ctx, "invoice", nil)
+	if assert.NoError(t, err, "Getting the latest version should succeed") && assert.NotNil(t, latest, "Latest version should exist") {
+		assert.Equal(t, 2, latest.Version, "Latest version should be returned")
+	}
+	ids, _ := storage.List(ctx)
+	assert.Equal(t, []string{"invoice", "receipt"}, ids, "Templates should be listed alphabetically")
+
+	assert.NoError(t, storage.Delete(ctx, "invoice"), "Deleting a template should succeed")
+	deleted, _ := storage.Get(ctx, "invoice", nil)
+	assert.Nil(t, deleted, "Deleted template should not be returned")
+
+	recreated, _ := storage.Create(ctx, "invoice", "<p>v3</p>")
+	assert.Equal(t, 3, recreated.Version, "Versions should not be reused after deleting the template")
+	first := 1
+	firstVersion, _ := storage.Get(ctx, "invoice", &first)
+	assert.Nil(t, firstVersion, "Deleted versions should not be returned")
+}
+
+// TestInMemoryJobStorage_Retention tests the jobs are forgotten once their retention passes
+func TestInMemoryJobStorage_Retention(t *testing.T) {
+	ctx := context.Background()
+	storage := sharedImplementations.NewInMemoryJobStorage(50 * time.Millisecond)
+
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.Job{ID: "first", Status: sharedDefinitions.JOB_STATUS_QUEUED}))
+	job, err := storage.Get(ctx, "first")
+	if assert.NoError(t, err, "Getting a job should succeed") && assert.NotNil(t, job, "Job should be stored") {
+		assert.Equal(t, sharedDefinitions.JOB_STATUS_QUEUED, job.Status, "Job state should match")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	expired, err := storage.Get(ctx, "first")
+	assert.NoError(t, err, "Getting an expired job should succeed")
+	assert.Nil(t, expired, "Expired job should not be returned")
+}
+
+// TestInMemoryFileExpirationStorage tests the expired files are listed the oldest first, claimed once and only extended later
+func TestInMemoryFileExpirationStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := sharedImplementations.NewInMemoryFileExpirationStorage()
+	now := time.Now()
+	report := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "report.pdf"}
+	invoice := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "invoice.pdf"}
+	receipt := sharedDefinitions.ExpiringFile{FileFolder: "reports", FilePath: "receipt.pdf"}
+
+	_ = storage.Schedule(ctx, report, now.Add(-time.Minute))
+	_ = storage.Schedule(ctx, invoice, now.Add(-time.Hour))
+	_ = storage.Schedule(ctx, receipt, now.Add(time.Hour))
+
+	expired, err := storage.ListExpired(ctx, now, 10)
+	assert.NoError(t, err, "Listing the expired files should succeed")
+	assert.Equal(t, []sharedDefinitions.ExpiringFile{invoice, report}, expired, "Expired files should be listed the oldest first")
+
+	limited, _ := storage.ListExpired(ctx, now, 1)
+	assert.Equal(t, []sharedDefinitions.ExpiringFile{invoice}, limited, "Listed files should be limited")
+
+	claimed, _ := storage.Claim(ctx, invoice, now)
+	assert.True(t, claimed, "Expired file should be claimed")
+	claimed, _ = storage.Claim(ctx, invoice, now)
+	assert.False(t, claimed, "Claimed file should not be claimed again")
+	claimed, _ = storage.Claim(ctx, receipt, now)
+	assert.False(t, claimed, "File not expired yet should not be claimed")
+
+	// Extending never brings an expiration forward
+	_ = storage.Extend(ctx, receipt, now.Add(-time.Minute))
+	claimed, _ = storage.Claim(ctx, receipt, now)
+	assert.False(t, claimed, "Extending to an earlier time should keep the later expiration")
+	_ = storage.Extend(ctx, invoice, now.Add(-time.Minute))
+	claimed, _ = storage.Claim(ctx, invoice, now)
+	assert.True(t, claimed, "Extending a file not scheduled should schedule it")
+}
+
+// TestInMemoryDeduplicatedFileStorage tests a deduplicated file is released once its last copy is removed or replaced
+func TestInMemoryDeduplicatedFileStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := sharedImplementations.NewInMemoryDeduplicatedFileStorage()
+	first := sharedDefinitions.DeduplicatedFile{FileFolder: "reports", FilePath: "deduplicated/first.pdf", ContentHash: "first"}
+	second := sharedDefinitions.DeduplicatedFile{FileFolder: "reports", FilePath: "deduplicated/second.pdf", ContentHash: "second"}
+	invoice := sharedDefinitions.FileCopy{FileFolder: "reports", FilePath: "invoice.pdf"}
+	receipt := sharedDefinitions.FileCopy{FileFolder: "reports", FilePath: "receipt.pdf"}
+
+	_, _ = storage.AddCopy(ctx, first, invoice)
+	_, _ = storage.AddCopy(ctx, first, receipt)
+	released, err := storage.AddCopy(ctx, first, receipt)
+	assert.NoError(t, err, "Recording the same copy again should succeed")
+	assert.Nil(t, released, "Recording the same copy again should not release its source")
+
+	released, _ = storage.RemoveCopy(ctx, invoice)
+	assert.Nil(t, released, "Source should not be released while a copy is left")
+
+	released, _ = storage.AddCopy(ctx, second, receipt)
+	assert.Equal(t, &first, released, "Source should be released once its last copy is replaced")
+
+	released, _ = storage.RemoveCopy(ctx, receipt)
+	assert.Equal(t, &second, released, "Source should be released once its last copy is removed")
+
+	released, err = storage.RemoveCopy(ctx, receipt)
+	assert.NoError(t, err, "Removing a file that is not a copy should succeed")
+	assert.Nil(t, released, "Removing a file that is not a copy should not release anything")
+}
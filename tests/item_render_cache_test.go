@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/implementations"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFingerprintItem tests the item keys only depend on the HTML and the normalized print options
+func TestFingerprintItem(t *testing.T) {
+	fingerprinter := fingerprint.RequestFingerprinter{HashGenerator: testUtilities.FakeHashGenerator{}, Version: 1}
+	scale := 1.0
+	landscape := "landscape"
+	remoteURL := "https://example.com/report"
+
+	key, cacheable, err := fingerprinter.FingerprintItem(dto.PDFItem{BodyHTML: "<p>Cover</p>"})
+	assert.NoError(t, err, "Fingerprinting should succeed")
+	assert.True(t, cacheable, "Inline items should be cacheable")
+
+	defaultKey, _, _ := fingerprinter.FingerprintItem(dto.PDFItem{
+		BodyHTML: "<p>Cover</p>",
+		Config:   &dto.ItemConfig{Scale: &scale},
+	})
+	assert.Equal(t, key, defaultKey, "Default print options should yield the same key")
+
+	landscapeKey, _, _ := fingerprinter.FingerprintItem(dto.PDFItem{
+		BodyHTML: "<p>Cover</p>",
+		Config:   &dto.ItemConfig{Orientation: &landscape},
+	})
+	assert.NotEqual(t, key, landscapeKey, "Different print options should yield different keys")
+
+	_, cacheable, err = fingerprinter.FingerprintItem(dto.PDFItem{URL: &remoteURL})
+	assert.NoError(t, err, "Fingerprinting a remote item should succeed")
+	assert.False(t, cacheable, "Remote items should not be cacheable")
+}
+
+// TestPDFGeneratorRod_ItemCache tests the cached items are merged without rendering them
+func TestPDFGeneratorRod_ItemCache(t *testing.T) {
+	fingerprinter := fingerprint.RequestFingerprinter{HashGenerator: testUtilities.FakeHashGenerator{}, Version: 1}
+	itemCache := testUtilities.NewFakeRenderCache()
+	items := []dto.PDFItem{{BodyHTML: "<p>Cover</p>"}, {BodyHTML: "<p>Appendix</p>"}}
+	for idx, item := range items {
+		key, _, err := fingerprinter.FingerprintItem(item)
+		assert.NoError(t, err, "Fingerprinting should succeed")
+		itemCache.Entries[key] = dto.CachedPDF{Content: testUtilities.NewBlankPDF(idx + 1)}
+	}
+
+	// Every item is cached, so no browser is launched
+	generator := implementations.NewPDFGeneratorRod(testUtilities.NewFakeMetricsRecorder(), itemCache, fingerprinter)
+	pdf, err := generator.GeneratePDF(context.Background(), &dto.PDFGenerationDTO{Items: items})
+	if !assert.NoError(t, err, "Generation should succeed") {
+		return
+	}
+	defer func() {
+		_ = pdf.Reader.Close()
+	}()
+
+	assert.Equal(t, 3, pdf.PageCount, "Merged PDF should hold the pages of every cached item")
+	assert.Equal(t, dto.ItemCacheStats{Hits: 2, Misses: 0}, pdf.ItemCache, "Every item should be a hit")
+}
+
+// TestPostPDFUrl_ItemCacheStats tests the item cache hits and misses are reported for rendered PDFs only
+func TestPostPDFUrl_ItemCacheStats(t *testing.T) {
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(&testUtilities.FakePDFGenerator{
+			Content:   []byte("%PDF-1.7"),
+			ItemCache: dto.ItemCacheStats{Hits: 29, Misses: 1},
+		}),
+		testUtilities.WithRenderCache(testUtilities.NewFakeRenderCache()),
+	)
+
+	rendered, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest())
+	assert.NoError(t, err, "Request should succeed")
+	if assert.NotNil(t, rendered.ItemCache, "Rendered PDFs should report the item cache") {
+		assert.Equal(t, dto.ItemCacheStats{Hits: 29, Misses: 1}, *rendered.ItemCache, "Item cache stats should be reported")
+	}
+
+	cached, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest())
+	assert.NoError(t, err, "Request should succeed")
+	assert.True(t, cached.CacheHit, "Second request should hit the URL cache")
+	assert.Nil(t, cached.ItemCache, "Cached URLs should not report the item cache")
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedMiddlewares "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http/middlewares"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/storage/infrastructure/http/controllers"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLocalFilesRouter returns a router serving the files of the given local storage
+func newLocalFilesRouter(storage *sharedImplementations.LocalCloudStorage) *gin.Engine {
+	router := gin.New()
+	router.Use(sharedMiddlewares.ErrorHandlerMiddleware())
+
+	controller := &controllers.GetStoredFileController{
+		UseCase: use_cases.GetStoredFileUseCase{LocalFileStorage: storage},
+	}
+	router.GET(sharedImplementations.LOCAL_FILES_ROUTE_PREFIX+"/*filepath", controller.Handle)
+
+	return router
+}
+
+// uploadLocalTestFile uploads a small PDF to the local storage and returns its path relative to the server
+func uploadLocalTestFile(t *testing.T, storage *sharedImplementations.LocalCloudStorage) string {
+	err := storage.UploadFile(context.Background(), sharedDefinitions.UploadFileRequest{
+		FileReader:  strings.NewReader("%PDF-1.7"),
+		FileFolder:  "reports",
+		FilePath:    "2025/monthly report.pdf",
+		FileOptions: sharedDefinitions.FileOptions{ContentType: "application/pdf"},
+	})
+	assert.NoError(t, err, "Upload should succeed")
+
+	downloadURL, err := storage.GetDownloadURL(context.Background(), sharedDefinitions.GetDownloadURLRequest{
+		FileFolder:      "reports",
+		FilePath:        "2025/monthly report.pdf",
+		Mode:            sharedDefinitions.DOWNLOAD_URL_MODE_PUBLIC,
+		PublicURLPrefix: "http://localhost:3000",
+	})
+	assert.NoError(t, err, "Getting the download URL should succeed")
+
+	fileURL := downloadURL.URL
+	assert.Truef(t, strings.HasPrefix(fileURL, "http://localhost:3000/files/reports/2025/monthly%20report.pdf"), "URL should point to the files route (got: %s)", fileURL)
+
+	return strings.TrimPrefix(fileURL, "http://localhost:3000")
+}
+
+// TestLocalCloudStorage_UploadAndServe tests uploaded files exist and are served from the files route
+func TestLocalCloudStorage_UploadAndServe(t *testing.T) {
+	storage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "", time.Minute)
+	router := newLocalFilesRouter(storage)
+
+	exists, err := storage.FileExists(context.Background(), sharedDefinitions.FileExistsRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly report.pdf",
+	})
+	assert.NoError(t, err, "Checking a missing file should succeed")
+	assert.False(t, exists, "File should not exist before the upload")
+
+	filePath := uploadLocalTestFile(t, storage)
+
+	exists, err = storage.FileExists(context.Background(), sharedDefinitions.FileExistsRequest{
+		FileFolder: "reports",
+		FilePath:   "2025/monthly report.pdf",
+	})
+	assert.NoError(t, err, "Checking an uploaded file should succeed")
+	assert.True(t, exists, "File should exist after the upload")
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    filePath,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 for an uploaded file (got %d)", w.Code)
+	assert.Equal(t, "%PDF-1.7", w.Body.String(), "Should return the file content")
+
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    "/files/reports/missing.pdf",
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusNotFound, w.Code, "Should return 404 for a missing file (got %d)", w.Code)
+}
+
+// TestLocalCloudStorage_PathTraversal tests files outside the storage root cannot be written or served
+func TestLocalCloudStorage_PathTraversal(t *testing.T) {
+	storage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "", time.Minute)
+	router := newLocalFilesRouter(storage)
+
+	err := storage.UploadFile(context.Background(), sharedDefinitions.UploadFileRequest{
+		FileReader: strings.NewReader("%PDF-1.7"),
+		FileFolder: "reports",
+		FilePath:   "../../escaped.pdf",
+	})
+	var domainError sharedErrors.DomainError
+	if assert.ErrorAs(t, err, &domainError, "Upload should fail with a domain error when the path escapes the root") {
+		assert.Equal(t, sharedErrors.FILE_PATH_NOT_VALID_ERROR_CODE, domainError.Code(), "Error code should match")
+	}
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    "/files/reports/..%2F..%2Fetc%2Fpasswd",
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusNotFound, w.Code, "Should return 404 when the path escapes the root (got %d)", w.Code)
+}
+
+// TestLocalCloudStorage_PathTraversalStatus tests the paths escaping the root are reported as bad requests, even if the error is wrapped
+func TestLocalCloudStorage_PathTraversalStatus(t *testing.T) {
+	storage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "", time.Minute)
+
+	router := gin.New()
+	router.Use(sharedMiddlewares.ErrorHandlerMiddleware())
+	router.DELETE("/files", func(c *gin.Context) {
+		err := storage.DeleteFile(c.Request.Context(), sharedDefinitions.DeleteFileRequest{
+			FileFolder: "reports",
+			FilePath:   "../../escaped.pdf",
+		})
+		_ = c.Error(fmt.Errorf("error deleting the file: %w", err))
+	})
+
+	w := testUtilities.SendToAPI(testUtilities.APIRequest{
+		Router: router,
+		Method: http.MethodDelete,
+		URL:    "/files",
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusBadRequest, w.Code, "Should return 400 when the path escapes the root (got %d)", w.Code)
+}
+
+// TestLocalCloudStorage_CopyFile tests files are copied within the storage root
+func TestLocalCloudStorage_CopyFile(t *testing.T) {
+	storage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "", time.Minute)
+	router := newLocalFilesRouter(storage)
+	uploadLocalTestFile(t, storage)
+
+	err := storage.CopyFile(context.Background(), sharedDefinitions.CopyFileRequest{
+		SourceFileFolder: "reports",
+		SourceFilePath:   "2025/monthly report.pdf",
+		FileFolder:       "archive",
+		FilePath:         "2025/copy.pdf",
+	})
+	assert.NoError(t, err, "Copy should succeed")
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    "/files/archive/2025/copy.pdf",
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 for a copied file (got %d)", w.Code)
+	assert.Equal(t, "%PDF-1.7", w.Body.String(), "Should return the content of the source file")
+
+	err = storage.CopyFile(context.Background(), sharedDefinitions.CopyFileRequest{
+		SourceFileFolder: "reports",
+		SourceFilePath:   "missing.pdf",
+		FileFolder:       "archive",
+		FilePath:         "2025/missing.pdf",
+	})
+	assert.Error(t, err, "Copying a missing file should fail")
+}
+
+// TestLocalCloudStorage_SignedURLs tests files are only served through valid signed URLs when there is a secret
+func TestLocalCloudStorage_SignedURLs(t *testing.T) {
+	storage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "a-very-long-signing-secret", time.Minute)
+	router := newLocalFilesRouter(storage)
+
+	filePath := uploadLocalTestFile(t, storage)
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    filePath,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 with a valid signature (got %d)", w.Code)
+
+	// The URL without the signature is rejected
+	unsignedPath, _, _ := strings.Cut(filePath, "?")
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    unsignedPath,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusForbidden, w.Code, "Should return 403 without a signature (got %d)", w.Code)
+
+	// Extending the expiration time invalidates the signature
+	parsedURL, _ := url.Parse(filePath)
+	query := parsedURL.Query()
+	query.Set(sharedImplementations.LOCAL_FILE_EXPIRES_QUERY_PARAM, "99999999999")
+	parsedURL.RawQuery = query.Encode()
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    parsedURL.String(),
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusForbidden, w.Code, "Should return 403 with a tampered URL (got %d)", w.Code)
+
+	// Expired URLs are rejected
+	expiredStorage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "a-very-long-signing-secret", -time.Minute)
+	expiredFilePath := uploadLocalTestFile(t, expiredStorage)
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: newLocalFilesRouter(expiredStorage),
+		URL:    expiredFilePath,
+		Auth:   testUtilities.AuthOptions{Skip: true},
+	})
+	assert.Equalf(t, http.StatusForbidden, w.Code, "Should return 403 with an expired URL (got %d)", w.Code)
+}
+
+// TestLocalCloudStorage_PresignedURLs tests presigned URLs require a secret and expire as requested
+func TestLocalCloudStorage_PresignedURLs(t *testing.T) {
+	request := sharedDefinitions.GetDownloadURLRequest{
+		FileFolder:      "reports",
+		FilePath:        "monthly.pdf",
+		Mode:            sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED,
+		PublicURLPrefix: "http://localhost:3000",
+		Expiration:      10 * time.Minute,
+	}
+
+	unsignedStorage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "", time.Minute)
+	_, err := unsignedStorage.GetDownloadURL(context.Background(), request)
+	assert.Error(t, err, "Presigning should fail without a signing secret")
+
+	storage := sharedImplementations.NewLocalCloudStorage(t.TempDir(), "a-very-long-signing-secret", time.Minute)
+	downloadURL, err := storage.GetDownloadURL(context.Background(), request)
+	assert.NoError(t, err, "Presigning should succeed with a signing secret")
+	if assert.NotNil(t, downloadURL.ExpiresAt, "Presigned URLs should report their expiration") {
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), *downloadURL.ExpiresAt, 2*time.Second, "Expiration should match the requested one")
+	}
+	assert.Contains(t, downloadURL.URL, sharedImplementations.LOCAL_FILE_SIGNATURE_QUERY_PARAM+"=", "URL should be signed")
+}
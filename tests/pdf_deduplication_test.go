@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostPDFUrl_Deduplication tests identical documents are rendered once and copied to every requested file name
+func TestPostPDFUrl_Deduplication(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCloudStorage(cloudStorage),
+		testUtilities.WithFileExpirationStorage(expirationStorage),
+		testUtilities.WithDefaultRetention(600),
+	)
+
+	first, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("first.pdf"),
+		testUtilities.WithDeduplication(true),
+	))
+	assert.NoError(t, err, "First request should succeed")
+	assert.False(t, first.Deduplicated, "First request should render the PDF")
+	assert.NotNil(t, first.FileSize, "File size should be known when the PDF is rendered")
+
+	second, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+		testUtilities.WithFileName("second.pdf"),
+		testUtilities.WithDeduplication(true),
+	))
+	assert.NoError(t, err, "Second request should succeed")
+	assert.True(t, second.Deduplicated, "Second request should reuse the rendered PDF")
+	assert.Nil(t, second.FileSize, "File size should be unknown when the PDF is deduplicated")
+	assert.Equal(t, "https://cdn.example.com/reports/second.pdf", second.URL, "URL should point to the requested file name")
+
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered once")
+	assert.Contains(t, cloudStorage.Files, "reports/first.pdf", "First file should be stored")
+	assert.Contains(t, cloudStorage.Files, "reports/second.pdf", "Second file should be stored")
+	assert.Len(t, cloudStorage.Copies, 2, "Both files should be copied from the deduplicated PDF")
+
+	deduplicatedFiles := 0
+	for file := range expirationStorage.Expirations {
+		if strings.HasPrefix(file.FilePath, use_cases.DEDUPLICATED_FILES_PREFIX) {
+			deduplicatedFiles++
+		}
+	}
+	assert.Equal(t, 1, deduplicatedFiles, "Deduplicated PDF deletion should be scheduled")
+}
+
+// TestPostPDFUrl_DeduplicationRetention tests the deduplicated PDF is kept until the longest retention of its copies,
+// even if later copies ask for a shorter one or to be kept forever
+func TestPostPDFUrl_DeduplicationRetention(t *testing.T) {
+	expirationStorage := testUtilities.NewFakeFileExpirationStorage()
+	useCase := testUtilities.NewTestURLUseCase(testUtilities.WithFileExpirationStorage(expirationStorage))
+
+	requests := []struct {
+		fileName  string
+		retention int64
+	}{
+		{fileName: "long.pdf", retention: 3600},
+		{fileName: "short.pdf", retention: 60},
+		{fileName: "forever.pdf", retention: 0},
+	}
+	for _, request := range requests {
+		_, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(
+			testUtilities.WithFileName(request.fileName),
+			testUtilities.WithRetention(request.retention),
+			testUtilities.WithDeduplication(true),
+		))
+		assert.NoError(t, err, "Request should succeed")
+	}
+
+	var deduplicatedExpiration *time.Time
+	for file, expiresAt := range expirationStorage.Expirations {
+		if strings.HasPrefix(file.FilePath, use_cases.DEDUPLICATED_FILES_PREFIX) {
+			deduplicatedExpiration = &expiresAt
+		}
+	}
+	if assert.NotNil(t, deduplicatedExpiration, "Deduplicated PDF deletion should stay scheduled") {
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *deduplicatedExpiration, 5*time.Second, "Deduplicated PDF should expire along with the longest retention")
+	}
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expirationStorage.Expirations[sharedDefinitions.ExpiringFile{
+		FileFolder: "reports",
+		FilePath:   "short.pdf",
+	}], 5*time.Second, "Copies should expire after their own retention")
+	assert.NotContains(t, expirationStorage.Expirations, sharedDefinitions.ExpiringFile{
+		FileFolder: "reports",
+		FilePath:   "forever.pdf",
+	}, "Copies kept forever should not be scheduled")
+}
+
+// TestPostPDFUrl_WithoutDeduplication tests identical documents are rendered again unless deduplication is requested
+func TestPostPDFUrl_WithoutDeduplication(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCloudStorage(cloudStorage),
+	)
+
+	for _, fileName := range []string{"first.pdf", "second.pdf"} {
+		result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName(fileName)))
+		assert.NoError(t, err, "Request should succeed")
+		assert.False(t, result.Deduplicated, "Request should render the PDF")
+	}
+
+	assert.Equal(t, 2, generator.Calls, "PDF should be rendered for every file name")
+	assert.Empty(t, cloudStorage.Copies, "No file should be copied")
+	assert.Len(t, cloudStorage.Files, 2, "Only the requested files should be stored")
+}
+
+// TestPostPDFUrl_DeduplicationRemoteItems tests documents with remote items are rendered again even if deduplication is requested
+func TestPostPDFUrl_DeduplicationRemoteItems(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCloudStorage(cloudStorage),
+	)
+
+	remoteURL := "https://example.com/invoice"
+	for _, fileName := range []string{"first.pdf", "second.pdf"} {
+		request := testUtilities.NewTestPDFRequest(
+			testUtilities.WithFileName(fileName),
+			testUtilities.WithDeduplication(true),
+			testUtilities.WithItems(dto.PDFItem{URL: &remoteURL}),
+		)
+
+		result, err := useCase.Execute(context.Background(), request)
+		assert.NoError(t, err, "Request should succeed")
+		if assert.NotNil(t, result, "Result should be returned") {
+			assert.False(t, result.Deduplicated, "Request with remote items should render the PDF")
+		}
+	}
+
+	assert.Equal(t, 2, generator.Calls, "PDF with remote items should be rendered for every file name")
+	assert.Empty(t, cloudStorage.Copies, "No file should be copied")
+	assert.Len(t, cloudStorage.Files, 2, "Only the requested files should be stored")
+}
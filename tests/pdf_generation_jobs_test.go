@@ -0,0 +1,260 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/implementations"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	sharedHTTP "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	testConstants "github.com/PChaparro/serpentarius/tests/constants"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// Hardcoded maximum time to wait for a job to finish
+	MAX_DURATION_FOR_JOB_COMPLETION = 60 * time.Second
+	// Interval between job status polls
+	JOB_POLLING_INTERVAL = 250 * time.Millisecond
+)
+
+// TestPDFJobs_ValidDocument tests a job is queued and eventually succeeds with a valid document
+func TestPDFJobs_ValidDocument(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	bodyBytes, err := testUtilities.ReadFileFromTestsDataDirectory("valid-request.json")
+	if err != nil {
+		t.Fatalf("Could not read valid body file: %v", err)
+	}
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.PDF_GENERATION_JOBS_ENDPOINT,
+		Body:   string(bodyBytes),
+	})
+	require.Equalf(t, http.StatusAccepted, w.Code, "Should return 202 with valid document (got %d)", w.Code)
+
+	respAny, err := testUtilities.ParseJSONResponse(w)
+	require.NoError(t, err, "Response should be valid JSON")
+
+	resp, ok := respAny.(map[string]any)
+	require.Truef(t, ok, "Response should be a JSON object (got: %T)", respAny)
+
+	job, ok := resp["job"].(map[string]any)
+	require.Truef(t, ok, "Response should contain a 'job' object (got: %v)", resp)
+
+	jobID, ok := job["id"].(string)
+	require.Truef(t, ok && jobID != "", "Job should have an 'id' (got: %v)", job)
+	assert.Equalf(t, "queued", job["status"], "Job should be queued (got: %v)", job["status"])
+
+	// Poll the job until it finishes
+	deadline := time.Now().Add(MAX_DURATION_FOR_JOB_COMPLETION)
+	for time.Now().Before(deadline) {
+		w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+			Router: router,
+			URL:    testConstants.PDF_GENERATION_JOBS_ENDPOINT + "/" + jobID,
+		})
+		require.Equalf(t, http.StatusOK, w.Code, "Should return 200 for an existing job (got %d)", w.Code)
+
+		respAny, err = testUtilities.ParseJSONResponse(w)
+		require.NoError(t, err, "Response should be valid JSON")
+
+		resp, ok = respAny.(map[string]any)
+		require.Truef(t, ok, "Response should be a JSON object (got: %T)", respAny)
+
+		job, ok = resp["job"].(map[string]any)
+		require.Truef(t, ok, "Response should contain a 'job' object (got: %v)", resp)
+		if job["status"] == "succeeded" || job["status"] == "failed" {
+			break
+		}
+
+		time.Sleep(JOB_POLLING_INTERVAL)
+	}
+
+	assert.Equalf(t, "succeeded", job["status"], "Job should succeed (got: %v)", job)
+
+	url, ok := job["url"].(string)
+	assert.Truef(t, ok, "Succeeded job should contain a 'url' field (got: %v)", job)
+	assert.NotEmptyf(t, url, "Succeeded job 'url' field should not be empty (got: %v)", url)
+}
+
+// TestPDFJobs_NotFound tests the API returns 404 for unknown jobs
+func TestPDFJobs_NotFound(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    testConstants.PDF_GENERATION_JOBS_ENDPOINT + "/not-existing-job",
+	})
+
+	assert.Equalf(t, http.StatusNotFound, w.Code, "Should return 404 for unknown job (got %d)", w.Code)
+}
+
+// TestPDFJobs_Webhook tests the callback URL receives a signed notification when the job finishes
+func TestPDFJobs_Webhook(t *testing.T) {
+	const secret = "a-very-long-webhook-secret"
+
+	// Start a server that receives the callbacks
+	received := make(chan *http.Request, 1)
+	receivedBodies := make(chan []byte, 1)
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		receivedBodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer callbackServer.Close()
+
+	// The callback server listens on the loopback interface
+	jobStorage := sharedImplementations.NewInMemoryJobStorage(0)
+	useCase := use_cases.DeliverPDFGenerationJobWebhookUseCase{
+		Fetcher:        sharedImplementations.NewHTTPFetcher(5*time.Second, implementations.NewRemoteURLPolicy(nil, nil, true)),
+		JobStorage:     jobStorage,
+		MaxAttempts:    1,
+		InitialBackoff: time.Millisecond,
+	}
+	url := "https://cdn.example.com/reports/report.pdf"
+	cacheHit := false
+	job := sharedDefinitions.Job{ID: "webhook-job", Status: sharedDefinitions.JOB_STATUS_SUCCEEDED, URL: &url, CacheHit: &cacheHit}
+	secretValue := secret
+	assert.NoError(t, useCase.Execute(context.Background(), job, callbackServer.URL, &secretValue), "Webhook should be delivered")
+
+	r := <-received
+	callbackBody := <-receivedBodies
+
+	// Verify the signature
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(r.Header.Get("X-Serpentarius-Timestamp") + "."))
+	mac.Write(callbackBody)
+	expectedSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	assert.Equalf(t, expectedSignature, r.Header.Get("X-Serpentarius-Signature"), "Callback should be signed with the secret")
+
+	// Verify the payload
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal(callbackBody, &payload), "Callback body should be valid JSON")
+	assert.Equalf(t, "succeeded", payload["status"], "Callback should report the job succeeded (got: %v)", payload)
+	assert.NotEmptyf(t, payload["url"], "Callback should contain the URL (got: %v)", payload)
+	_, hasCacheHit := payload["cacheHit"]
+	assert.Truef(t, hasCacheHit, "Callback should contain the cache-hit flag (got: %v)", payload)
+
+	storedJob, _ := jobStorage.Get(context.Background(), job.ID)
+	if assert.NotNil(t, storedJob, "Job should be stored") && assert.NotNil(t, storedJob.Webhook, "Delivery should be recorded") {
+		assert.Equal(t, sharedDefinitions.WEBHOOK_STATUS_DELIVERED, storedJob.Webhook.Status, "Webhook should be delivered")
+	}
+}
+
+// TestPDFJobs_PrivateCallbackURL tests the jobs notifying a private network are rejected
+func TestPDFJobs_PrivateCallbackURL(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	bodyBytes, err := testUtilities.ReadFileFromTestsDataDirectory("valid-request.json")
+	if err != nil {
+		t.Fatalf("Could not read valid body file: %v", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		t.Fatalf("Could not parse valid body file: %v", err)
+	}
+	body["config"].(map[string]any)["callbackURL"] = "http://169.254.169.254/latest/meta-data"
+	bodyBytes, _ = json.Marshal(body)
+
+	w := testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.PDF_GENERATION_JOBS_ENDPOINT,
+		Body:   string(bodyBytes),
+	})
+	assert.Equalf(t, http.StatusUnprocessableEntity, w.Code, "Should return 422 with a private callback URL (got %d)", w.Code)
+}
+
+// TestPDFJobs_QueueFull tests the jobs that cannot be dispatched are recorded as failed instead of left queued
+func TestPDFJobs_QueueFull(t *testing.T) {
+	ctx := context.Background()
+	code := sharedErrors.JOB_QUEUE_FULL_ERROR_CODE
+	jobStorage := sharedImplementations.NewInMemoryJobStorage(0)
+	dispatcher := &testUtilities.FakePDFJobDispatcher{
+		Error: sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+			Code:    &code,
+			Message: "There are too many queued jobs. Please, try again later",
+		}),
+	}
+	useCase := use_cases.CreatePDFGenerationJobUseCase{
+		JobStorage:    jobStorage,
+		JobDispatcher: dispatcher,
+	}
+
+	job, err := useCase.Execute(ctx, &dto.PDFGenerationDTO{})
+	assert.ErrorIs(t, err, dispatcher.Error, "Dispatch error should be returned")
+	assert.Nil(t, job, "No job should be returned")
+
+	if assert.Len(t, dispatcher.JobIDs, 1, "Job should be dispatched once") {
+		storedJob, _ := jobStorage.Get(ctx, dispatcher.JobIDs[0])
+		if assert.NotNil(t, storedJob, "Job should be stored") && assert.NotNil(t, storedJob.Error, "Job error should be recorded") {
+			assert.Equal(t, sharedDefinitions.JOB_STATUS_FAILED, storedJob.Status, "Job should be failed")
+			assert.Equal(t, code, storedJob.Error.Code, "Job error code should match")
+		}
+	}
+}
+
+// TestPDFJobs_WebhookRedirect tests the webhooks do not follow redirects to URLs that are not allowed, nor retry them
+func TestPDFJobs_WebhookRedirect(t *testing.T) {
+	var internalRequests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/internal", func(w http.ResponseWriter, r *http.Request) {
+		internalRequests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/internal", http.StatusTemporaryRedirect)
+	})
+
+	jobStorage := sharedImplementations.NewInMemoryJobStorage(0)
+	useCase := use_cases.DeliverPDFGenerationJobWebhookUseCase{
+		Fetcher:        sharedImplementations.NewHTTPFetcher(5*time.Second, implementations.NewRemoteURLPolicy(nil, []string{"localhost"}, true)),
+		JobStorage:     jobStorage,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+	job := sharedDefinitions.Job{ID: "redirected-job", Status: sharedDefinitions.JOB_STATUS_FAILED}
+
+	err := useCase.Execute(context.Background(), job, server.URL+"/webhook", nil)
+	assert.Error(t, err, "Redirect to a denied host should fail")
+	assert.Zero(t, internalRequests.Load(), "Denied host should not be reached")
+
+	storedJob, _ := jobStorage.Get(context.Background(), job.ID)
+	if assert.NotNil(t, storedJob, "Job should be stored") && assert.NotNil(t, storedJob.Webhook, "Delivery should be recorded") {
+		assert.Equal(t, sharedDefinitions.WEBHOOK_STATUS_FAILED, storedJob.Webhook.Status, "Webhook should fail")
+		assert.Equal(t, 1, storedJob.Webhook.Attempts, "Not allowed URL should not be retried")
+	}
+}
+
+// TestHTTPFetcher_ResponseLimit tests the response bodies are read up to the limit
+func TestHTTPFetcher_ResponseLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, 2*sharedImplementations.HTTP_FETCHER_MAX_RESPONSE_BYTES))
+	}))
+	defer server.Close()
+
+	fetcher := sharedImplementations.NewHTTPFetcher(5*time.Second, nil)
+	body, err := fetcher.Get(context.Background(), sharedDefinitions.GetRequest{URL: server.URL})
+	assert.NoError(t, err, "Request should succeed")
+	assert.Len(t, body, sharedImplementations.HTTP_FETCHER_MAX_RESPONSE_BYTES, "Response body should be truncated")
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSelfSignedCertificate writes a self-signed certificate and its key as PEM files, returning their paths
+func writeSelfSignedCertificate(t *testing.T) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate ECDSA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis.internal"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("Could not create certificate: %v", err)
+	}
+	encodedKey, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+
+	directory := t.TempDir()
+	certificatePath := filepath.Join(directory, "redis.crt")
+	keyPath := filepath.Join(directory, "redis.key")
+	_ = os.WriteFile(certificatePath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0o600)
+	_ = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedKey}), 0o600)
+
+	return certificatePath, keyPath
+}
+
+// TestNewRedisOptions_Standalone tests a single node is addressed by its host and port
+func TestNewRedisOptions_Standalone(t *testing.T) {
+	options, err := sharedImplementations.NewRedisOptions(&sharedInfrastructure.EnvironmentSpec{
+		RedisHost:     "redis.internal",
+		RedisPort:     "6380",
+		RedisUsername: "renderer",
+		RedisPassword: "secret",
+		RedisDB:       2,
+		RedisPoolSize: 20,
+	})
+
+	assert.NoError(t, err, "Options should be built")
+	assert.Equal(t, []string{"redis.internal:6380"}, options.Addrs, "Host and port should be the address")
+	assert.False(t, options.IsClusterMode, "Single node should not be a cluster")
+	assert.Equal(t, "renderer", options.Username, "ACL username should be set")
+	assert.Equal(t, 2, options.DB, "DB should be kept")
+	assert.Equal(t, 20, options.PoolSize, "Pool size should be set")
+	assert.Nil(t, options.TLSConfig, "TLS should be disabled")
+}
+
+// TestNewRedisOptions_Sentinel tests the sentinels are addressed when there is a master name
+func TestNewRedisOptions_Sentinel(t *testing.T) {
+	options, err := sharedImplementations.NewRedisOptions(&sharedInfrastructure.EnvironmentSpec{
+		RedisAddresses:        []string{"sentinel-1:26379", "sentinel-2:26379", "sentinel-3:26379"},
+		RedisMasterName:       "renderer",
+		RedisDB:               1,
+		RedisSentinelPassword: "sentinel-secret",
+	})
+
+	assert.NoError(t, err, "Options should be built")
+	assert.Len(t, options.Addrs, 3, "Every sentinel should be addressed")
+	assert.Equal(t, "renderer", options.MasterName, "Master name should be set")
+	assert.False(t, options.IsClusterMode, "Sentinels should not be a cluster")
+	assert.Equal(t, 1, options.DB, "DB should be kept")
+	assert.Equal(t, "sentinel-secret", options.SentinelPassword, "Sentinel password should be set")
+}
+
+// TestNewRedisOptions_Cluster tests several addresses, or a single one in cluster mode, are cluster seed nodes
+func TestNewRedisOptions_Cluster(t *testing.T) {
+	options, err := sharedImplementations.NewRedisOptions(&sharedInfrastructure.EnvironmentSpec{
+		RedisAddresses: []string{"node-1:6379", "node-2:6379"},
+		RedisDB:        3,
+	})
+	assert.NoError(t, err, "Options should be built")
+	assert.True(t, options.IsClusterMode, "Several addresses should be a cluster")
+	assert.Zero(t, options.DB, "Cluster should use the only DB")
+
+	options, err = sharedImplementations.NewRedisOptions(&sharedInfrastructure.EnvironmentSpec{
+		RedisAddresses:   []string{"clustercfg.renderer.cache.amazonaws.com:6379"},
+		RedisClusterMode: true,
+	})
+	assert.NoError(t, err, "Options should be built")
+	assert.True(t, options.IsClusterMode, "Configuration endpoint should be a cluster")
+}
+
+// TestNewRedisOptions_TLS tests the server is verified with the CA file and the client presents its certificate
+func TestNewRedisOptions_TLS(t *testing.T) {
+	certificatePath, keyPath := writeSelfSignedCertificate(t)
+
+	options, err := sharedImplementations.NewRedisOptions(&sharedInfrastructure.EnvironmentSpec{
+		RedisHost:          "redis.internal",
+		RedisPort:          "6379",
+		RedisTLSEnabled:    true,
+		RedisTLSCaFile:     certificatePath,
+		RedisTLSCertFile:   certificatePath,
+		RedisTLSKeyFile:    keyPath,
+		RedisTLSServerName: "redis.internal",
+	})
+
+	if assert.NoError(t, err, "Options should be built") && assert.NotNil(t, options.TLSConfig, "TLS should be enabled") {
+		assert.NotNil(t, options.TLSConfig.RootCAs, "CA file should verify the server")
+		assert.Len(t, options.TLSConfig.Certificates, 1, "Client certificate should be presented")
+		assert.Equal(t, "redis.internal", options.TLSConfig.ServerName, "Server name should be set")
+	}
+
+	_, err = sharedImplementations.NewRedisOptions(&sharedInfrastructure.EnvironmentSpec{
+		RedisTLSEnabled: true,
+		RedisTLSCaFile:  filepath.Join(t.TempDir(), "missing.crt"),
+	})
+	assert.Error(t, err, "Missing CA file should fail")
+}
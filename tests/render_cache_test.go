@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/implementations"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostPDFUrl_RenderCache tests requests with the same render inputs reuse the cached PDF but still upload their file
+func TestPostPDFUrl_RenderCache(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	renderCache := testUtilities.NewFakeRenderCache()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCloudStorage(cloudStorage),
+		testUtilities.WithRenderCache(renderCache),
+	)
+
+	for _, fileName := range []string{"first.pdf", "second.pdf"} {
+		result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName(fileName)))
+		assert.NoError(t, err, "Request should succeed")
+		if assert.NotNil(t, result.PageCount, "Page count should be known") {
+			assert.Equal(t, 1, *result.PageCount, "Page count should be cached along with the PDF")
+		}
+	}
+
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered once")
+	assert.Len(t, renderCache.Entries, 1, "Rendered PDF should be cached")
+	assert.Equal(t, []byte("%PDF-1.7"), cloudStorage.Files["reports/second.pdf"], "Cached PDF should be uploaded")
+}
+
+// TestPostPDFStream_RenderCache tests streamed PDFs are returned from the render cache
+func TestPostPDFStream_RenderCache(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	useCase := use_cases.GeneratePDFReturningStreamUseCase{
+		PDFGenerator:  generator,
+		Fingerprinter: fingerprint.RequestFingerprinter{HashGenerator: testUtilities.FakeHashGenerator{}},
+		RenderCache:   testUtilities.NewFakeRenderCache(),
+	}
+
+	for range 2 {
+		stream, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName("stream.pdf")))
+		if !assert.NoError(t, err, "Request should succeed") {
+			return
+		}
+
+		content, err := io.ReadAll(stream.Reader)
+		_ = stream.Reader.Close()
+		assert.NoError(t, err, "Stream should be readable")
+		assert.Equal(t, "%PDF-1.7", string(content), "Stream should return the PDF")
+		assert.Equal(t, int64(len(content)), stream.Size, "Size should match the content")
+	}
+
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered once")
+}
+
+// TestPostPDFUrl_RenderCacheRemoteItems tests the PDFs of requests with remote items are rendered every time
+func TestPostPDFUrl_RenderCacheRemoteItems(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	renderCache := testUtilities.NewFakeRenderCache()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithRenderCache(renderCache),
+	)
+
+	remoteURL := "https://example.com/report"
+	for _, fileName := range []string{"first.pdf", "second.pdf"} {
+		request := testUtilities.NewTestPDFRequest(
+			testUtilities.WithFileName(fileName),
+			testUtilities.WithItems(dto.PDFItem{BodyHTML: "<p>Report</p>"}, dto.PDFItem{URL: &remoteURL}),
+		)
+
+		_, err := useCase.Execute(context.Background(), request)
+		assert.NoError(t, err, "Request should succeed")
+	}
+
+	assert.Equal(t, 2, generator.Calls, "PDF with remote items should be rendered every time")
+	assert.Empty(t, renderCache.Entries, "PDF with remote items should not be cached")
+}
+
+// TestPostPDFStream_RenderCacheRemoteItems tests streamed PDFs with remote items are rendered every time
+func TestPostPDFStream_RenderCacheRemoteItems(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	renderCache := testUtilities.NewFakeRenderCache()
+	useCase := use_cases.GeneratePDFReturningStreamUseCase{
+		PDFGenerator:  generator,
+		Fingerprinter: fingerprint.RequestFingerprinter{HashGenerator: testUtilities.FakeHashGenerator{}},
+		RenderCache:   renderCache,
+	}
+
+	remoteURL := "https://example.com/report"
+	for range 2 {
+		request := testUtilities.NewTestPDFRequest(
+			testUtilities.WithFileName("stream.pdf"),
+			testUtilities.WithItems(dto.PDFItem{URL: &remoteURL}),
+		)
+
+		stream, err := useCase.Execute(context.Background(), request)
+		if assert.NoError(t, err, "Request should succeed") {
+			_ = stream.Reader.Close()
+		}
+	}
+
+	assert.Equal(t, 2, generator.Calls, "PDF with remote items should be rendered every time")
+	assert.Empty(t, renderCache.Entries, "PDF with remote items should not be cached")
+}
+
+// TestPostPDFStream_RenderCacheFailure tests the PDFs are generated when the render cache is unavailable
+func TestPostPDFStream_RenderCacheFailure(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	renderCache := testUtilities.NewFakeRenderCache()
+	renderCache.Error = errors.New("connection refused")
+	useCase := use_cases.GeneratePDFReturningStreamUseCase{
+		PDFGenerator:  generator,
+		Fingerprinter: fingerprint.RequestFingerprinter{HashGenerator: testUtilities.FakeHashGenerator{}},
+		RenderCache:   renderCache,
+	}
+
+	stream, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName("stream.pdf")))
+	if assert.NoError(t, err, "Request should succeed without the render cache") {
+		_ = stream.Reader.Close()
+	}
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered")
+}
+
+// TestDiskRenderCache tests PDFs are cached on disk up to the entry size and the least recently used ones are evicted
+func TestDiskRenderCache(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	cache, err := implementations.NewDiskRenderCache(rootPath, 20, 10, 0)
+	if !assert.NoError(t, err, "Cache should be created") {
+		return
+	}
+
+	missing, err := cache.Get(ctx, "missing")
+	assert.NoError(t, err, "Getting a missing PDF should succeed")
+	assert.Nil(t, missing, "Missing PDF should not be cached")
+
+	assert.NoError(t, cache.Set(ctx, "first", &dto.CachedPDF{Content: []byte("%PDF-first"), PageCount: 1}))
+	assert.NoError(t, cache.Set(ctx, "too-large", &dto.CachedPDF{Content: []byte("%PDF-too-large"), PageCount: 1}))
+
+	tooLarge, err := cache.Get(ctx, "too-large")
+	assert.NoError(t, err, "Getting a skipped PDF should succeed")
+	assert.Nil(t, tooLarge, "PDFs larger than the entry size should not be cached")
+
+	first, err := cache.Get(ctx, "first")
+	assert.NoError(t, err, "Getting a cached PDF should succeed")
+	if assert.NotNil(t, first, "PDF should be cached") {
+		assert.Equal(t, "%PDF-first", string(first.Content), "Cached content should match")
+		assert.Equal(t, 1, first.PageCount, "Cached page count should match")
+	}
+
+	// The second PDF fills the cache, and the third evicts the least recently used one
+	assert.NoError(t, cache.Set(ctx, "second", &dto.CachedPDF{Content: []byte("%PDF-secon"), PageCount: 2}))
+	_, _ = cache.Get(ctx, "first")
+	assert.NoError(t, cache.Set(ctx, "third", &dto.CachedPDF{Content: []byte("%PDF-third"), PageCount: 3}))
+
+	second, err := cache.Get(ctx, "second")
+	assert.NoError(t, err, "Getting an evicted PDF should succeed")
+	assert.Nil(t, second, "Least recently used PDF should be evicted")
+
+	files, err := os.ReadDir(rootPath)
+	assert.NoError(t, err, "Cache directory should be readable")
+	assert.Len(t, files, 2, "Evicted PDF should be removed from disk")
+
+	// A new cache indexes the PDFs left by the previous one
+	reloaded, err := implementations.NewDiskRenderCache(rootPath, 20, 10, 0)
+	if !assert.NoError(t, err, "Cache should be reloaded") {
+		return
+	}
+	third, err := reloaded.Get(ctx, "third")
+	assert.NoError(t, err, "Getting a reloaded PDF should succeed")
+	if assert.NotNil(t, third, "PDF should survive the reload") {
+		assert.Equal(t, 3, third.PageCount, "Page count should survive the reload")
+	}
+}
+
+// TestDiskRenderCache_Expiration tests expired PDFs are not returned and are removed from disk
+func TestDiskRenderCache_Expiration(t *testing.T) {
+	ctx := context.Background()
+	rootPath := t.TempDir()
+	cache, err := implementations.NewDiskRenderCache(rootPath, 1024, 1024, time.Nanosecond)
+	if !assert.NoError(t, err, "Cache should be created") {
+		return
+	}
+
+	assert.NoError(t, cache.Set(ctx, "expiring", &dto.CachedPDF{Content: []byte("%PDF-1.7"), PageCount: 1}))
+	time.Sleep(time.Millisecond)
+
+	expired, err := cache.Get(ctx, "expiring")
+	assert.NoError(t, err, "Getting an expired PDF should succeed")
+	assert.Nil(t, expired, "Expired PDF should not be returned")
+
+	files, err := os.ReadDir(rootPath)
+	assert.NoError(t, err, "Cache directory should be readable")
+	assert.Empty(t, files, "Expired PDF should be removed from disk")
+}
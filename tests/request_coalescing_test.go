@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/infrastructure/implementations"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedErrors "github.com/PChaparro/serpentarius/internal/modules/shared/domain/errors"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// coalescedExecution is the outcome of one of the concurrent executions of a use case
+type coalescedExecution struct {
+	result *dto.PDFURL
+	err    error
+}
+
+// executeConcurrently runs the use case the given times at once with the same request, releasing the generator
+// once the first execution is rendering and the rest had time to wait for it
+func executeConcurrently(
+	t *testing.T,
+	useCase *use_cases.GeneratePDFReturningURLUseCase,
+	generator *testUtilities.FakePDFGenerator,
+	times int,
+) []coalescedExecution {
+	executions := make([]coalescedExecution, times)
+	var waitGroup sync.WaitGroup
+	for idx := range times {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest(testUtilities.WithFileName("coalesced.pdf")))
+			executions[idx] = coalescedExecution{result: result, err: err}
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return generator.GetCalls() > 0
+	}, time.Second, time.Millisecond, "PDF should start rendering")
+	time.Sleep(50 * time.Millisecond)
+	close(generator.Block)
+	waitGroup.Wait()
+
+	return executions
+}
+
+// TestPostPDFUrl_CoalescedRequests tests identical concurrent requests render the PDF once and get the same URL
+func TestPostPDFUrl_CoalescedRequests(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7"), Block: make(chan struct{})}
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCoalescer(implementations.NewRequestCoalescer(nil, time.Second, time.Millisecond)),
+	)
+
+	executions := executeConcurrently(t, useCase, generator, 5)
+
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered once")
+	for _, execution := range executions {
+		if assert.NoError(t, execution.err, "Every request should succeed") {
+			assert.Equal(t, executions[0].result.URL, execution.result.URL, "Every request should get the same URL")
+		}
+	}
+}
+
+// TestPostPDFUrl_CoalescedRequestsFailure tests the requests waiting for a failed render get its error
+func TestPostPDFUrl_CoalescedRequestsFailure(t *testing.T) {
+	code := "PDF_GENERATION_FAILED"
+	generator := &testUtilities.FakePDFGenerator{
+		Block: make(chan struct{}),
+		Error: sharedErrors.NewGenericDomainError(sharedErrors.CreateDomainErrorArguments{
+			Code:    &code,
+			Message: "Chromium crashed",
+		}),
+	}
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCoalescer(implementations.NewRequestCoalescer(nil, time.Second, time.Millisecond)),
+	)
+
+	executions := executeConcurrently(t, useCase, generator, 5)
+
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered once")
+	for _, execution := range executions {
+		var domainError sharedErrors.DomainError
+		if assert.ErrorAs(t, execution.err, &domainError, "Every request should fail") {
+			assert.Equal(t, code, domainError.Code(), "Every request should get the error of the render")
+		}
+	}
+}
+
+// TestRequestCoalescer_RemoteFailure tests the failure recorded by the replica holding the lock is returned
+// without running the function again
+func TestRequestCoalescer_RemoteFailure(t *testing.T) {
+	lockStorage := testUtilities.NewFakeLockStorage()
+	lockStorage.Held["report"] = true
+	coalescer := implementations.NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		lockStorage.Release("report", &sharedDefinitions.LockFailure{Code: "PDF_GENERATION_FAILED", Message: "Chromium crashed"})
+	})
+
+	calls := 0
+	_, _, err := coalescer.Do(context.Background(), "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		calls++
+		return &dto.PDFURL{}, nil
+	})
+
+	var domainError sharedErrors.DomainError
+	if assert.ErrorAs(t, err, &domainError, "Failure of the other replica should be returned") {
+		assert.Equal(t, "PDF_GENERATION_FAILED", domainError.Code(), "Error code should be kept")
+		assert.Equal(t, "Chromium crashed", domainError.Message(), "Error message should be kept")
+	}
+	assert.Zero(t, calls, "Function should not run again")
+	assert.False(t, lockStorage.Held["report"], "Lock should be released")
+}
+
+// TestRequestCoalescer_RemoteSuccess tests the function runs knowing it waited once the other replica succeeds,
+// and records its own failure for the next ones
+func TestRequestCoalescer_RemoteSuccess(t *testing.T) {
+	lockStorage := testUtilities.NewFakeLockStorage()
+	lockStorage.Held["report"] = true
+	coalescer := implementations.NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		lockStorage.Release("report", nil)
+	})
+
+	_, _, err := coalescer.Do(context.Background(), "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		assert.True(t, waited, "Function should know it waited for another replica")
+		return nil, errors.New("upload failed")
+	})
+
+	assert.EqualError(t, err, "upload failed", "Error of the function should be returned")
+	failure, _ := lockStorage.GetFailure(context.Background(), "report")
+	if assert.NotNil(t, failure, "Failure should be recorded for the other replicas") {
+		assert.Equal(t, "upload failed", failure.Message, "Failure message should be recorded")
+	}
+}
+
+// TestRequestCoalescer_LockStorageFailure tests the function runs without the lock when the lock storage is unavailable
+func TestRequestCoalescer_LockStorageFailure(t *testing.T) {
+	lockStorage := testUtilities.NewFakeLockStorage()
+	lockStorage.Error = errors.New("connection refused")
+	coalescer := implementations.NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)
+
+	result, shared, err := coalescer.Do(context.Background(), "report", func(ctx context.Context, waited bool) (*dto.PDFURL, error) {
+		assert.False(t, waited, "Function should not have waited")
+		return &dto.PDFURL{URL: "https://cdn.example.com/reports/report.pdf"}, nil
+	})
+
+	assert.NoError(t, err, "Request should succeed without the lock storage")
+	assert.False(t, shared, "Result should not be shared")
+	if assert.NotNil(t, result, "Result should be returned") {
+		assert.Equal(t, "https://cdn.example.com/reports/report.pdf", result.URL, "Result of the function should be returned")
+	}
+}
+
+// TestPostPDFUrl_CoalescedWaiterMetrics tests a request finding the PDF stored by the replica it waited for
+// records a single cache lookup
+func TestPostPDFUrl_CoalescedWaiterMetrics(t *testing.T) {
+	request := testUtilities.NewTestPDFRequest(testUtilities.WithFileName("coalesced.pdf"))
+	fingerprinter := fingerprint.RequestFingerprinter{HashGenerator: testUtilities.FakeHashGenerator{}}
+	hash, err := fingerprinter.Fingerprint(request)
+	assert.NoError(t, err, "Fingerprinting the request should succeed")
+
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	cloudStorage := testUtilities.NewFakeCloudStorage()
+	cloudStorage.Files["reports/coalesced.pdf"] = []byte("%PDF-1.7")
+	cacheStorage := testUtilities.NewFakeURLCacheStorage()
+	metrics := testUtilities.NewFakeMetricsRecorder()
+	lockStorage := testUtilities.NewFakeLockStorage()
+	lockStorage.Held[hash] = true
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithCloudStorage(cloudStorage),
+		testUtilities.WithURLCacheStorage(cacheStorage),
+		testUtilities.WithFingerprinter(fingerprinter),
+		testUtilities.WithMetricsRecorder(metrics),
+		testUtilities.WithCoalescer(implementations.NewRequestCoalescer(lockStorage, time.Second, time.Millisecond)),
+	)
+
+	// The other replica caches the URL of the PDF it stored before releasing the lock
+	time.AfterFunc(20*time.Millisecond, func() {
+		_ = cacheStorage.Set(context.Background(), sharedDefinitions.SetURLCacheRequest{
+			Key:   hash,
+			Value: "https://cdn.example.com/reports/coalesced.pdf",
+		})
+		lockStorage.Release(hash, nil)
+	})
+
+	result, err := useCase.Execute(context.Background(), request)
+
+	if assert.NoError(t, err, "Request should succeed") {
+		assert.True(t, result.CacheHit, "URL stored by the other replica should be returned")
+	}
+	assert.Zero(t, generator.GetCalls(), "PDF should not be rendered again")
+	assert.Equal(t, map[string]int{sharedDefinitions.CACHE_LOOKUP_MISS: 1}, metrics.CacheLookups, "Only the first lookup should be recorded")
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	sharedInfrastructure "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure"
+	sharedHTTP "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/http"
+	testConstants "github.com/PChaparro/serpentarius/tests/constants"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTemplates_Lifecycle tests templates can be versioned, rendered and deleted
+func TestTemplates_Lifecycle(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	templateID := "test-invoice-" + sharedInfrastructure.GenerateXID()
+	templateURL := testConstants.TEMPLATES_ENDPOINT + "/" + templateID
+
+	// Create two versions
+	for version := 1; version <= 2; version++ {
+		w := testUtilities.SendToAPI(testUtilities.APIRequest{
+			Router: router,
+			Method: http.MethodPut,
+			URL:    templateURL,
+			Body:   `{"content": "<!DOCTYPE html><html><body><h1>Invoice {{.number}}</h1></body></html>"}`,
+		})
+		require.Equalf(t, http.StatusCreated, w.Code, "Should return 201 when creating version %d (got %d)", version, w.Code)
+
+		respAny, err := testUtilities.ParseJSONResponse(w)
+		require.NoError(t, err, "Response should be valid JSON")
+
+		resp, ok := respAny.(map[string]any)
+		require.Truef(t, ok, "Response should be a JSON object (got: %T)", respAny)
+
+		template, ok := resp["template"].(map[string]any)
+		require.Truef(t, ok, "Response should contain a 'template' object (got: %v)", resp)
+		assert.EqualValuesf(t, version, template["version"], "Template version should be %d (got: %v)", version, template["version"])
+	}
+
+	// Get a specific version
+	w := testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    templateURL + "?version=1",
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 for an existing version (got %d)", w.Code)
+
+	// Render the template
+	w = testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   `{"items": [{"template": "` + templateID + `", "data": {"number": 42}}], "config": {"fileName": "invoice.pdf"}}`,
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 when rendering a template (got %d)", w.Code)
+
+	// Rendering with missing data fails
+	w = testUtilities.PostToAPI(testUtilities.PostAPIRequest{
+		Router: router,
+		URL:    testConstants.GENERATE_PDF_RETURNING_STREAM_ENDPOINT,
+		Body:   `{"items": [{"template": "` + templateID + `", "data": {}}], "config": {"fileName": "invoice.pdf"}}`,
+	})
+	assert.Equalf(t, http.StatusUnprocessableEntity, w.Code, "Should return 422 when data is missing (got %d)", w.Code)
+
+	// Delete the template
+	w = testUtilities.SendToAPI(testUtilities.APIRequest{
+		Router: router,
+		Method: http.MethodDelete,
+		URL:    templateURL,
+	})
+	assert.Equalf(t, http.StatusOK, w.Code, "Should return 200 when deleting (got %d)", w.Code)
+
+	w = testUtilities.GetFromAPI(testUtilities.GetAPIRequest{
+		Router: router,
+		URL:    templateURL,
+	})
+	assert.Equalf(t, http.StatusNotFound, w.Code, "Should return 404 after deleting (got %d)", w.Code)
+}
+
+// TestTemplates_InvalidContent tests templates that cannot be parsed are refused
+func TestTemplates_InvalidContent(t *testing.T) {
+	router := sharedHTTP.RegisterRoutes()
+
+	w := testUtilities.SendToAPI(testUtilities.APIRequest{
+		Router: router,
+		Method: http.MethodPut,
+		URL:    testConstants.TEMPLATES_ENDPOINT + "/broken",
+		Body:   `{"content": "<p>{{.number</p>"}`,
+	})
+
+	assert.Equalf(t, http.StatusUnprocessableEntity, w.Code, "Should return 422 for unparsable templates (got %d)", w.Code)
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+	testUtilities "github.com/PChaparro/serpentarius/tests/utilities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInMemoryCacheStorage tests the URLs are cached up to the max entries, evicting the least recently used ones
+func TestInMemoryCacheStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := sharedImplementations.NewInMemoryCacheStorage(2)
+	report := sharedDefinitions.CachedFile{FileFolder: "reports", FilePath: "report.pdf"}
+	invoice := sharedDefinitions.CachedFile{FileFolder: "reports", FilePath: "invoice.pdf"}
+
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "first", Value: "https://cdn.example.com/first", File: report}))
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "second", Value: "https://cdn.example.com/second", File: invoice}))
+
+	// Reading the first URL makes the second one the least recently used
+	first, err := storage.Get(ctx, "first")
+	assert.NoError(t, err, "Getting a cached URL should succeed")
+	if assert.NotNil(t, first, "URL should be cached") {
+		assert.Equal(t, "https://cdn.example.com/first", *first, "Cached URL should match")
+	}
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "third", Value: "https://cdn.example.com/third", File: report}))
+
+	second, err := storage.Get(ctx, "second")
+	assert.NoError(t, err, "Getting an evicted URL should succeed")
+	assert.Nil(t, second, "Least recently used URL should be evicted")
+
+	file, err := storage.GetFile(ctx, "third")
+	assert.NoError(t, err, "Getting the file of a cached URL should succeed")
+	assert.Equal(t, &report, file, "File of the URL should be cached")
+
+	deleted, err := storage.DeleteByFile(ctx, report)
+	assert.NoError(t, err, "Deleting the URLs of a file should succeed")
+	assert.Equal(t, 2, deleted, "Every URL pointing to the file should be deleted")
+
+	first, _ = storage.Get(ctx, "first")
+	assert.Nil(t, first, "URLs of the deleted file should not be cached")
+}
+
+// TestInMemoryCacheStorage_Expiration tests the expired URLs are not returned
+func TestInMemoryCacheStorage_Expiration(t *testing.T) {
+	ctx := context.Background()
+	storage := sharedImplementations.NewInMemoryCacheStorage(10)
+
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "expiring", Value: "https://cdn.example.com/expiring", Expiration: 1}))
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "forever", Value: "https://cdn.example.com/forever"}))
+	time.Sleep(1100 * time.Millisecond)
+
+	expiring, err := storage.Get(ctx, "expiring")
+	assert.NoError(t, err, "Getting an expired URL should succeed")
+	assert.Nil(t, expiring, "Expired URL should not be returned")
+
+	forever, err := storage.Get(ctx, "forever")
+	assert.NoError(t, err, "Getting a URL without expiration should succeed")
+	assert.NotNil(t, forever, "URL without expiration should be kept")
+}
+
+// TestTieredCacheStorage tests the URLs are kept in both tiers, with a shorter expiration in the local one,
+// and that the remote URLs are still read when the local tier misses
+func TestTieredCacheStorage(t *testing.T) {
+	ctx := context.Background()
+	local := testUtilities.NewFakeURLCacheStorage()
+	remote := testUtilities.NewFakeURLCacheStorage()
+	storage := sharedImplementations.NewTieredCacheStorage(local, remote, 60)
+
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "report", Value: "https://cdn.example.com/report", Expiration: 3600}))
+	assert.Equal(t, int64(60), local.Expirations["report"], "Local URL should expire sooner")
+	assert.Equal(t, int64(3600), remote.Expirations["report"], "Remote URL should keep its expiration")
+
+	// Another replica cached the URL, so only the remote tier has it
+	remote.Entries["invoice"] = "https://cdn.example.com/invoice"
+	invoice, err := storage.Get(ctx, "invoice")
+	assert.NoError(t, err, "Getting a remote URL should succeed")
+	if assert.NotNil(t, invoice, "Remote URL should be returned") {
+		assert.Equal(t, "https://cdn.example.com/invoice", *invoice, "Remote URL should match")
+	}
+}
+
+// TestTieredCacheStorage_RemoteFailure tests the local URLs are still served when the remote tier is unavailable,
+// and the rest are misses
+func TestTieredCacheStorage_RemoteFailure(t *testing.T) {
+	ctx := context.Background()
+	remote := testUtilities.NewFakeURLCacheStorage()
+	remote.Error = errors.New("connection refused")
+	storage := sharedImplementations.NewTieredCacheStorage(sharedImplementations.NewInMemoryCacheStorage(10), remote, 60)
+
+	assert.NoError(t, storage.Set(ctx, sharedDefinitions.SetURLCacheRequest{Key: "report", Value: "https://cdn.example.com/report"}), "Setting a URL should succeed without the remote tier")
+
+	report, err := storage.Get(ctx, "report")
+	assert.NoError(t, err, "Getting a local URL should succeed")
+	assert.NotNil(t, report, "Local URL should be served")
+
+	missing, err := storage.Get(ctx, "missing")
+	assert.NoError(t, err, "Remote failures should be misses")
+	assert.Nil(t, missing, "Missing URL should not be returned")
+
+	assert.NoError(t, storage.Delete(ctx, "report"), "Deleting a URL should succeed without the remote tier")
+}
+
+// TestPostPDFUrl_URLCacheFailure tests the PDFs are generated when the URL cache is unavailable
+func TestPostPDFUrl_URLCacheFailure(t *testing.T) {
+	generator := &testUtilities.FakePDFGenerator{Content: []byte("%PDF-1.7")}
+	cacheStorage := testUtilities.NewFakeURLCacheStorage()
+	cacheStorage.Error = errors.New("connection refused")
+	metricsRecorder := testUtilities.NewFakeMetricsRecorder()
+	useCase := testUtilities.NewTestURLUseCase(
+		testUtilities.WithPDFGenerator(generator),
+		testUtilities.WithURLCacheStorage(cacheStorage),
+		testUtilities.WithMetricsRecorder(metricsRecorder),
+	)
+
+	result, err := useCase.Execute(context.Background(), testUtilities.NewTestPDFRequest())
+	if assert.NoError(t, err, "Request should succeed without the URL cache") {
+		assert.False(t, result.CacheHit, "Request should be a cache miss")
+		assert.Equal(t, "https://cdn.example.com/reports/report.pdf", result.URL, "URL of the stored PDF should be returned")
+	}
+	assert.Equal(t, 1, generator.Calls, "PDF should be rendered")
+	assert.Equal(t, 1, metricsRecorder.CacheLookups[sharedDefinitions.CACHE_LOOKUP_MISS], "Lookup should be recorded as a miss")
+}
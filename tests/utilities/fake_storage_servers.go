@@ -0,0 +1,381 @@
+package utilities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FakeStoredObject is an object stored by the fake storage servers
+type FakeStoredObject struct {
+	Content     []byte
+	ContentType string
+	Headers     http.Header    // Headers of the upload request, to check the object options
+	Resource    map[string]any // Object resource of the Cloud Storage uploads and rewrites, to check the object options
+}
+
+// FakeStorageServer is an in-process server storing the objects in memory, indexed by "{bucket}/{name}"
+type FakeStorageServer struct {
+	Server               *httptest.Server
+	MultipartUploadParts []int // Parts of every completed multipart upload, only counted by the fake S3 server
+	RewriteRequests      int   // Requests of every rewrite, only counted by the fake Cloud Storage server
+	mutex                sync.Mutex
+	objects              map[string]FakeStoredObject
+}
+
+// Object returns the stored object, if any
+func (s *FakeStorageServer) Object(bucket string, name string) (FakeStoredObject, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	object, exists := s.objects[bucket+"/"+name]
+	return object, exists
+}
+
+// Close shuts down the server
+func (s *FakeStorageServer) Close() {
+	s.Server.Close()
+}
+
+// remove deletes the object, returning whether it existed
+func (s *FakeStorageServer) remove(bucket string, name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, exists := s.objects[bucket+"/"+name]
+	delete(s.objects, bucket+"/"+name)
+	return exists
+}
+
+// copy duplicates the object, returning whether the source existed
+func (s *FakeStorageServer) copy(sourceBucket string, sourceName string, bucket string, name string, object FakeStoredObject) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	source, exists := s.objects[sourceBucket+"/"+sourceName]
+	if !exists {
+		return false
+	}
+
+	object.Content = source.Content
+	s.objects[bucket+"/"+name] = object
+	return true
+}
+
+// store saves the object
+func (s *FakeStorageServer) store(bucket string, name string, object FakeStoredObject) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.objects[bucket+"/"+name] = object
+}
+
+// NewFakeGCSServer starts a server implementing the multipart upload, object metadata, object rewrite and object deletion
+// endpoints of the Cloud Storage JSON API, as fake-gcs-server does. Every rewrite takes two requests, as large ones do.
+func NewFakeGCSServer() *FakeStorageServer {
+	fake := &FakeStorageServer{objects: make(map[string]FakeStoredObject)}
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.EscapedPath()
+
+		// POST /upload/storage/v1/b/{bucket}/o?uploadType=multipart
+		if rest, ok := strings.CutPrefix(path, "/upload/storage/v1/b/"); ok && r.Method == http.MethodPost {
+			bucket, _ := url.PathUnescape(strings.TrimSuffix(rest, "/o"))
+			resource, content, contentType, err := readFakeGCSMultipartUpload(r)
+			if r.URL.Query().Get("uploadType") != "multipart" || err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"error": map[string]any{"code": http.StatusBadRequest, "message": "Invalid multipart upload"},
+				})
+				return
+			}
+
+			name, _ := resource["name"].(string)
+			fake.store(bucket, name, FakeStoredObject{Content: content, ContentType: contentType, Headers: r.Header, Resource: resource})
+			_ = json.NewEncoder(w).Encode(map[string]any{"bucket": bucket, "name": name})
+			return
+		}
+
+		// POST /storage/v1/b/{bucket}/o/{object}/rewriteTo/b/{bucket}/o/{object}
+		if rest, ok := strings.CutPrefix(path, "/storage/v1/b/"); ok && r.Method == http.MethodPost {
+			fake.mutex.Lock()
+			fake.RewriteRequests++
+			fake.mutex.Unlock()
+
+			source, destination, _ := strings.Cut(rest, "/rewriteTo/b/")
+			escapedSourceBucket, escapedSourceName, _ := strings.Cut(source, "/o/")
+			escapedBucket, escapedName, _ := strings.Cut(destination, "/o/")
+			sourceBucket, _ := url.PathUnescape(escapedSourceBucket)
+			sourceName, _ := url.PathUnescape(escapedSourceName)
+			bucket, _ := url.PathUnescape(escapedBucket)
+			name, _ := url.PathUnescape(escapedName)
+
+			// Ask for a second request to finish the rewrite
+			if r.URL.Query().Get("rewriteToken") != "fake-rewrite-token" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"done": false, "rewriteToken": "fake-rewrite-token"})
+				return
+			}
+
+			var resource map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&resource)
+			contentType, _ := resource["contentType"].(string)
+
+			if !fake.copy(sourceBucket, sourceName, bucket, name, FakeStoredObject{ContentType: contentType, Headers: r.Header, Resource: resource}) {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"error": map[string]any{"code": http.StatusNotFound, "message": "No such object"},
+				})
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"done": true, "resource": map[string]any{"bucket": bucket, "name": name}})
+			return
+		}
+
+		// DELETE /storage/v1/b/{bucket}/o/{object}
+		if rest, ok := strings.CutPrefix(path, "/storage/v1/b/"); ok && r.Method == http.MethodDelete {
+			escapedBucket, escapedName, _ := strings.Cut(rest, "/o/")
+			bucket, _ := url.PathUnescape(escapedBucket)
+			name, _ := url.PathUnescape(escapedName)
+
+			if !fake.remove(bucket, name) {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"error": map[string]any{"code": http.StatusNotFound, "message": "No such object"},
+				})
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// GET /storage/v1/b/{bucket}/o/{object}
+		if rest, ok := strings.CutPrefix(path, "/storage/v1/b/"); ok && r.Method == http.MethodGet {
+			escapedBucket, escapedName, _ := strings.Cut(rest, "/o/")
+			bucket, _ := url.PathUnescape(escapedBucket)
+			name, _ := url.PathUnescape(escapedName)
+
+			if _, exists := fake.Object(bucket, name); !exists {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"error": map[string]any{"code": http.StatusNotFound, "message": "No such object"},
+				})
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"bucket": bucket, "name": name})
+			return
+		}
+
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+
+	return fake
+}
+
+// readFakeGCSMultipartUpload reads the object resource and the content of a multipart upload
+func readFakeGCSMultipartUpload(r *http.Request) (map[string]any, []byte, string, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if mediaType != "multipart/related" {
+		return nil, nil, "", fmt.Errorf("unexpected media type %q", mediaType)
+	}
+
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	resourcePart, err := reader.NextPart()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	var resource map[string]any
+	if err := json.NewDecoder(resourcePart).Decode(&resource); err != nil {
+		return nil, nil, "", err
+	}
+
+	contentPart, err := reader.NextPart()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	content, err := io.ReadAll(contentPart)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return resource, content, contentPart.Header.Get("Content-Type"), nil
+}
+
+// NewFakeS3Server starts a server implementing the path-style PutObject, CopyObject, HeadObject, DeleteObject and multipart upload
+// operations of S3, as MinIO does. The parts of each multipart upload are counted in MultipartUploadParts.
+func NewFakeS3Server() *FakeStorageServer {
+	fake := &FakeStorageServer{objects: make(map[string]FakeStoredObject)}
+	uploads := make(map[string]*fakeMultipartUpload)
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /{bucket}/{key}
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		query := r.URL.Query()
+
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			uploadID := strconv.Itoa(len(uploads) + 1)
+			fake.mutex.Lock()
+			uploads[uploadID] = &fakeMultipartUpload{headers: r.Header, parts: make(map[int][]byte)}
+			fake.mutex.Unlock()
+
+			_, _ = fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", bucket, key, uploadID)
+		case r.Method == http.MethodPut && query.Has("uploadId"):
+			partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+			content, _ := io.ReadAll(r.Body)
+			fake.mutex.Lock()
+			uploads[query.Get("uploadId")].parts[partNumber] = content
+			fake.mutex.Unlock()
+
+			w.Header().Set("ETag", fmt.Sprintf(`"part-%d"`, partNumber))
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			fake.mutex.Lock()
+			upload := uploads[query.Get("uploadId")]
+			var content []byte
+			for partNumber := 1; partNumber <= len(upload.parts); partNumber++ {
+				content = append(content, upload.parts[partNumber]...)
+			}
+			fake.MultipartUploadParts = append(fake.MultipartUploadParts, len(upload.parts))
+			fake.mutex.Unlock()
+
+			fake.store(bucket, key, FakeStoredObject{Content: content, ContentType: upload.headers.Get("Content-Type"), Headers: upload.headers})
+			_, _ = fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key></CompleteMultipartUploadResult>", bucket, key)
+		case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+			// The copy source is "{bucket}/{key}", URL-encoded
+			source, _ := url.PathUnescape(strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"))
+			sourceBucket, sourceKey, _ := strings.Cut(source, "/")
+			if !fake.copy(sourceBucket, sourceKey, bucket, key, FakeStoredObject{ContentType: r.Header.Get("Content-Type"), Headers: r.Header}) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+				return
+			}
+
+			_, _ = fmt.Fprint(w, `<CopyObjectResult><ETag>"copy"</ETag></CopyObjectResult>`)
+		case r.Method == http.MethodPut:
+			content, _ := io.ReadAll(r.Body)
+			fake.store(bucket, key, FakeStoredObject{Content: content, ContentType: r.Header.Get("Content-Type"), Headers: r.Header})
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodDelete:
+			fake.remove(bucket, key)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodHead:
+			object, exists := fake.Object(bucket, key)
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", object.ContentType)
+			w.Header().Set("Content-Length", strconv.Itoa(len(object.Content)))
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+
+	return fake
+}
+
+// fakeMultipartUpload is a multipart upload in progress in the fake S3 server
+type fakeMultipartUpload struct {
+	headers http.Header
+	parts   map[int][]byte
+}
+
+// FAKE_AZURE_ACCOUNT_NAME is the storage account served by the fake Azure Blob server
+const FAKE_AZURE_ACCOUNT_NAME = "devstoreaccount1"
+
+// NewFakeAzureBlobServer starts a server implementing the Put Blob, Copy Blob, Set Blob Properties, Get Blob Properties
+// and Delete Blob operations of the Blob service, as Azurite does. Copies finish right away.
+func NewFakeAzureBlobServer() *FakeStorageServer {
+	fake := &FakeStorageServer{objects: make(map[string]FakeStoredObject)}
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /{account}/{container}/{blob}
+		rest := strings.TrimPrefix(r.URL.Path, "/"+FAKE_AZURE_ACCOUNT_NAME+"/")
+		container, blob, _ := strings.Cut(rest, "/")
+
+		switch {
+		case r.Method == http.MethodPut && r.Header.Get("x-ms-copy-source") != "":
+			sourceURL, _ := url.Parse(r.Header.Get("x-ms-copy-source"))
+			sourceContainer, sourceBlob, _ := strings.Cut(strings.TrimPrefix(sourceURL.Path, "/"+FAKE_AZURE_ACCOUNT_NAME+"/"), "/")
+			if !fake.copy(sourceContainer, sourceBlob, container, blob, FakeStoredObject{Headers: r.Header}) {
+				w.Header().Set("x-ms-error-code", "CannotVerifyCopySource")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("x-ms-copy-id", "copy")
+			w.Header().Set("x-ms-copy-status", "success")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && r.URL.Query().Get("comp") == "properties":
+			object, exists := fake.Object(container, blob)
+			if !exists {
+				w.Header().Set("x-ms-error-code", "BlobNotFound")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			object.ContentType = r.Header.Get("x-ms-blob-content-type")
+			fake.store(container, blob, object)
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPut && r.URL.Query().Get("comp") == "":
+			content, _ := io.ReadAll(r.Body)
+			fake.store(container, blob, FakeStoredObject{
+				Content:     content,
+				ContentType: r.Header.Get("x-ms-blob-content-type"),
+				Headers:     r.Header,
+			})
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			if !fake.remove(container, blob) {
+				w.Header().Set("x-ms-error-code", "BlobNotFound")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodHead:
+			object, exists := fake.Object(container, blob)
+			if !exists {
+				w.Header().Set("x-ms-error-code", "BlobNotFound")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", object.ContentType)
+			w.Header().Set("Content-Length", strconv.Itoa(len(object.Content)))
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+
+	return fake
+}
+
+// FakeAzureConnectionString returns the connection string pointing to the fake Azure Blob server
+func (s *FakeStorageServer) FakeAzureConnectionString() string {
+	return fmt.Sprintf(
+		"DefaultEndpointsProtocol=http;AccountName=%s;AccountKey=%s;BlobEndpoint=%s/%s;",
+		FAKE_AZURE_ACCOUNT_NAME,
+		base64.StdEncoding.EncodeToString([]byte("fake-account-key")),
+		s.Server.URL,
+		FAKE_AZURE_ACCOUNT_NAME,
+	)
+}
@@ -0,0 +1,531 @@
+package utilities
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+)
+
+// FakePDFGenerator is a PDFGenerator returning a fixed document without launching a browser
+type FakePDFGenerator struct {
+	mutex     sync.Mutex
+	Content   []byte
+	ItemCache dto.ItemCacheStats // Reported as if the items were looked up in the item cache
+	// Error is returned instead of the document if set, to simulate a failed render
+	Error error
+	// Block holds the generation until it is closed, if set, to simulate a slow render
+	Block chan struct{}
+	Calls int
+}
+
+// GeneratePDF returns the fixed document
+func (g *FakePDFGenerator) GeneratePDF(ctx context.Context, request *dto.PDFGenerationDTO) (*dto.GeneratedPDF, error) {
+	g.mutex.Lock()
+	g.Calls++
+	g.mutex.Unlock()
+
+	if g.Block != nil {
+		<-g.Block
+	}
+	if g.Error != nil {
+		return nil, g.Error
+	}
+
+	return &dto.GeneratedPDF{
+		Reader:    io.NopCloser(bytes.NewReader(g.Content)),
+		Size:      int64(len(g.Content)),
+		PageCount: 1,
+		ItemCache: g.ItemCache,
+	}, nil
+}
+
+// GetCalls returns the number of generations started so far
+func (g *FakePDFGenerator) GetCalls() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.Calls
+}
+
+// FakeCloudStorage is an in-memory CloudStorage
+type FakeCloudStorage struct {
+	Files map[string][]byte
+	// MaxPresignExpiration shortens the presigned URLs lifetime as real backends do, unlimited if zero
+	MaxPresignExpiration time.Duration
+	// DeleteError is returned by DeleteFile if set, to simulate an unavailable backend
+	DeleteError error
+	// Copies are the copy requests received, in order
+	Copies []sharedDefinitions.CopyFileRequest
+}
+
+// NewFakeCloudStorage creates an empty FakeCloudStorage
+func NewFakeCloudStorage() *FakeCloudStorage {
+	return &FakeCloudStorage{Files: make(map[string][]byte)}
+}
+
+// UploadFile stores the file in memory
+func (s *FakeCloudStorage) UploadFile(ctx context.Context, request sharedDefinitions.UploadFileRequest) error {
+	content, err := io.ReadAll(request.FileReader)
+	if err != nil {
+		return err
+	}
+
+	s.Files[request.FileFolder+"/"+request.FilePath] = content
+	return nil
+}
+
+// FileExists checks if the file is stored in memory
+func (s *FakeCloudStorage) FileExists(ctx context.Context, request sharedDefinitions.FileExistsRequest) (bool, error) {
+	_, exists := s.Files[request.FileFolder+"/"+request.FilePath]
+	return exists, nil
+}
+
+// GetDownloadURL returns the public URL of the file, or a fake presigned URL expiring as requested
+func (s *FakeCloudStorage) GetDownloadURL(ctx context.Context, request sharedDefinitions.GetDownloadURLRequest) (*sharedDefinitions.DownloadURL, error) {
+	if request.Mode != sharedDefinitions.DOWNLOAD_URL_MODE_PRESIGNED {
+		return &sharedDefinitions.DownloadURL{
+			URL: request.PublicURLPrefix + "/" + request.FileFolder + "/" + request.FilePath,
+		}, nil
+	}
+
+	expiration := request.Expiration
+	if s.MaxPresignExpiration > 0 {
+		expiration = min(expiration, s.MaxPresignExpiration)
+	}
+	expiresAt := time.Now().Add(expiration)
+
+	return &sharedDefinitions.DownloadURL{
+		URL:       "https://signed.example.com/" + request.FileFolder + "/" + request.FilePath + "?signature=fake",
+		ExpiresAt: &expiresAt,
+	}, nil
+}
+
+// CopyFile copies the file in memory, failing if the source does not exist
+func (s *FakeCloudStorage) CopyFile(ctx context.Context, request sharedDefinitions.CopyFileRequest) error {
+	content, exists := s.Files[request.SourceFileFolder+"/"+request.SourceFilePath]
+	if !exists {
+		return fmt.Errorf("source file %s/%s does not exist", request.SourceFileFolder, request.SourceFilePath)
+	}
+
+	s.Files[request.FileFolder+"/"+request.FilePath] = content
+	s.Copies = append(s.Copies, request)
+	return nil
+}
+
+// DeleteFile removes the file from memory, or returns the DeleteError if set
+func (s *FakeCloudStorage) DeleteFile(ctx context.Context, request sharedDefinitions.DeleteFileRequest) error {
+	if s.DeleteError != nil {
+		return s.DeleteError
+	}
+
+	delete(s.Files, request.FileFolder+"/"+request.FilePath)
+	return nil
+}
+
+// FakeURLCacheStorage is an in-memory UrlCacheStorage recording, but not enforcing, the expirations
+type FakeURLCacheStorage struct {
+	mutex       sync.Mutex
+	Entries     map[string]string
+	Expirations map[string]int64
+	Files       map[string]sharedDefinitions.CachedFile
+	// Error is returned by every operation if set, to simulate an unavailable backend
+	Error error
+}
+
+// NewFakeURLCacheStorage creates an empty FakeURLCacheStorage
+func NewFakeURLCacheStorage() *FakeURLCacheStorage {
+	return &FakeURLCacheStorage{
+		Entries:     make(map[string]string),
+		Expirations: make(map[string]int64),
+		Files:       make(map[string]sharedDefinitions.CachedFile),
+	}
+}
+
+// Set stores the entry and its expiration
+func (c *FakeURLCacheStorage) Set(ctx context.Context, request sharedDefinitions.SetURLCacheRequest) error {
+	if c.Error != nil {
+		return c.Error
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Entries[request.Key] = request.Value
+	c.Expirations[request.Key] = request.Expiration
+	c.Files[request.Key] = request.File
+	return nil
+}
+
+// Get returns the entry, or nil if there is none
+func (c *FakeURLCacheStorage) Get(ctx context.Context, key string) (*string, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	value, exists := c.Entries[key]
+	if !exists {
+		return nil, nil
+	}
+
+	return &value, nil
+}
+
+// Delete removes the entry
+func (c *FakeURLCacheStorage) Delete(ctx context.Context, key string) error {
+	if c.Error != nil {
+		return c.Error
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.delete(key)
+	return nil
+}
+
+// GetFile returns the file of the entry, or nil if there is none
+func (c *FakeURLCacheStorage) GetFile(ctx context.Context, key string) (*sharedDefinitions.CachedFile, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	file, exists := c.Files[key]
+	if !exists {
+		return nil, nil
+	}
+
+	return &file, nil
+}
+
+// DeleteByFile removes the entries pointing to the file
+func (c *FakeURLCacheStorage) DeleteByFile(ctx context.Context, file sharedDefinitions.CachedFile) (int, error) {
+	if c.Error != nil {
+		return 0, c.Error
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	deleted := 0
+	for key, entryFile := range c.Files {
+		if entryFile == file {
+			c.delete(key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// delete removes the entry, the caller must hold the mutex
+func (c *FakeURLCacheStorage) delete(key string) {
+	delete(c.Entries, key)
+	delete(c.Expirations, key)
+	delete(c.Files, key)
+}
+
+// FakeRenderCache is an in-memory RenderCache without size limit, optionally failing every operation
+type FakeRenderCache struct {
+	Entries map[string]dto.CachedPDF
+	// Error is returned by every operation if set, to simulate an unavailable backend
+	Error error
+}
+
+// NewFakeRenderCache creates an empty FakeRenderCache
+func NewFakeRenderCache() *FakeRenderCache {
+	return &FakeRenderCache{Entries: make(map[string]dto.CachedPDF)}
+}
+
+// Get returns the cached PDF, or nil if there is none
+func (c *FakeRenderCache) Get(ctx context.Context, key string) (*dto.CachedPDF, error) {
+	if c.Error != nil {
+		return nil, c.Error
+	}
+
+	pdf, exists := c.Entries[key]
+	if !exists {
+		return nil, nil
+	}
+
+	return &pdf, nil
+}
+
+// Set stores the PDF
+func (c *FakeRenderCache) Set(ctx context.Context, key string, pdf *dto.CachedPDF) error {
+	if c.Error != nil {
+		return c.Error
+	}
+
+	c.Entries[key] = *pdf
+	return nil
+}
+
+// Delete removes the PDF
+func (c *FakeRenderCache) Delete(ctx context.Context, key string) error {
+	if c.Error != nil {
+		return c.Error
+	}
+
+	delete(c.Entries, key)
+	return nil
+}
+
+// MaxEntrySize returns the largest size there is
+func (c *FakeRenderCache) MaxEntrySize() int64 {
+	return math.MaxInt64
+}
+
+// FakeFileExpirationStorage is an in-memory FileExpirationStorage
+type FakeFileExpirationStorage struct {
+	mutex       sync.Mutex
+	Expirations map[sharedDefinitions.ExpiringFile]time.Time
+	// Error is returned by Schedule, Extend and Unschedule if set, to simulate an unavailable backend
+	Error error
+}
+
+// NewFakeFileExpirationStorage creates an empty FakeFileExpirationStorage
+func NewFakeFileExpirationStorage() *FakeFileExpirationStorage {
+	return &FakeFileExpirationStorage{Expirations: make(map[sharedDefinitions.ExpiringFile]time.Time)}
+}
+
+// Schedule records the expiration of the file
+func (s *FakeFileExpirationStorage) Schedule(ctx context.Context, file sharedDefinitions.ExpiringFile, expiresAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Error != nil {
+		return s.Error
+	}
+	s.Expirations[file] = expiresAt
+	return nil
+}
+
+// Extend records the expiration of the file, unless it expires later already
+func (s *FakeFileExpirationStorage) Extend(ctx context.Context, file sharedDefinitions.ExpiringFile, expiresAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Error != nil {
+		return s.Error
+	}
+	if current, exists := s.Expirations[file]; !exists || expiresAt.After(current) {
+		s.Expirations[file] = expiresAt
+	}
+	return nil
+}
+
+// Unschedule removes the expiration of the file
+func (s *FakeFileExpirationStorage) Unschedule(ctx context.Context, file sharedDefinitions.ExpiringFile) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Error != nil {
+		return s.Error
+	}
+	delete(s.Expirations, file)
+	return nil
+}
+
+// ListExpired returns up to limit files expired at the given time, in no particular order
+func (s *FakeFileExpirationStorage) ListExpired(ctx context.Context, now time.Time, limit int) ([]sharedDefinitions.ExpiringFile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var files []sharedDefinitions.ExpiringFile
+	for file, expiresAt := range s.Expirations {
+		if len(files) == limit {
+			break
+		}
+		if !expiresAt.After(now) {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// Claim removes the expiration of the file if it is still expired
+func (s *FakeFileExpirationStorage) Claim(ctx context.Context, file sharedDefinitions.ExpiringFile, now time.Time) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	expiresAt, exists := s.Expirations[file]
+	if !exists || expiresAt.After(now) {
+		return false, nil
+	}
+
+	delete(s.Expirations, file)
+	return true, nil
+}
+
+// FakeLockStorage is an in-memory LockStorage whose locks never expire
+type FakeLockStorage struct {
+	mutex    sync.Mutex
+	Held     map[string]bool
+	Failures map[string]sharedDefinitions.LockFailure
+	// Error is returned by Acquire if set, to simulate an unavailable backend
+	Error error
+}
+
+// NewFakeLockStorage creates a FakeLockStorage without locks
+func NewFakeLockStorage() *FakeLockStorage {
+	return &FakeLockStorage{
+		Held:     make(map[string]bool),
+		Failures: make(map[string]sharedDefinitions.LockFailure),
+	}
+}
+
+// Acquire takes the lock of the key, returning nil if it is held
+func (s *FakeLockStorage) Acquire(ctx context.Context, key string, lease time.Duration) (sharedDefinitions.Lock, error) {
+	if s.Error != nil {
+		return nil, s.Error
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Held[key] {
+		return nil, nil
+	}
+
+	s.Held[key] = true
+	return &fakeLock{storage: s, key: key}, nil
+}
+
+// GetFailure returns the failure recorded for the key, nil if there is none
+func (s *FakeLockStorage) GetFailure(ctx context.Context, key string) (*sharedDefinitions.LockFailure, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	failure, exists := s.Failures[key]
+	if !exists {
+		return nil, nil
+	}
+
+	return &failure, nil
+}
+
+// Release frees the lock of the key, recording the failure if any, as another replica would
+func (s *FakeLockStorage) Release(key string, failure *sharedDefinitions.LockFailure) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.Held, key)
+	if failure != nil {
+		s.Failures[key] = *failure
+	} else {
+		delete(s.Failures, key)
+	}
+}
+
+// fakeLock is a lock of the FakeLockStorage
+type fakeLock struct {
+	storage *FakeLockStorage
+	key     string
+}
+
+// Renew does nothing, as the locks never expire
+func (l *fakeLock) Renew(ctx context.Context, lease time.Duration) error {
+	return nil
+}
+
+// Release frees the lock
+func (l *fakeLock) Release(ctx context.Context, failure *sharedDefinitions.LockFailure) error {
+	l.storage.Release(l.key, failure)
+	return nil
+}
+
+// Abandon frees the lock, keeping the recorded failure
+func (l *fakeLock) Abandon(ctx context.Context) error {
+	l.storage.mutex.Lock()
+	defer l.storage.mutex.Unlock()
+
+	delete(l.storage.Held, l.key)
+	return nil
+}
+
+// FakeHashGenerator is a HashGenerator using the input as its own hash
+type FakeHashGenerator struct{}
+
+// GenerateHash returns the input unchanged
+func (g FakeHashGenerator) GenerateHash(input string) (string, error) {
+	return input, nil
+}
+
+// FakeMetricsRecorder is a MetricsRecorder counting the recorded events
+type FakeMetricsRecorder struct {
+	mutex        sync.Mutex
+	CacheLookups map[string]int
+	Uploads      int
+	UploadBytes  int64
+}
+
+// NewFakeMetricsRecorder creates an empty FakeMetricsRecorder
+func NewFakeMetricsRecorder() *FakeMetricsRecorder {
+	return &FakeMetricsRecorder{CacheLookups: make(map[string]int)}
+}
+
+// ObserveItemRender is ignored
+func (r *FakeMetricsRecorder) ObserveItemRender(duration time.Duration, outcome string) {}
+
+// ObserveMerge is ignored
+func (r *FakeMetricsRecorder) ObserveMerge(duration time.Duration) {}
+
+// ObserveUpload counts the upload and its bytes
+func (r *FakeMetricsRecorder) ObserveUpload(duration time.Duration, bytes int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Uploads++
+	r.UploadBytes += bytes
+}
+
+// IncCacheLookup counts the lookup by its result
+func (r *FakeMetricsRecorder) IncCacheLookup(result string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.CacheLookups[result]++
+}
+
+// ObservePoolWait is ignored
+func (r *FakeMetricsRecorder) ObservePoolWait(duration time.Duration) {}
+
+// IncBrowserLaunch is ignored
+func (r *FakeMetricsRecorder) IncBrowserLaunch() {}
+
+// IncBrowserClose is ignored
+func (r *FakeMetricsRecorder) IncBrowserClose() {}
+
+// ObserveHTTPRequest is ignored
+func (r *FakeMetricsRecorder) ObserveHTTPRequest(method string, route string, statusCode int, duration time.Duration) {
+}
+
+// FakePDFJobDispatcher is a PDFJobDispatcher recording the dispatched jobs without processing them
+type FakePDFJobDispatcher struct {
+	mutex  sync.Mutex
+	JobIDs []string
+	Error  error
+}
+
+// Dispatch records the job ID and returns the configured error
+func (d *FakePDFJobDispatcher) Dispatch(jobID string, request *dto.PDFGenerationDTO) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.JobIDs = append(d.JobIDs, jobID)
+	return d.Error
+}
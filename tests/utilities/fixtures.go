@@ -0,0 +1,172 @@
+package utilities
+
+import (
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/application/use_cases"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/definitions"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/dto"
+	"github.com/PChaparro/serpentarius/internal/modules/pdf/domain/fingerprint"
+	sharedDefinitions "github.com/PChaparro/serpentarius/internal/modules/shared/domain/definitions"
+	sharedImplementations "github.com/PChaparro/serpentarius/internal/modules/shared/infrastructure/implementations"
+)
+
+// TestPDFRequestOption overrides a field of the request returned by NewTestPDFRequest
+type TestPDFRequestOption func(request *dto.PDFGenerationDTO)
+
+// NewTestPDFRequest returns a request rendering an inline document to reports/report.pdf
+// under a public URL prefix, with the given overrides applied
+func NewTestPDFRequest(options ...TestPDFRequestOption) *dto.PDFGenerationDTO {
+	request := &dto.PDFGenerationDTO{
+		Items: []dto.PDFItem{{BodyHTML: "<p>Report</p>"}},
+		Config: dto.GeneralConfig{
+			Directory:       "reports",
+			FileName:        "report.pdf",
+			PublicURLPrefix: "https://cdn.example.com",
+		},
+	}
+	for _, option := range options {
+		option(request)
+	}
+
+	return request
+}
+
+// WithFileName stores the PDF under the given file name
+func WithFileName(fileName string) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Config.FileName = fileName
+	}
+}
+
+// WithBodyHTML renders a single inline item with the given content
+func WithBodyHTML(bodyHTML string) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Items = []dto.PDFItem{{BodyHTML: bodyHTML}}
+	}
+}
+
+// WithItems renders the given items
+func WithItems(items ...dto.PDFItem) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Items = items
+	}
+}
+
+// WithURLMode returns the URL in the given DOWNLOAD_URL_MODE_* mode
+func WithURLMode(urlMode string) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Config.URLMode = urlMode
+	}
+}
+
+// WithExpiration caches the URL, and signs it in presigned URL mode, for the given seconds
+func WithExpiration(seconds int64) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Config.Expiration = &seconds
+	}
+}
+
+// WithRetention keeps the file for the given seconds, forever if 0
+func WithRetention(seconds int64) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Config.Retention = &seconds
+	}
+}
+
+// WithDeduplication sets whether an identical PDF stored by another request is copied instead of rendered again
+func WithDeduplication(deduplicate bool) TestPDFRequestOption {
+	return func(request *dto.PDFGenerationDTO) {
+		request.Config.Deduplicate = deduplicate
+	}
+}
+
+// TestURLUseCaseOption overrides a dependency of the use case returned by NewTestURLUseCase
+type TestURLUseCaseOption func(useCase *use_cases.GeneratePDFReturningURLUseCase)
+
+// NewTestURLUseCase returns a URL use case with in-memory dependencies, rendering "%PDF-1.7" and
+// keeping the files forever, with the given overrides applied. The render cache and the coalescer are not set.
+func NewTestURLUseCase(options ...TestURLUseCaseOption) *use_cases.GeneratePDFReturningURLUseCase {
+	useCase := &use_cases.GeneratePDFReturningURLUseCase{
+		PDFGenerator:            &FakePDFGenerator{Content: []byte("%PDF-1.7")},
+		CloudStorage:            NewFakeCloudStorage(),
+		URLCacheStorage:         NewFakeURLCacheStorage(),
+		Fingerprinter:           fingerprint.RequestFingerprinter{HashGenerator: FakeHashGenerator{}},
+		MetricsRecorder:         NewFakeMetricsRecorder(),
+		FileExpirationStorage:   NewFakeFileExpirationStorage(),
+		DeduplicatedFileStorage: sharedImplementations.NewInMemoryDeduplicatedFileStorage(),
+	}
+	for _, option := range options {
+		option(useCase)
+	}
+
+	return useCase
+}
+
+// WithPDFGenerator renders the PDFs with the given generator
+func WithPDFGenerator(generator definitions.PDFGenerator) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.PDFGenerator = generator
+	}
+}
+
+// WithCloudStorage stores the PDFs in the given storage
+func WithCloudStorage(cloudStorage sharedDefinitions.CloudStorage) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.CloudStorage = cloudStorage
+	}
+}
+
+// WithURLCacheStorage caches the URLs in the given storage
+func WithURLCacheStorage(cacheStorage sharedDefinitions.UrlCacheStorage) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.URLCacheStorage = cacheStorage
+	}
+}
+
+// WithFingerprinter builds the cache keys with the given fingerprinter
+func WithFingerprinter(fingerprinter fingerprint.RequestFingerprinter) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.Fingerprinter = fingerprinter
+	}
+}
+
+// WithRenderCache caches the rendered PDFs in the given cache
+func WithRenderCache(renderCache definitions.RenderCache) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.RenderCache = renderCache
+	}
+}
+
+// WithMetricsRecorder records the metrics in the given recorder
+func WithMetricsRecorder(metricsRecorder sharedDefinitions.MetricsRecorder) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.MetricsRecorder = metricsRecorder
+	}
+}
+
+// WithFileExpirationStorage records the expirations of the files in the given storage
+func WithFileExpirationStorage(expirationStorage sharedDefinitions.FileExpirationStorage) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.FileExpirationStorage = expirationStorage
+	}
+}
+
+// WithDeduplicatedFileStorage records the copies of the deduplicated PDFs in the given storage
+func WithDeduplicatedFileStorage(copyStorage sharedDefinitions.DeduplicatedFileStorage) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.DeduplicatedFileStorage = copyStorage
+	}
+}
+
+// WithCoalescer coalesces the identical concurrent requests with the given coalescer
+func WithCoalescer(coalescer definitions.RequestCoalescer) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.Coalescer = coalescer
+	}
+}
+
+// WithDefaultRetention keeps the files for the given seconds when the request does not say
+func WithDefaultRetention(seconds int64) TestURLUseCaseOption {
+	return func(useCase *use_cases.GeneratePDFReturningURLUseCase) {
+		useCase.DefaultRetention = seconds
+	}
+}
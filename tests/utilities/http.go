@@ -49,6 +49,66 @@ func PostToAPI(req PostAPIRequest) *httptest.ResponseRecorder {
 	return w
 }
 
+type GetAPIRequest struct {
+	Router  http.Handler
+	URL     string
+	Auth    AuthOptions
+	Headers map[string]string
+}
+
+// GetFromAPI sends a GET request to the API with custom headers
+func GetFromAPI(req GetAPIRequest) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", req.URL, nil)
+
+	if !req.Auth.Skip {
+		token := req.Auth.Token
+		if token == "" {
+			token = sharedInfrastructure.GetEnvironment().AuthSecret
+		}
+		r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	for headerName, headerContent := range req.Headers {
+		if headerName == "Authorization" {
+			continue
+		}
+		r.Header.Set(headerName, headerContent)
+	}
+
+	req.Router.ServeHTTP(w, r)
+	return w
+}
+
+type APIRequest struct {
+	Router http.Handler
+	Method string
+	URL    string
+	Body   string
+	Auth   AuthOptions
+}
+
+// SendToAPI sends a request with any method and an optional JSON body to the API
+func SendToAPI(req APIRequest) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(req.Method, req.URL, strings.NewReader(req.Body))
+
+	if req.Body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	if !req.Auth.Skip {
+		token := req.Auth.Token
+		if token == "" {
+			token = sharedInfrastructure.GetEnvironment().AuthSecret
+		}
+		r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	req.Router.ServeHTTP(w, r)
+	return w
+}
+
 // ParseJSONResponse parses the response body as JSON and returns the result as an any and an error.
 func ParseJSONResponse(w *httptest.ResponseRecorder) (any, error) {
 	var resp any